@@ -2,30 +2,60 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	_ "github.com/jackc/pgx/v5/stdlib" // "pgx" database/sql driver for the audit log
 	"github.com/sirupsen/logrus"
 
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/domain/repository"
 	"github.com/project-exam/pkg/infrastructure/config"
 	"github.com/project-exam/pkg/infrastructure/ethereum"
+	"github.com/project-exam/pkg/infrastructure/jwks"
 	"github.com/project-exam/pkg/infrastructure/persistence"
+	"github.com/project-exam/pkg/infrastructure/secrets"
 	"github.com/project-exam/pkg/interface/api/handler"
+	"github.com/project-exam/pkg/interface/api/response"
 	"github.com/project-exam/pkg/interface/api/router"
+	"github.com/project-exam/pkg/interface/graphql"
+	"github.com/project-exam/pkg/interface/grpcapi"
 	"github.com/project-exam/pkg/interface/validator"
 	"github.com/project-exam/pkg/usecase"
 )
 
 func main() {
-	// Load configuration
-	cfg := config.LoadConfig()
+	configPath := flag.String("config", "", "path to a YAML or TOML config file (defaults to $CONFIG_FILE)")
+	printConfig := flag.Bool("print-config", false, "print the effective configuration with secrets redacted and exit")
+	flag.Parse()
+
+	// Load and validate configuration
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *printConfig {
+		if err := cfg.WriteRedacted(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Keep secrets such as API keys and RPC URL credentials out of logs and error responses
+	redactor := secrets.NewRedactor(cfg.SecretValues()...)
+	response.SetRedactor(redactor.Redact)
 
 	// Set up logger
-	logger := setupLogger(cfg.Log)
+	logger := setupLogger(cfg.Log, redactor)
 	logger.Info("Starting Ethereum Data API")
 
 	// Initialize Ethereum client
@@ -40,7 +70,7 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	blockNumber, err := ethClient.EthClient.BlockNumber(ctx)
+	blockNumber, err := ethClient.Eth().BlockNumber(ctx)
 	if err != nil {
 		logger.WithError(err).Fatal("Failed to connect to Ethereum node")
 	}
@@ -49,15 +79,107 @@ func main() {
 	// Initialize repository layer
 	ethereumRepo := persistence.NewEthereumRepository(ethClient)
 
+	rpcRepo := persistence.NewRPCRepository(ethClient)
+
+	abiRepo, err := persistence.NewABIRepository(cfg.ABI.RegistryDir)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load ABI registry")
+	}
+
 	// Initialize use case layer
 	ethereumUseCase := usecase.NewEthereumUseCase(ethereumRepo)
+	healthUseCase := usecase.NewHealthUseCase(ethereumRepo, usecase.HealthOptions{
+		ExpectedChainID: cfg.Health.ExpectedChainID,
+		MaxBlockAge:     cfg.Health.MaxBlockAge,
+	})
+
+	rpcUseCase := usecase.NewRPCUseCase(rpcRepo, usecase.RPCOptions{
+		AllowedMethods: cfg.RPC.AllowedMethods,
+		CacheTTL:       cfg.RPC.CacheTTL,
+	})
+
+	chainUseCase := usecase.NewChainUseCase(ethereumRepo)
+
+	logsUseCase := usecase.NewLogsUseCase(ethereumRepo, abiRepo, usecase.LogsOptions{
+		ChunkSize:     cfg.Logs.ChunkSize,
+		MaxBlockRange: cfg.Logs.MaxBlockRange,
+		MaxResults:    cfg.Logs.MaxResults,
+	})
+
+	trackerUseCase := usecase.NewTrackerUseCase(ethereumRepo, usecase.TrackerOptions{
+		Confirmations: cfg.Tracking.Confirmations,
+		Retention:     cfg.Tracking.Retention,
+		MaxTracked:    cfg.Tracking.MaxTracked,
+		MaxPerClient:  cfg.Tracking.MaxPerClient,
+	})
+	transactionUseCase := usecase.NewTransactionUseCase(ethereumRepo, abiRepo, trackerUseCase, func(tx *entity.SignedTransaction, err error) {
+		logger.WithError(err).WithField("tx_hash", tx.Hash).Warn("Failed to track sent transaction")
+	})
+	abiUseCase := usecase.NewABIUseCase(abiRepo)
 
 	// Initialize interface layer
 	ethereumValidator := validator.NewEthereumValidator()
 	ethereumHandler := handler.NewEthereumHandler(ethereumUseCase, ethereumValidator)
+	healthHandler := handler.NewHealthHandler(healthUseCase, cfg.Health.CheckTimeout)
+	rpcHandler := handler.NewRPCHandler(rpcUseCase, cfg.RPC.MaxBatchSize)
+
+	graphQLSchema, err := graphql.NewSchema(chainUseCase, graphql.Options{
+		MaxDepth:  cfg.GraphQL.MaxDepth,
+		MaxCost:   cfg.GraphQL.MaxCost,
+		MaxFields: cfg.GraphQL.MaxFields,
+		Logger:    logger,
+	})
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to build GraphQL schema")
+	}
+	graphQLHandler := handler.NewGraphQLHandler(graphQLSchema)
+	logsHandler := handler.NewLogsHandler(logsUseCase, ethereumValidator)
+	txHandler := handler.NewTransactionHandler(transactionUseCase, trackerUseCase, ethereumValidator)
+	abiHandler := handler.NewABIHandler(abiUseCase, ethereumValidator)
+
+	// Load JWKS for bearer token authentication if enabled
+	var jwtKeys *jwks.KeySet
+	if cfg.Server.Auth.Enabled && cfg.Server.Auth.JWT.Enabled {
+		jwtKeys, err = jwks.NewKeySet(cfg.Server.Auth.JWT.JWKS, cfg.Server.Auth.JWT.JWKSRefresh)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to load JWKS")
+		}
+	}
+
+	// Open audit log sinks if enabled
+	var auditRepo repository.AuditRepository
+	if cfg.Audit.Enabled {
+		auditRepo, err = newAuditRepository(cfg.Audit, logger)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to initialize audit log")
+		}
+		defer auditRepo.Close()
+	}
 
 	// Create router
-	router := router.NewRouter(cfg, ethereumHandler, logger)
+	router, err := router.NewRouter(cfg, ethereumHandler, healthHandler, rpcHandler, graphQLHandler, logsHandler, txHandler, abiHandler, jwtKeys, auditRepo, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to create router")
+	}
+
+	// Reload configuration on SIGHUP or config file changes
+	watcher := config.NewWatcher(cfg, cfg.Reload.WatchInterval)
+	watcher.Subscribe(func(newCfg *config.Config, changes []config.Change) {
+		redactor.Set(newCfg.SecretValues())
+		applyConfig(newCfg, changes, ethClient, router, logger)
+	})
+	watchCtx, stopWatching := context.WithCancel(context.Background())
+	defer stopWatching()
+	go watcher.Run(watchCtx, func(err error) {
+		logger.WithError(err).Error("Configuration reload rejected, keeping current configuration")
+	})
+
+	// Follow new blocks to update tracked transactions
+	trackCtx, stopTracking := context.WithCancel(context.Background())
+	defer stopTracking()
+	go trackerUseCase.Run(trackCtx, func(err error) {
+		logger.WithError(err).Warn("Failed to update tracked transactions")
+	})
 
 	// Start server in a goroutine
 	go func() {
@@ -67,6 +189,29 @@ func main() {
 		}
 	}()
 
+	// Serve the same API over gRPC, sharing the router's access policy
+	var grpcServer *grpcapi.Server
+	if cfg.Server.GRPCPort != "0" {
+		lis, err := net.Listen("tcp", ":"+cfg.Server.GRPCPort)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to listen for gRPC")
+		}
+
+		grpcServer = grpcapi.NewServer(ethereumUseCase, trackerUseCase, ethereumValidator, grpcapi.Options{
+			Access:         router.AccessControl(),
+			RequestTimeout: cfg.Server.RequestTimeout,
+			AuditRepo:      auditRepo,
+			Logger:         logger,
+		})
+
+		go func() {
+			logger.WithField("port", cfg.Server.GRPCPort).Info("gRPC server starting")
+			if err := grpcServer.Serve(lis); err != nil {
+				logger.WithError(err).Fatal("Failed to start gRPC server")
+			}
+		}()
+	}
+
 	// Wait for interrupt signal to gracefully shut down the server
 	quit := make(chan os.Signal, 1)
 	// Kill (no param) default sends syscall.SIGTERM
@@ -80,14 +225,101 @@ func main() {
 	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	// Finish pending gRPC calls, cancelling block streams at the deadline
+	if grpcServer != nil {
+		grpcServer.GracefulStop(ctx)
+	}
+
 	// Close resources
 	ethClient.Close()
 
 	logger.Info("Server exited properly")
 }
 
-// setupLogger configures the logger based on configuration
-func setupLogger(cfg config.LogConfig) *logrus.Logger {
+// applyConfig applies the reloadable settings of a reloaded configuration
+// and logs every changed setting
+func applyConfig(cfg *config.Config, changes []config.Change, ethClient *ethereum.Client, router *router.Router, logger *logrus.Logger) {
+	var reconnect, reconfigure bool
+	for _, change := range changes {
+		entry := logger.WithFields(logrus.Fields{
+			"setting": change.Setting,
+			"old":     change.Old,
+			"new":     change.New,
+		})
+		if change.Reloadable {
+			entry.Info("Configuration setting changed")
+		} else {
+			entry.Warn("Configuration setting changed, restart required to apply")
+		}
+
+		switch change.Setting {
+		case "ETHEREUM_RPC_URL", "ETHEREUM_FALLBACK_RPC_URLS":
+			reconnect = true
+		case "ETHEREUM_REQUEST_TIMEOUT", "ETHEREUM_RETRY_ATTEMPTS", "ETHEREUM_RETRY_DELAY":
+			reconfigure = true
+		}
+	}
+
+	// Dial new endpoints once per reload, applying the other Ethereum
+	// settings along with them
+	switch {
+	case reconnect:
+		if err := ethClient.Reconnect(&cfg.Ethereum); err != nil {
+			logger.WithError(err).Error("Failed to connect to new Ethereum node, keeping current connection")
+		}
+	case reconfigure:
+		ethClient.Configure(&cfg.Ethereum)
+	}
+
+	if level, err := logrus.ParseLevel(cfg.Log.Level); err == nil {
+		logger.SetLevel(level)
+	}
+
+	if err := router.ApplyConfig(cfg); err != nil {
+		logger.WithError(err).Error("Failed to apply reloaded configuration")
+	}
+}
+
+// newAuditRepository creates the audit sinks: the JSON lines file, plus a
+// database when a DSN is configured, written to in the background
+func newAuditRepository(cfg config.AuditConfig, logger *logrus.Logger) (repository.AuditRepository, error) {
+	fileRepo, err := persistence.NewFileAuditRepository(cfg.FilePath, cfg.MaxSize, cfg.MaxBackups)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := fileRepo
+	if cfg.DBDSN != "" {
+		dbRepo, err := persistence.NewSQLAuditRepository(cfg.DBDriver, cfg.DBDSN)
+		if err != nil {
+			fileRepo.Close()
+			return nil, err
+		}
+		repo = persistence.NewMultiAuditRepository(fileRepo, dbRepo)
+	}
+
+	return persistence.NewAsyncAuditRepository(repo, cfg.QueueSize, func(event *entity.AuditEvent, err error) {
+		logger.WithError(err).WithField("request_id", event.RequestID).Error("Failed to record audit event")
+	}), nil
+}
+
+// redactingFormatter removes secrets from formatted log entries
+type redactingFormatter struct {
+	logrus.Formatter
+	redactor *secrets.Redactor
+}
+
+// Format formats the entry and redacts known secrets
+func (f redactingFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	data, err := f.Formatter.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(f.redactor.Redact(string(data))), nil
+}
+
+// setupLogger configures the logger based on configuration, redacting secrets
+func setupLogger(cfg config.LogConfig, redactor *secrets.Redactor) *logrus.Logger {
 	logger := logrus.New()
 
 	// Set log level
@@ -97,17 +329,20 @@ func setupLogger(cfg config.LogConfig) *logrus.Logger {
 	}
 	logger.SetLevel(level)
 
-	// Configure formatter
+	// Configure formatter; HTML escaping is disabled so URL secrets are matched verbatim
+	var formatter logrus.Formatter
 	if cfg.Format == "json" {
-		logger.SetFormatter(&logrus.JSONFormatter{
-			TimestampFormat: time.RFC3339,
-		})
+		formatter = &logrus.JSONFormatter{
+			TimestampFormat:   time.RFC3339,
+			DisableHTMLEscape: true,
+		}
 	} else {
-		logger.SetFormatter(&logrus.TextFormatter{
+		formatter = &logrus.TextFormatter{
 			FullTimestamp:   true,
 			TimestampFormat: time.RFC3339,
-		})
+		}
 	}
+	logger.SetFormatter(redactingFormatter{Formatter: formatter, redactor: redactor})
 
 	// Configure output
 	switch cfg.OutputPath {
@@ -0,0 +1,45 @@
+package entity
+
+import "time"
+
+// DecodedEvent is an event log decoded with the ABI of its event
+type DecodedEvent struct {
+	Name      string
+	Signature string // canonical signature, e.g. Transfer(address,address,uint256)
+	Args      []DecodedArg
+}
+
+// DecodedArg is a decoded argument of an event or function call. Values are
+// JSON-friendly: integers are decimal strings, bytes and hashes 0x-prefixed
+// hex, arrays lists and tuples objects by component name.
+type DecodedArg struct {
+	Name    string
+	Type    string
+	Value   interface{}
+	Indexed bool // an indexed event argument; dynamic types only have their keccak256 hash
+}
+
+// ABISource tells where the ABI data was decoded with came from
+type ABISource string
+
+// ABI sources
+const (
+	ABISourceRegistry ABISource = "registry" // the ABI uploaded for the contract
+	ABISourceSelector ABISource = "selector" // a signature of the selector database, matched by selector alone
+)
+
+// DecodedCall is call input decoded with the ABI of the called function
+type DecodedCall struct {
+	Name      string
+	Signature string // canonical signature, e.g. transfer(address,uint256)
+	Selector  string // first 4 bytes of the input, 0x-prefixed hex
+	Args      []DecodedArg
+	Source    ABISource
+}
+
+// ContractABI is the ABI of a contract kept in the ABI registry
+type ContractABI struct {
+	Address   string
+	ABI       []byte // JSON ABI
+	UpdatedAt time.Time
+}
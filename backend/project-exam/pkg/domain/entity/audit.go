@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// AuditEvent records a single authenticated API request
+type AuditEvent struct {
+	Time      time.Time     `json:"time"`
+	RequestID string        `json:"requestId"`
+	UserID    string        `json:"userId"`
+	Method    string        `json:"method"`
+	Route     string        `json:"route"`
+	Path      string        `json:"path"`
+	Addresses []string      `json:"addresses,omitempty"` // addresses the request looked up
+	Status    int           `json:"status"`
+	Latency   time.Duration `json:"latencyNs"`
+	ClientIP  string        `json:"clientIp"`
+}
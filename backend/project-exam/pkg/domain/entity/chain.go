@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"math/big"
+	"time"
+)
+
+// Block represents a block with the hashes of its transactions
+type Block struct {
+	Number       uint64
+	Hash         string
+	ParentHash   string
+	Timestamp    time.Time
+	Miner        string
+	GasUsed      uint64
+	GasLimit     uint64
+	BaseFee      *big.Int // nil before the London fork
+	Transactions []string // transaction hashes, in block order
+}
+
+// Transaction represents a transaction. Block fields are unset while the
+// transaction is pending.
+type Transaction struct {
+	Hash                 string
+	Type                 uint64
+	From                 string
+	To                   string // empty for contract creation
+	Nonce                uint64
+	Value                *big.Int
+	Gas                  uint64
+	GasPrice             *big.Int
+	MaxFeePerGas         *big.Int // nil for legacy transactions
+	MaxPriorityFeePerGas *big.Int // nil for legacy transactions
+	Input                []byte
+	BlockHash            string
+	BlockNumber          *uint64
+	Index                *uint64
+}
+
+// Receipt represents the outcome of a mined transaction
+type Receipt struct {
+	TransactionHash   string
+	BlockHash         string
+	BlockNumber       uint64
+	Status            uint64 // 1 for success, 0 for failure
+	GasUsed           uint64
+	CumulativeGasUsed uint64
+	EffectiveGasPrice *big.Int
+	ContractAddress   string // set when the transaction created a contract
+	Logs              []Log
+}
+
+// Log represents an event emitted by a contract
+type Log struct {
+	Address          string
+	Topics           []string
+	Data             []byte
+	BlockNumber      uint64
+	BlockHash        string
+	TransactionHash  string
+	TransactionIndex uint64
+	Index            uint64
+	Removed          bool // the log was reverted by a chain reorganization
+}
+
+// ContractCall is a read-only call to a contract at the latest block
+type ContractCall struct {
+	To   string
+	Data []byte
+}
+
+// CallResult is the output of a ContractCall, or the error it failed with
+type CallResult struct {
+	Output []byte
+	Err    error
+}
+
+// Token describes an ERC-20 token. Fields the contract doesn't implement are empty.
+type Token struct {
+	Address  string
+	Name     string
+	Symbol   string
+	Decimals *uint8
+}
@@ -0,0 +1,31 @@
+package entity
+
+// ProxyType identifies the standard a proxy contract follows
+type ProxyType string
+
+// Proxy types
+const (
+	ProxyTypeTransparent ProxyType = "transparent" // EIP-1967 proxy with an admin
+	ProxyTypeUUPS        ProxyType = "uups"        // EIP-1967 proxy upgraded through its implementation (EIP-1822 compatible)
+	ProxyTypeEIP1967     ProxyType = "eip1967"     // EIP-1967 proxy of unknown upgrade scheme
+	ProxyTypeEIP1822     ProxyType = "eip1822"     // proxy using the original EIP-1822 PROXIABLE slot
+	ProxyTypeBeacon      ProxyType = "beacon"      // EIP-1967 proxy reading its implementation from a beacon
+	ProxyTypeMinimal     ProxyType = "minimal"     // EIP-1167 minimal proxy, not upgradeable
+)
+
+// ContractInfo describes the contract deployed at an address
+type ContractInfo struct {
+	Address     string
+	Code        CodeInfo
+	Proxy       *ProxyInfo // nil when the contract isn't a recognized proxy
+	BlockNumber uint64
+}
+
+// ProxyInfo describes where a proxy contract delegates its calls. Addresses
+// that don't apply to the proxy type are empty.
+type ProxyInfo struct {
+	Type           ProxyType
+	Implementation string
+	Admin          string
+	Beacon         string
+}
@@ -0,0 +1,100 @@
+package entity
+
+import "errors"
+
+// ErrorCode is a machine-readable error classification exposed to API clients
+type ErrorCode string
+
+// Error codes returned by the API
+const (
+	ErrCodeInvalidAddress      ErrorCode = "INVALID_ADDRESS"
+	ErrCodeInvalidRequest      ErrorCode = "INVALID_REQUEST"
+	ErrCodeNotFound            ErrorCode = "NOT_FOUND"
+	ErrCodeUnauthorized        ErrorCode = "UNAUTHORIZED"
+	ErrCodeForbidden           ErrorCode = "FORBIDDEN"
+	ErrCodeRateLimited         ErrorCode = "RATE_LIMITED"
+	ErrCodeUnsupportedMedia    ErrorCode = "UNSUPPORTED_MEDIA_TYPE"
+	ErrCodeRequestTimeout      ErrorCode = "REQUEST_TIMEOUT"
+	ErrCodeUpstreamUnavailable ErrorCode = "UPSTREAM_UNAVAILABLE"
+	ErrCodeUpstreamTimeout     ErrorCode = "UPSTREAM_TIMEOUT"
+	ErrCodeUpstreamError       ErrorCode = "UPSTREAM_ERROR"
+	ErrCodeInternal            ErrorCode = "INTERNAL_ERROR"
+)
+
+// ErrorCodes lists every error code the API returns, for documentation
+var ErrorCodes = []ErrorCode{
+	ErrCodeInvalidAddress,
+	ErrCodeInvalidRequest,
+	ErrCodeNotFound,
+	ErrCodeUnauthorized,
+	ErrCodeForbidden,
+	ErrCodeRateLimited,
+	ErrCodeUnsupportedMedia,
+	ErrCodeRequestTimeout,
+	ErrCodeUpstreamUnavailable,
+	ErrCodeUpstreamTimeout,
+	ErrCodeUpstreamError,
+	ErrCodeInternal,
+}
+
+// Error is a domain error carrying a code and a message that is safe to show
+// to clients. The wrapped error holds internal details for logging only.
+type Error struct {
+	Code    ErrorCode
+	Message string
+	Err     error
+}
+
+// Common domain errors
+var (
+	ErrInvalidAddress = &Error{Code: ErrCodeInvalidAddress, Message: "Invalid Ethereum address format"}
+	ErrNotFound       = &Error{Code: ErrCodeNotFound, Message: "Resource not found"}
+)
+
+// NewError creates a new domain error wrapping err
+func NewError(code ErrorCode, message string, err error) *Error {
+	return &Error{
+		Code:    code,
+		Message: message,
+		Err:     err,
+	}
+}
+
+// Error returns the message including the wrapped error's details
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap returns the wrapped error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is a domain error with the same code
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// ErrorCodeOf returns the code of the first domain error in err's chain,
+// or ErrCodeInternal if there is none
+func ErrorCodeOf(err error) ErrorCode {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Code
+	}
+	return ErrCodeInternal
+}
+
+// SafeMessage returns the client-safe message of the first domain error in
+// err's chain, or a generic message if there is none
+func SafeMessage(err error) string {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Message
+	}
+	return "Internal error"
+}
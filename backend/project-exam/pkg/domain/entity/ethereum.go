@@ -11,9 +11,45 @@ type AddressInfo struct {
 	GasPrice     GasPrice
 	CurrentBlock uint64
 	Balance      Balance
+	Nonce        Nonce
+	Code         CodeInfo
 	Timestamp    time.Time
 }
 
+// AddressRevision identifies the state an AddressInfo was read at: the head
+// block, and the pending nonce, which changes within a block as the sender's
+// transactions enter the node's pool
+type AddressRevision struct {
+	Block        uint64
+	PendingNonce uint64
+}
+
+// Nonce represents the transaction counts of an address
+type Nonce struct {
+	Latest  uint64 // transactions mined
+	Pending uint64 // transactions mined or pending in the node's pool
+}
+
+// AccountType classifies an address by the code deployed at it
+type AccountType string
+
+// Account types
+const (
+	AccountTypeEOA       AccountType = "eoa"
+	AccountTypeContract  AccountType = "contract"
+	AccountTypeDelegated AccountType = "delegated" // an EOA delegating to a contract under EIP-7702
+)
+
+// CodeInfo describes the code deployed at an address
+type CodeInfo struct {
+	Type        AccountType
+	IsContract  bool   // false for EOAs, including delegated ones
+	Hash        string // keccak256 of the code, the empty code hash for accounts without code
+	Size        int
+	DelegatedTo string // the EIP-7702 delegation target of delegated EOAs
+	IsProxy     bool   // the contract is an EIP-1167 minimal proxy or has an EIP-1967 implementation or beacon
+}
+
 // GasPrice represents gas price information
 type GasPrice struct {
 	Wei  *big.Int
@@ -25,3 +61,18 @@ type Balance struct {
 	Wei   *big.Int
 	Ether float64
 }
+
+// BlockHeader represents the summary of a block header
+type BlockHeader struct {
+	Number    uint64
+	Hash      string
+	Timestamp time.Time
+	BaseFee   *big.Int // nil before London (EIP-1559)
+}
+
+// SyncProgress represents the synchronisation state of an Ethereum node.
+// A nil SyncProgress means the node is not syncing.
+type SyncProgress struct {
+	CurrentBlock uint64
+	HighestBlock uint64
+}
@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+// HealthStatus is the state of the service or one of its dependencies
+type HealthStatus string
+
+// Health statuses
+const (
+	HealthStatusUp   HealthStatus = "up"
+	HealthStatusDown HealthStatus = "down"
+)
+
+// HealthCheck is the result of checking a single dependency
+type HealthCheck struct {
+	Name    string
+	Status  HealthStatus
+	Latency time.Duration
+	Details map[string]interface{}
+	Error   string
+}
+
+// HealthReport aggregates the dependency checks of a readiness probe
+type HealthReport struct {
+	Status    HealthStatus
+	Checks    []HealthCheck
+	Timestamp time.Time
+}
@@ -0,0 +1,18 @@
+package entity
+
+// LogFilter selects event logs like eth_getLogs does
+type LogFilter struct {
+	Addresses []string   // emitting contracts, any of them; empty matches every contract
+	Topics    [][]string // per position, any of the topics; empty positions match any topic
+	FromBlock BlockSelector
+	ToBlock   BlockSelector
+	BlockHash string // selects the logs of this block only, instead of the range
+}
+
+// LogsResult is the logs matching a filter in a block range
+type LogsResult struct {
+	Logs      []Log
+	Events    []*DecodedEvent // decoded event of each log, nil for logs the ABI has no event for
+	FromBlock uint64
+	ToBlock   uint64
+}
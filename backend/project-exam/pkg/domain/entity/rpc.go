@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Standard JSON-RPC 2.0 error codes, plus the server error range used for
+// upstream failures and the EIP-1474 code for rate limited calls
+const (
+	RPCCodeParseError     = -32700
+	RPCCodeInvalidRequest = -32600
+	RPCCodeMethodNotFound = -32601
+	RPCCodeInvalidParams  = -32602
+	RPCCodeInternalError  = -32603
+	RPCCodeServerError    = -32000
+	RPCCodeLimitExceeded  = -32005
+)
+
+// RPCCall is a single JSON-RPC call to forward to the Ethereum node
+type RPCCall struct {
+	Method string
+	Params []json.RawMessage
+}
+
+// RPCResult is the outcome of an RPCCall. Error is an *RPCError when the
+// node answered with a JSON-RPC error, or a domain error otherwise.
+type RPCResult struct {
+	Result json.RawMessage
+	Error  error
+}
+
+// RPCError is a JSON-RPC error, either answered by the node or raised while
+// validating a call
+type RPCError struct {
+	Code    int
+	Message string
+	Data    interface{}
+}
+
+// NewRPCError creates a new JSON-RPC error without data
+func NewRPCError(code int, format string, args ...interface{}) *RPCError {
+	return &RPCError{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// Error returns the message and code of the error
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
@@ -0,0 +1,104 @@
+package entity
+
+import "math/big"
+
+// Simulation is a transaction to execute with eth_call on the state of a
+// block, optionally with overridden account state and block fields
+type Simulation struct {
+	From                 string
+	To                   string // empty simulates a contract creation
+	Value                *big.Int
+	Data                 []byte
+	Gas                  uint64 // 0 leaves the gas limit to the node
+	GasPrice             *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	Block                BlockSelector
+	StateOverrides       map[string]*AccountOverride // by address
+	BlockOverrides       *BlockOverrides
+}
+
+// AccountOverride replaces parts of an account's state for a simulation.
+// Unset fields keep the account's state at the block.
+type AccountOverride struct {
+	Balance   *big.Int
+	Nonce     *uint64
+	Code      []byte            // nil keeps the code; empty removes it
+	State     map[string]string // replaces the whole storage, by 32-byte slot; empty clears it
+	StateDiff map[string]string // replaces single storage slots
+}
+
+// BlockOverrides replaces fields of the block a simulation executes in.
+// Unset fields keep those of the block.
+type BlockOverrides struct {
+	Number       *uint64
+	Time         *uint64
+	GasLimit     *uint64
+	BaseFee      *big.Int
+	FeeRecipient string
+	PrevRandao   string
+}
+
+// SimulationExecution is how the node executed a simulated transaction
+type SimulationExecution struct {
+	Block        *BlockHeader // block whose state the transaction executed on, before overrides
+	From         string       // sender the transaction executed as
+	FeeRecipient string       // coinbase of the block, with overrides
+	BaseFee      *big.Int     // base fee of the block, with overrides; nil before London
+	Output       []byte       // return data, or revert data when it reverted
+	Reverted     bool
+	Error        string  // why execution failed, empty if it succeeded
+	GasUsed      *uint64 // nil when the node couldn't tell
+	// Traced tells that GasUsed and Transfers come from a call trace. Without
+	// one, GasUsed is the node's gas estimate and Transfers only holds the
+	// transaction's own value transfer.
+	Traced    bool
+	Transfers []ValueTransfer
+	Called    []string // addresses called, in call order, when traced
+}
+
+// ValueTransfer is ether moved from one account to another by a call
+type ValueTransfer struct {
+	From  string
+	To    string
+	Value *big.Int
+}
+
+// RevertKind tells which kind of error revert data holds
+type RevertKind string
+
+// Revert kinds
+const (
+	RevertKindError   RevertKind = "error"   // Error(string), raised by require and revert with a message
+	RevertKindPanic   RevertKind = "panic"   // Panic(uint256), raised by failed asserts and compiler checks
+	RevertKindCustom  RevertKind = "custom"  // a custom error of a registered ABI
+	RevertKindUnknown RevertKind = "unknown" // no data, or an error no known ABI defines
+)
+
+// RevertReason is the decoded revert data of a reverted call
+type RevertReason struct {
+	Kind      RevertKind
+	Message   string       // the Error(string) message, or what the panic code means
+	PanicCode *big.Int     // the Panic(uint256) code
+	Error     *DecodedCall // the custom error, decoded like a call with its selector and arguments
+}
+
+// BalanceChange is how a simulated transaction changes an account's balance
+type BalanceChange struct {
+	Address string
+	Before  *big.Int
+	After   *big.Int
+	Delta   *big.Int
+}
+
+// SimulationResult is the outcome of a simulated transaction
+type SimulationResult struct {
+	Success        bool
+	ReturnData     []byte // revert data when it reverted
+	Error          string // why execution failed, empty if it succeeded
+	Revert         *RevertReason
+	GasUsed        *uint64
+	Traced         bool // see SimulationExecution.Traced
+	BalanceChanges []BalanceChange
+	Block          *BlockHeader
+}
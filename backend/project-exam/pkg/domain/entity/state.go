@@ -0,0 +1,57 @@
+package entity
+
+import "math/big"
+
+// BlockTag names a block by its position relative to the chain head
+type BlockTag string
+
+// Block tags
+const (
+	BlockTagLatest    BlockTag = "latest"
+	BlockTagSafe      BlockTag = "safe"
+	BlockTagFinalized BlockTag = "finalized"
+	BlockTagEarliest  BlockTag = "earliest"
+)
+
+// BlockSelector selects the block state is read at, by hash, number or tag.
+// The first of them that is set applies.
+type BlockSelector struct {
+	Hash   string
+	Number *uint64
+	Tag    BlockTag
+}
+
+// LatestBlock selects the latest block
+var LatestBlock = BlockSelector{Tag: BlockTagLatest}
+
+// StorageSlot is the value of a storage slot of an account at a block
+type StorageSlot struct {
+	Address string
+	Slot    string
+	Value   []byte // 32 bytes
+	Block   *BlockHeader
+}
+
+// AccountProof is a Merkle proof of an account and some of its storage
+// slots against the state root of a block (EIP-1186)
+type AccountProof struct {
+	Address       string
+	Block         *BlockHeader
+	StateRoot     string
+	Balance       *big.Int
+	Nonce         uint64
+	CodeHash      string
+	StorageHash   string
+	AccountProof  [][]byte // trie nodes from the state root to the account
+	StorageProofs []StorageProof
+	// Verified is set once the proofs were checked against StateRoot
+	Verified bool
+}
+
+// StorageProof is a Merkle proof of a storage slot against the storage root
+// of its account
+type StorageProof struct {
+	Slot  string
+	Value []byte // 32 bytes
+	Proof [][]byte
+}
@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"math/big"
+	"time"
+)
+
+// TransactionDetails is a transaction with its receipt, its input and logs
+// decoded with the ABI registry
+type TransactionDetails struct {
+	Transaction *Transaction
+	Receipt     *Receipt        // nil while the transaction is pending
+	Call        *DecodedCall    // nil when the input couldn't be decoded
+	Events      []*DecodedEvent // decoded event of each receipt log, nil for logs that couldn't be decoded
+}
+
+// SignedTransaction is a decoded signed transaction, ready to be broadcast.
+// Transaction.From is the sender recovered from the signature.
+type SignedTransaction struct {
+	Transaction
+	ChainID *big.Int // nil for legacy transactions without replay protection (EIP-155)
+	Cost    *big.Int // most the sender can be charged: value plus gas and blob gas at their fee caps
+	Raw     []byte
+}
+
+// TxState is a stage in the lifecycle of a tracked transaction
+type TxState string
+
+// Transaction lifecycle states. Finalized and replaced transactions don't
+// change state anymore; the others may move back, e.g. when a reorg removes
+// a transaction's block or a dropped transaction is broadcast again.
+const (
+	TxStatePending   TxState = "pending"   // known by the node but not mined
+	TxStateIncluded  TxState = "included"  // mined, with fewer confirmations than required
+	TxStateConfirmed TxState = "confirmed" // mined, with the required confirmations
+	TxStateFinalized TxState = "finalized" // mined in a finalized block
+	TxStateDropped   TxState = "dropped"   // no longer known by the node, its nonce unused
+	TxStateReplaced  TxState = "replaced"  // another transaction of the sender used its nonce
+)
+
+// Final reports whether a transaction in the state can't change state anymore
+func (s TxState) Final() bool {
+	return s == TxStateFinalized || s == TxStateReplaced
+}
+
+// TransactionStatus is the lifecycle state of a tracked transaction
+type TransactionStatus struct {
+	Hash          string
+	From          string
+	Nonce         uint64
+	State         TxState
+	BlockNumber   *uint64 // set once mined
+	BlockHash     string
+	Confirmations uint64 // blocks from its block to the head, inclusive; 0 until mined
+	Success       *bool  // receipt status, set once mined
+	ReplacedBy    string // hash of the transaction that used the nonce, when known
+	CheckedBlock  uint64 // head block the status was last checked at
+	UpdatedAt     time.Time
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/project-exam/pkg/domain/entity"
+)
+
+// ABIRepository decodes contract data with contract ABIs and keeps a
+// registry of the ABIs of known contracts. ABIs are given in their JSON
+// form, either as a full ABI or a single entry of one.
+type ABIRepository interface {
+	// EventTopics returns the topic identifying each non-anonymous event of
+	// an ABI. ABIs that can't be parsed fail with an invalid request error.
+	EventTopics(abiJSON []byte) ([]string, error)
+
+	// DecodeLogs decodes logs with the events of an ABI, in order; nil for
+	// logs that match no event of the ABI or can't be decoded with it
+	DecodeLogs(abiJSON []byte, logs []entity.Log) ([]*entity.DecodedEvent, error)
+
+	// DecodeCall decodes call input with the functions of an ABI; nil when
+	// no function of the ABI matches the input
+	DecodeCall(abiJSON []byte, input []byte) (*entity.DecodedCall, error)
+
+	// DecodeCallBySelector decodes call input with the function signatures
+	// of the bundled selector database; nil when none of the signatures with
+	// the input's selector decodes it
+	DecodeCallBySelector(input []byte) *entity.DecodedCall
+
+	// DecodeRevert decodes revert data holding one of the errors the
+	// compiler defines, Error(string) and Panic(uint256); nil for other data
+	DecodeRevert(data []byte) *entity.RevertReason
+
+	// DecodeError decodes revert data with the custom errors of an ABI; nil
+	// when no error of the ABI matches the data
+	DecodeError(abiJSON []byte, data []byte) (*entity.DecodedCall, error)
+
+	// SaveABI stores the ABI of a contract, replacing any previous one
+	SaveABI(ctx context.Context, address string, abiJSON []byte) (*entity.ContractABI, error)
+
+	// GetABI returns the stored ABI of a contract, nil if there is none
+	GetABI(ctx context.Context, address string) (*entity.ContractABI, error)
+
+	// DeleteABI removes the stored ABI of a contract, failing with a not
+	// found error if there is none
+	DeleteABI(ctx context.Context, address string) error
+}
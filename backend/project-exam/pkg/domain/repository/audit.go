@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/project-exam/pkg/domain/entity"
+)
+
+// AuditRepository defines an append-only store for audit events
+type AuditRepository interface {
+	// Record appends an audit event
+	Record(ctx context.Context, event *entity.AuditEvent) error
+
+	// Close flushes and releases the underlying storage
+	Close() error
+}
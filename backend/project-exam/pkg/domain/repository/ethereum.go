@@ -18,9 +18,91 @@ type EthereumRepository interface {
 	// GetAddressBalance returns the balance for the given address
 	GetAddressBalance(ctx context.Context, address string) (*big.Int, error)
 
+	// GetNonce returns the number of transactions mined from the given address
+	GetNonce(ctx context.Context, address string) (uint64, error)
+
+	// GetPendingNonce returns the number of transactions from the given
+	// address, including those pending in the node's pool
+	GetPendingNonce(ctx context.Context, address string) (uint64, error)
+
+	// GetCode returns the code at the given address, empty for accounts without code
+	GetCode(ctx context.Context, address string) ([]byte, error)
+
+	// GetStorageAt returns the 32-byte value of a storage slot of the given address
+	GetStorageAt(ctx context.Context, address, slot string) ([]byte, error)
+
+	// GetBlockHeader returns the header of the selected block
+	GetBlockHeader(ctx context.Context, block entity.BlockSelector) (*entity.BlockHeader, error)
+
+	// GetStorageAtBlock returns the value of a storage slot of the given
+	// address at the selected block
+	GetStorageAtBlock(ctx context.Context, address, slot string, block entity.BlockSelector) (*entity.StorageSlot, error)
+
+	// GetProof returns the Merkle proof of an account and storage slots at
+	// the selected block, verified against the block's state root
+	GetProof(ctx context.Context, address string, slots []string, block entity.BlockSelector) (*entity.AccountProof, error)
+
+	// GetChainID returns the chain ID of the connected network
+	GetChainID(ctx context.Context) (*big.Int, error)
+
+	// GetSyncProgress returns the node's sync progress, or nil when it is fully synced
+	GetSyncProgress(ctx context.Context) (*entity.SyncProgress, error)
+
+	// GetLatestHeader returns the header of the latest block
+	GetLatestHeader(ctx context.Context) (*entity.BlockHeader, error)
+
+	// SubscribeNewHeads streams the header of every new head block until ctx
+	// is cancelled. The channel is closed when the stream ends.
+	SubscribeNewHeads(ctx context.Context) (<-chan *entity.BlockHeader, error)
+
 	// GetAddressInfo retrieves all required information for an address in a single call
 	GetAddressInfo(ctx context.Context, address string) (*entity.AddressInfo, error)
 
+	// GetBalances returns the latest balances of addresses, in order
+	GetBalances(ctx context.Context, addresses []string) ([]*big.Int, error)
+
+	// GetBalancesAtBlock returns the balances of addresses at the selected
+	// block, in order
+	GetBalancesAtBlock(ctx context.Context, addresses []string, block entity.BlockSelector) ([]*big.Int, error)
+
+	// GetNonces returns the latest nonces of addresses, in order
+	GetNonces(ctx context.Context, addresses []string) ([]uint64, error)
+
+	// GetCodes returns the code at addresses, in order; empty for accounts without code
+	GetCodes(ctx context.Context, addresses []string) ([][]byte, error)
+
+	// GetBlocksByNumber returns blocks by number, in order; nil for blocks that don't exist yet
+	GetBlocksByNumber(ctx context.Context, numbers []uint64) ([]*entity.Block, error)
+
+	// GetBlocksByHash returns blocks by hash, in order; nil for unknown blocks
+	GetBlocksByHash(ctx context.Context, hashes []string) ([]*entity.Block, error)
+
+	// GetTransactions returns transactions by hash, in order; nil for unknown transactions
+	GetTransactions(ctx context.Context, hashes []string) ([]*entity.Transaction, error)
+
+	// GetReceipts returns the receipts of transactions by hash, in order; nil
+	// for transactions that aren't mined
+	GetReceipts(ctx context.Context, hashes []string) ([]*entity.Receipt, error)
+
+	// CallContracts performs read-only calls at the latest block, in order.
+	// Calls that fail report their error in their result.
+	CallContracts(ctx context.Context, calls []entity.ContractCall) ([]entity.CallResult, error)
+
+	// FilterLogs returns the logs matching a filter in a single query.
+	// Block ranges must be given by number; nodes may reject large ones.
+	FilterLogs(ctx context.Context, filter entity.LogFilter) ([]entity.Log, error)
+
+	// DecodeTransaction decodes a signed raw transaction and recovers its sender
+	DecodeTransaction(raw []byte) (*entity.SignedTransaction, error)
+
+	// SendTransaction submits a signed transaction to the node's transaction pool
+	SendTransaction(ctx context.Context, tx *entity.SignedTransaction) error
+
+	// SimulateTransaction executes a transaction with eth_call on the state
+	// of the selected block. A transaction that reverts or fails to execute
+	// reports it in the execution rather than as an error.
+	SimulateTransaction(ctx context.Context, sim *entity.Simulation) (*entity.SimulationExecution, error)
+
 	// Close closes any connections to the Ethereum network
 	Close()
 }
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// RPCRepository forwards raw JSON-RPC calls to the Ethereum network
+type RPCRepository interface {
+	// Call performs a JSON-RPC call and returns its raw result. Errors the
+	// node answers with are returned as *entity.RPCError.
+	Call(ctx context.Context, method string, params []json.RawMessage) (json.RawMessage, error)
+}
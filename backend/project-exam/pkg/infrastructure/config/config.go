@@ -1,53 +1,215 @@
 package config
 
 import (
-	"log"
-	"os"
-	"strconv"
+	"errors"
+	"net/http"
 	"strings"
 	"time"
 
-	"github.com/joho/godotenv"
+	"github.com/project-exam/pkg/infrastructure/secrets"
 )
 
 // Config holds all configuration for the application
 type Config struct {
 	Server   ServerConfig
 	Ethereum EthereumConfig
+	RPC      RPCConfig
+	GraphQL  GraphQLConfig
+	Logs     LogsConfig
+	ABI      ABIConfig
+	Tracking TrackingConfig
 	Log      LogConfig
+	Audit    AuditConfig
+	Health   HealthConfig
+	Reload   ReloadConfig
+	Vault    VaultConfig
+
+	file      string            // config file path, empty when configured by environment only
+	effective map[string]string // raw effective values by environment variable name
+}
+
+// VaultConfig configures the Vault compatible server used to resolve
+// vault:// secret references
+type VaultConfig struct {
+	Addr  string
+	Token string
+}
+
+// ReloadConfig configures runtime reloading of the configuration
+type ReloadConfig struct {
+	WatchInterval time.Duration // how often the config file is checked for changes, 0 disables polling
+}
+
+// HealthConfig configures the readiness probe
+type HealthConfig struct {
+	ExpectedChainID uint64        // 0 accepts any chain
+	MaxBlockAge     time.Duration // head block older than this marks the node as stale
+	CheckTimeout    time.Duration
 }
 
 // ServerConfig holds configuration related to the HTTP server
 type ServerConfig struct {
 	Port         string
+	GRPCPort     string // port of the gRPC API, "0" disables it
 	Mode         string // debug or release
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
-	RateLimit    RateLimitConfig
-	Auth         AuthConfig
+	// RequestTimeout bounds handler execution; RouteTimeouts overrides it per
+	// route path, with zero disabling the timeout for that route
+	RequestTimeout time.Duration
+	RouteTimeouts  map[string]time.Duration
+	RateLimit      RateLimitConfig
+	Auth           AuthConfig
+	CORS           CORSConfig
+	ClientIP       ClientIPConfig
+	Deprecation    DeprecationConfig
+}
+
+// DeprecationConfig schedules the removal of the unversioned /api routes
+type DeprecationConfig struct {
+	Date   time.Time // announced in the Deprecation header
+	Sunset time.Time // announced in the Sunset header, zero if not scheduled
+}
+
+// ClientIPConfig controls how the real client IP is determined and filtered
+type ClientIPConfig struct {
+	TrustedProxies []string // CIDRs of proxies allowed to set forwarding headers
+	Header         string   // forwarding header the trusted proxies set
+	AllowCIDRs     []string // when non-empty, only these networks may call the API
+	DenyCIDRs      []string // networks that may never call the API
+}
+
+// CORSConfig configures Cross-Origin Resource Sharing
+type CORSConfig struct {
+	AllowedOrigins   []string // exact origins, "https://*.example.com" patterns or "*"
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
 }
 
 // RateLimitConfig configures the rate limiter
 type RateLimitConfig struct {
-	Limit  int
-	Window time.Duration
+	Limit       int
+	Window      time.Duration
+	ExemptCIDRs []string // networks that are not rate limited
 }
 
 // AuthConfig holds authentication configuration
 type AuthConfig struct {
-	Enabled bool
-	APIKeys map[string]string // map[apiKey]userID
+	Enabled       bool
+	APIKeys       map[string]APIKeyConfig // map[apiKey]key details
+	DefaultScopes []string                // scopes granted to keys that don't declare any
+	JWT           JWTConfig
+}
+
+// JWTConfig holds bearer token (OIDC) authentication configuration
+type JWTConfig struct {
+	Enabled     bool
+	JWKS        string // file path or http(s) URL of the JWKS document
+	JWKSRefresh time.Duration
+	Issuer      string
+	Audience    string
+	UserClaim   string
+	ScopesClaim string
+	Leeway      time.Duration
+	// RequireExpiry rejects tokens without an "exp" claim, which never expire
+	RequireExpiry bool
+}
+
+// APIKeyConfig describes the owner and permissions of a single API key
+type APIKeyConfig struct {
+	UserID string
+	Scopes []string
 }
 
 // EthereumConfig holds configuration related to Ethereum client
 type EthereumConfig struct {
 	RPCURL          string
+	FallbackRPCURLs []string // tried in order when RPCURL fails, by the JSON-RPC proxy
 	RequestTimeout  time.Duration
 	DefaultGasLimit uint64
 	RetryAttempts   int
 	RetryDelay      time.Duration
 }
 
+// RPCConfig configures the JSON-RPC proxy endpoint
+type RPCConfig struct {
+	AllowedMethods []string      // JSON-RPC methods forwarded to the node
+	CacheTTL       time.Duration // how long results at the latest block are cached, 0 disables caching
+	MaxBatchSize   int
+}
+
+// GraphQLConfig configures the GraphQL endpoint
+type GraphQLConfig struct {
+	MaxDepth  int // deepest nesting of fields a query may use
+	MaxCost   int // most node calls a query may make
+	MaxFields int // most top-level fields a query may have, aliases included
+}
+
+// LogsConfig configures the event log query endpoint
+type LogsConfig struct {
+	ChunkSize     uint64 // blocks queried per eth_getLogs call, halved while the node rejects a chunk
+	MaxBlockRange uint64 // widest block range a query may span
+	MaxResults    int    // most logs a query may return
+}
+
+// ABIConfig configures the ABI registry
+type ABIConfig struct {
+	RegistryDir string // directory registered ABIs are stored in, empty to keep them in memory only
+}
+
+// TrackingConfig configures transaction lifecycle tracking
+type TrackingConfig struct {
+	Confirmations uint64        // blocks, including its own, after which an included transaction is confirmed
+	Retention     time.Duration // how long transactions are kept after they are finalized, replaced or dropped
+	MaxTracked    int           // most transactions tracked at once
+	MaxPerClient  int           // most transactions tracked at once that a client started tracking by looking them up
+}
+
+// DefaultRPCMethods are the read-only JSON-RPC methods the proxy forwards by
+// default. Methods that send transactions, sign, or manage filters and
+// subscriptions held on the node are excluded.
+var DefaultRPCMethods = []string{
+	"eth_blockNumber",
+	"eth_call",
+	"eth_chainId",
+	"eth_estimateGas",
+	"eth_feeHistory",
+	"eth_gasPrice",
+	"eth_getBalance",
+	"eth_getBlockByHash",
+	"eth_getBlockByNumber",
+	"eth_getBlockReceipts",
+	"eth_getBlockTransactionCountByHash",
+	"eth_getBlockTransactionCountByNumber",
+	"eth_getCode",
+	"eth_getLogs",
+	"eth_getProof",
+	"eth_getStorageAt",
+	"eth_getTransactionByBlockHashAndIndex",
+	"eth_getTransactionByBlockNumberAndIndex",
+	"eth_getTransactionByHash",
+	"eth_getTransactionCount",
+	"eth_getTransactionReceipt",
+	"eth_maxPriorityFeePerGas",
+	"eth_syncing",
+	"net_version",
+	"web3_clientVersion",
+}
+
+// AuditConfig holds audit logging configuration
+type AuditConfig struct {
+	Enabled    bool
+	FilePath   string
+	MaxSize    int64  // bytes before the file is rotated, 0 disables rotation
+	MaxBackups int    // rotated files to keep, 0 keeps all
+	DBDriver   string // database/sql driver compiled into the binary, e.g. "pgx"
+	DBDSN      string // optional database sink, used when set
+	QueueSize  int    // events waiting to be written before new ones are dropped
+}
+
 // LogConfig holds logging configuration
 type LogConfig struct {
 	Level      string // debug, info, warn, error
@@ -55,94 +217,194 @@ type LogConfig struct {
 	OutputPath string // stdout, stderr, or filepath
 }
 
-// LoadConfig loads configuration from environment variables
-func LoadConfig() *Config {
-	// Load .env file if it exists
-	if err := godotenv.Load(); err != nil {
-		log.Println("Warning: .env file not found, using environment variables")
+// Load loads configuration from an optional YAML or TOML file, with
+// environment variables and then the .env file taking precedence, and
+// validates the result. When path is empty, the CONFIG_FILE environment
+// variable is used. All invalid settings are reported together in the
+// returned error.
+func Load(path string) (*Config, error) {
+	return load(path, nil)
+}
+
+// load loads configuration like Load, with the raw values of pinned
+// settings taking precedence over every source
+func load(path string, pinned map[string]string) (*Config, error) {
+	l := newLoader(pinned)
+	if err := l.readDotenv(); err != nil {
+		return nil, err
+	}
+	if path == "" {
+		path, _ = l.env("CONFIG_FILE")
+	}
+	if path != "" {
+		if err := l.readFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	// Secrets may reference Vault, so its settings are read first
+	vault := VaultConfig{
+		Addr:  l.getString("VAULT_ADDR", ""),
+		Token: l.getString("VAULT_TOKEN", ""),
+	}
+	if vault.Addr != "" {
+		l.resolver.Register("vault", secrets.NewVaultProvider(vault.Addr, vault.Token))
 	}
 
 	// Parse API keys from environment
-	apiKeys := make(map[string]string)
-	apiKeysStr := getEnv("API_KEYS", "")
-	if apiKeysStr != "" {
-		keyPairs := strings.Split(apiKeysStr, ",")
-		for _, pair := range keyPairs {
-			parts := strings.Split(pair, ":")
-			if len(parts) == 2 {
-				apiKeys[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
-			}
+	// Format: key:user[:scope1|scope2], scopes may themselves contain colons
+	defaultScopes := l.getList("AUTH_DEFAULT_SCOPES", []string{"read:address", "read:tx"})
+	apiKeys := make(map[string]APIKeyConfig)
+	for _, pair := range l.getList("API_KEYS", nil) {
+		parts := strings.SplitN(pair, ":", 3)
+		if len(parts) < 2 || strings.TrimSpace(parts[0]) == "" {
+			l.errs = append(l.errs, errors.New("API_KEYS: entries must have the form key:user[:scope1|scope2]"))
+			continue
+		}
+
+		scopes := defaultScopes
+		if len(parts) == 3 {
+			scopes = splitList(parts[2], "|")
+		}
+
+		apiKeys[strings.TrimSpace(parts[0])] = APIKeyConfig{
+			UserID: strings.TrimSpace(parts[1]),
+			Scopes: scopes,
 		}
 	}
 
-	return &Config{
+	cfg := &Config{
 		Server: ServerConfig{
-			Port:         getEnv("PORT", "8080"),
-			Mode:         getEnv("GIN_MODE", "debug"),
-			ReadTimeout:  getDurationEnv("SERVER_READ_TIMEOUT", 10*time.Second),
-			WriteTimeout: getDurationEnv("SERVER_WRITE_TIMEOUT", 10*time.Second),
+			Port:           l.getString("PORT", "8080"),
+			GRPCPort:       l.getString("GRPC_PORT", "9090"),
+			Mode:           l.getString("GIN_MODE", "debug"),
+			ReadTimeout:    l.getDuration("SERVER_READ_TIMEOUT", 10*time.Second),
+			WriteTimeout:   l.getDuration("SERVER_WRITE_TIMEOUT", 10*time.Second),
+			RequestTimeout: l.getDuration("REQUEST_TIMEOUT", 30*time.Second),
+			RouteTimeouts:  l.getDurationMap("ROUTE_TIMEOUTS"), // Format: /path=duration, comma separated
 			RateLimit: RateLimitConfig{
-				Limit:  getIntEnv("RATE_LIMIT", 100),
-				Window: getDurationEnv("RATE_LIMIT_WINDOW", 15*time.Minute),
+				Limit:       l.getInt("RATE_LIMIT", 100),
+				Window:      l.getDuration("RATE_LIMIT_WINDOW", 15*time.Minute),
+				ExemptCIDRs: l.getCIDRList("RATE_LIMIT_EXEMPT_CIDRS", nil),
+			},
+			Deprecation: DeprecationConfig{
+				Date:   l.getTime("API_DEPRECATION_DATE", time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)),
+				Sunset: l.getTime("API_SUNSET_DATE", time.Date(2027, time.May, 1, 0, 0, 0, 0, time.UTC)),
+			},
+			ClientIP: ClientIPConfig{
+				TrustedProxies: l.getCIDRList("TRUSTED_PROXIES", nil),
+				Header:         http.CanonicalHeaderKey(l.getString("CLIENT_IP_HEADER", "X-Forwarded-For")),
+				AllowCIDRs:     l.getCIDRList("IP_ALLOW_CIDRS", nil),
+				DenyCIDRs:      l.getCIDRList("IP_DENY_CIDRS", nil),
+			},
+			CORS: CORSConfig{
+				AllowedOrigins: l.getList("CORS_ALLOWED_ORIGINS", []string{"*"}),
+				AllowedMethods: l.getList("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}),
+				AllowedHeaders: l.getList("CORS_ALLOWED_HEADERS", []string{
+					"Origin", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "X-API-Key",
+				}),
+				ExposedHeaders:   l.getList("CORS_EXPOSED_HEADERS", []string{"Content-Length", "Content-Type", "X-Request-ID", "API-Version", "Deprecation", "Sunset", "Link"}),
+				AllowCredentials: l.getBool("CORS_ALLOW_CREDENTIALS", false),
+				MaxAge:           l.getDuration("CORS_MAX_AGE", 10*time.Minute),
 			},
 			Auth: AuthConfig{
-				Enabled: getBoolEnv("AUTH_ENABLED", false),
-				APIKeys: apiKeys,
+				Enabled:       l.getBool("AUTH_ENABLED", false),
+				APIKeys:       apiKeys,
+				DefaultScopes: defaultScopes,
+				JWT: JWTConfig{
+					Enabled:       l.getBool("AUTH_JWT_ENABLED", false),
+					JWKS:          l.getString("AUTH_JWT_JWKS", ""),
+					JWKSRefresh:   l.getDuration("AUTH_JWT_JWKS_REFRESH", 15*time.Minute),
+					Issuer:        l.getString("AUTH_JWT_ISSUER", ""),
+					Audience:      l.getString("AUTH_JWT_AUDIENCE", ""),
+					UserClaim:     l.getString("AUTH_JWT_USER_CLAIM", "sub"),
+					ScopesClaim:   l.getString("AUTH_JWT_SCOPES_CLAIM", "scope"),
+					Leeway:        l.getDuration("AUTH_JWT_LEEWAY", 30*time.Second),
+					RequireExpiry: l.getBool("AUTH_JWT_REQUIRE_EXP", true),
+				},
 			},
 		},
 		Ethereum: EthereumConfig{
-			RPCURL:          getEnv("ETHEREUM_RPC_URL", "https://mainnet.infura.io/v3/YOUR_INFURA_KEY"),
-			RequestTimeout:  getDurationEnv("ETHEREUM_REQUEST_TIMEOUT", 10*time.Second),
-			DefaultGasLimit: getUint64Env("ETHEREUM_DEFAULT_GAS_LIMIT", 21000),
-			RetryAttempts:   getIntEnv("ETHEREUM_RETRY_ATTEMPTS", 3),
-			RetryDelay:      getDurationEnv("ETHEREUM_RETRY_DELAY", 1*time.Second),
+			RPCURL:          l.getString("ETHEREUM_RPC_URL", ""),
+			FallbackRPCURLs: l.getList("ETHEREUM_FALLBACK_RPC_URLS", nil),
+			RequestTimeout:  l.getDuration("ETHEREUM_REQUEST_TIMEOUT", 10*time.Second),
+			DefaultGasLimit: l.getUint64("ETHEREUM_DEFAULT_GAS_LIMIT", 21000),
+			RetryAttempts:   l.getInt("ETHEREUM_RETRY_ATTEMPTS", 3),
+			RetryDelay:      l.getDuration("ETHEREUM_RETRY_DELAY", 1*time.Second),
+		},
+		RPC: RPCConfig{
+			AllowedMethods: l.getList("RPC_ALLOWED_METHODS", DefaultRPCMethods),
+			CacheTTL:       l.getDuration("RPC_CACHE_TTL", 2*time.Second),
+			MaxBatchSize:   l.getInt("RPC_MAX_BATCH_SIZE", 100),
+		},
+		GraphQL: GraphQLConfig{
+			MaxDepth:  l.getInt("GRAPHQL_MAX_DEPTH", 8),
+			MaxCost:   l.getInt("GRAPHQL_MAX_COST", 2000),
+			MaxFields: l.getInt("GRAPHQL_MAX_FIELDS", 20),
+		},
+		Logs: LogsConfig{
+			ChunkSize:     l.getUint64("LOGS_CHUNK_SIZE", 2000),
+			MaxBlockRange: l.getUint64("LOGS_MAX_BLOCK_RANGE", 100000),
+			MaxResults:    l.getInt("LOGS_MAX_RESULTS", 10000),
+		},
+		ABI: ABIConfig{
+			RegistryDir: l.getString("ABI_REGISTRY_DIR", ""),
+		},
+		Tracking: TrackingConfig{
+			Confirmations: l.getUint64("TX_CONFIRMATIONS", 12),
+			Retention:     l.getDuration("TX_TRACKING_RETENTION", time.Hour),
+			MaxTracked:    l.getInt("TX_TRACKING_MAX", 10000),
+			MaxPerClient:  l.getInt("TX_TRACKING_MAX_PER_CLIENT", 100),
 		},
 		Log: LogConfig{
-			Level:      getEnv("LOG_LEVEL", "info"),
-			Format:     getEnv("LOG_FORMAT", "json"),
-			OutputPath: getEnv("LOG_OUTPUT", "stdout"),
+			Level:      l.getString("LOG_LEVEL", "info"),
+			Format:     l.getString("LOG_FORMAT", "json"),
+			OutputPath: l.getString("LOG_OUTPUT", "stdout"),
 		},
+		Health: HealthConfig{
+			ExpectedChainID: l.getUint64("HEALTH_EXPECTED_CHAIN_ID", 0),
+			MaxBlockAge:     l.getDuration("HEALTH_MAX_BLOCK_AGE", 2*time.Minute),
+			CheckTimeout:    l.getDuration("HEALTH_CHECK_TIMEOUT", 5*time.Second),
+		},
+		Audit: AuditConfig{
+			Enabled:    l.getBool("AUDIT_ENABLED", false),
+			FilePath:   l.getString("AUDIT_LOG_PATH", "logs/audit.jsonl"),
+			MaxSize:    int64(l.getInt("AUDIT_LOG_MAX_SIZE_MB", 100)) * 1024 * 1024,
+			MaxBackups: l.getInt("AUDIT_LOG_MAX_BACKUPS", 30),
+			DBDriver:   l.getString("AUDIT_DB_DRIVER", "pgx"),
+			DBDSN:      l.getString("AUDIT_DB_DSN", ""),
+			QueueSize:  l.getInt("AUDIT_QUEUE_SIZE", 1000),
+		},
+		Reload: ReloadConfig{
+			WatchInterval: l.getDuration("CONFIG_WATCH_INTERVAL", 10*time.Second),
+		},
+		Vault: vault,
 	}
-}
-
-// Helper functions for environment variables
-func getEnv(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
-	}
-	return defaultValue
-}
 
-func getIntEnv(key string, defaultValue int) int {
-	valueStr := getEnv(key, "")
-	if value, err := strconv.Atoi(valueStr); err == nil {
-		return value
+	errs := append(l.errs, cfg.validate()...)
+	if len(errs) > 0 {
+		return nil, &ValidationError{Errors: errs}
 	}
-	return defaultValue
-}
+	cfg.file = path
+	cfg.effective = l.effective
 
-func getUint64Env(key string, defaultValue uint64) uint64 {
-	valueStr := getEnv(key, "")
-	if value, err := strconv.ParseUint(valueStr, 10, 64); err == nil {
-		return value
-	}
-	return defaultValue
+	return cfg, nil
 }
 
-func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
-	valueStr := getEnv(key, "")
-	if value, err := time.ParseDuration(valueStr); err == nil {
-		return value
+// SecretValues returns the secrets in the effective configuration, such as
+// API keys and credentials embedded in URLs, so they can be redacted from logs
+func (c *Config) SecretValues() []string {
+	var values []string
+	for _, s := range settings {
+		if s.secret != nil {
+			values = append(values, s.secret(c.effective[s.env])...)
+		}
 	}
-	return defaultValue
+	return values
 }
 
-func getBoolEnv(key string, defaultValue bool) bool {
-	valueStr := getEnv(key, "")
-	if valueStr == "" {
-		return defaultValue
-	}
-
-	value := strings.ToLower(valueStr)
-	return value == "true" || value == "1" || value == "yes" || value == "y"
+// File returns the path of the config file the configuration was loaded
+// from, or an empty string when no file was used
+func (c *Config) File() string {
+	return c.file
 }
@@ -0,0 +1,599 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/project-exam/pkg/infrastructure/secrets"
+)
+
+// setting describes a configuration value that can be set from the config
+// file (by its dotted path) or overridden by an environment variable.
+// Secret settings can also be read from the file named by <env>_FILE or
+// given as a secret reference such as vault://secret/data/ethereum#rpcUrl.
+type setting struct {
+	env    string
+	file   string
+	list   bool
+	redact func(string) string   // hides secrets when printing the value
+	secret func(string) []string // extracts the secret parts of the value
+}
+
+// settings lists every supported setting in the order used by PrintConfig
+var settings = []setting{
+	{env: "PORT", file: "server.port"},
+	{env: "GRPC_PORT", file: "server.grpcPort"},
+	{env: "GIN_MODE", file: "server.mode"},
+	{env: "SERVER_READ_TIMEOUT", file: "server.readTimeout"},
+	{env: "SERVER_WRITE_TIMEOUT", file: "server.writeTimeout"},
+	{env: "REQUEST_TIMEOUT", file: "server.requestTimeout"},
+	{env: "ROUTE_TIMEOUTS", file: "server.routeTimeouts", list: true},
+	{env: "RATE_LIMIT", file: "server.rateLimit.limit"},
+	{env: "RATE_LIMIT_WINDOW", file: "server.rateLimit.window"},
+	{env: "RATE_LIMIT_EXEMPT_CIDRS", file: "server.rateLimit.exemptCidrs", list: true},
+	{env: "TRUSTED_PROXIES", file: "server.clientIp.trustedProxies", list: true},
+	{env: "CLIENT_IP_HEADER", file: "server.clientIp.header"},
+	{env: "IP_ALLOW_CIDRS", file: "server.clientIp.allowCidrs", list: true},
+	{env: "IP_DENY_CIDRS", file: "server.clientIp.denyCidrs", list: true},
+	{env: "CORS_ALLOWED_ORIGINS", file: "server.cors.allowedOrigins", list: true},
+	{env: "CORS_ALLOWED_METHODS", file: "server.cors.allowedMethods", list: true},
+	{env: "CORS_ALLOWED_HEADERS", file: "server.cors.allowedHeaders", list: true},
+	{env: "CORS_EXPOSED_HEADERS", file: "server.cors.exposedHeaders", list: true},
+	{env: "CORS_ALLOW_CREDENTIALS", file: "server.cors.allowCredentials"},
+	{env: "CORS_MAX_AGE", file: "server.cors.maxAge"},
+	{env: "API_DEPRECATION_DATE", file: "server.deprecation.date"},
+	{env: "API_SUNSET_DATE", file: "server.deprecation.sunset"},
+	{env: "AUTH_ENABLED", file: "server.auth.enabled"},
+	{env: "AUTH_DEFAULT_SCOPES", file: "server.auth.defaultScopes", list: true},
+	{env: "API_KEYS", file: "server.auth.apiKeys", list: true, redact: redactAPIKeys, secret: apiKeySecrets},
+	{env: "AUTH_JWT_ENABLED", file: "server.auth.jwt.enabled"},
+	{env: "AUTH_JWT_JWKS", file: "server.auth.jwt.jwks", redact: redactURL, secret: urlSecrets},
+	{env: "AUTH_JWT_JWKS_REFRESH", file: "server.auth.jwt.jwksRefresh"},
+	{env: "AUTH_JWT_ISSUER", file: "server.auth.jwt.issuer"},
+	{env: "AUTH_JWT_AUDIENCE", file: "server.auth.jwt.audience"},
+	{env: "AUTH_JWT_USER_CLAIM", file: "server.auth.jwt.userClaim"},
+	{env: "AUTH_JWT_SCOPES_CLAIM", file: "server.auth.jwt.scopesClaim"},
+	{env: "AUTH_JWT_LEEWAY", file: "server.auth.jwt.leeway"},
+	{env: "AUTH_JWT_REQUIRE_EXP", file: "server.auth.jwt.requireExp"},
+	{env: "ETHEREUM_RPC_URL", file: "ethereum.rpcUrl", redact: redactURL, secret: urlSecrets},
+	{env: "ETHEREUM_FALLBACK_RPC_URLS", file: "ethereum.fallbackRpcUrls", list: true, redact: redactURLList, secret: urlListSecrets},
+	{env: "ETHEREUM_REQUEST_TIMEOUT", file: "ethereum.requestTimeout"},
+	{env: "ETHEREUM_DEFAULT_GAS_LIMIT", file: "ethereum.defaultGasLimit"},
+	{env: "ETHEREUM_RETRY_ATTEMPTS", file: "ethereum.retryAttempts"},
+	{env: "ETHEREUM_RETRY_DELAY", file: "ethereum.retryDelay"},
+	{env: "RPC_ALLOWED_METHODS", file: "rpc.allowedMethods", list: true},
+	{env: "RPC_CACHE_TTL", file: "rpc.cacheTtl"},
+	{env: "RPC_MAX_BATCH_SIZE", file: "rpc.maxBatchSize"},
+	{env: "GRAPHQL_MAX_DEPTH", file: "graphql.maxDepth"},
+	{env: "GRAPHQL_MAX_COST", file: "graphql.maxCost"},
+	{env: "GRAPHQL_MAX_FIELDS", file: "graphql.maxFields"},
+	{env: "LOGS_CHUNK_SIZE", file: "logs.chunkSize"},
+	{env: "LOGS_MAX_BLOCK_RANGE", file: "logs.maxBlockRange"},
+	{env: "LOGS_MAX_RESULTS", file: "logs.maxResults"},
+	{env: "ABI_REGISTRY_DIR", file: "abi.registryDir"},
+	{env: "TX_CONFIRMATIONS", file: "tracking.confirmations"},
+	{env: "TX_TRACKING_RETENTION", file: "tracking.retention"},
+	{env: "TX_TRACKING_MAX", file: "tracking.maxTracked"},
+	{env: "TX_TRACKING_MAX_PER_CLIENT", file: "tracking.maxPerClient"},
+	{env: "LOG_LEVEL", file: "log.level"},
+	{env: "LOG_FORMAT", file: "log.format"},
+	{env: "LOG_OUTPUT", file: "log.output"},
+	{env: "HEALTH_EXPECTED_CHAIN_ID", file: "health.expectedChainId"},
+	{env: "HEALTH_MAX_BLOCK_AGE", file: "health.maxBlockAge"},
+	{env: "HEALTH_CHECK_TIMEOUT", file: "health.checkTimeout"},
+	{env: "AUDIT_ENABLED", file: "audit.enabled"},
+	{env: "AUDIT_LOG_PATH", file: "audit.path"},
+	{env: "AUDIT_LOG_MAX_SIZE_MB", file: "audit.maxSizeMb"},
+	{env: "AUDIT_LOG_MAX_BACKUPS", file: "audit.maxBackups"},
+	{env: "AUDIT_QUEUE_SIZE", file: "audit.queueSize"},
+	{env: "AUDIT_DB_DRIVER", file: "audit.dbDriver"},
+	{env: "AUDIT_DB_DSN", file: "audit.dbDsn", redact: redactAll, secret: valueSecret},
+	{env: "CONFIG_WATCH_INTERVAL", file: "reload.watchInterval"},
+	{env: "VAULT_ADDR", file: "vault.addr"},
+	{env: "VAULT_TOKEN", file: "vault.token", redact: redactAll, secret: valueSecret},
+}
+
+// settingsByEnv indexes settings by environment variable name
+var settingsByEnv = func() map[string]setting {
+	m := make(map[string]setting, len(settings))
+	for _, s := range settings {
+		m[s.env] = s
+	}
+	return m
+}()
+
+// loader resolves settings from environment variables, then the .env file,
+// then the config file, then defaults, recording the effective values and
+// every parse error
+type loader struct {
+	pinned    map[string]string // map[env]value taking precedence over every source
+	dotenv    map[string]string // map[env]value from the .env file
+	file      map[string]string // map[env]value from the config file
+	effective map[string]string // map[env]value actually used
+	resolver  *secrets.Resolver
+	errs      []error
+}
+
+func newLoader(pinned map[string]string) *loader {
+	return &loader{
+		pinned:    pinned,
+		file:      make(map[string]string),
+		effective: make(map[string]string),
+		resolver:  secrets.NewResolver(),
+	}
+}
+
+// readDotenv reads the .env file of the working directory, if there is one.
+// Its variables are kept apart from the process environment, which they
+// would never override again, so that edits are picked up on reload.
+func (l *loader) readDotenv() error {
+	vars, err := godotenv.Read()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read .env: %w", err)
+	}
+
+	l.dotenv = vars
+	l.resolver.Register("env", secrets.EnvProvider{Fallback: vars})
+	return nil
+}
+
+// env returns the value of an environment variable, falling back to the
+// .env file
+func (l *loader) env(key string) (string, bool) {
+	if value, ok := os.LookupEnv(key); ok {
+		return value, true
+	}
+	value, ok := l.dotenv[key]
+	return value, ok
+}
+
+// readFile loads a YAML or TOML config file, chosen by extension
+func (l *loader) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc map[string]interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &doc)
+	case ".toml":
+		err = toml.Unmarshal(data, &doc)
+	default:
+		return fmt.Errorf("unsupported config file type %q (use .yaml, .yml or .toml)", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	envByFile := make(map[string]string, len(settings))
+	for _, s := range settings {
+		envByFile[s.file] = s.env
+	}
+
+	values := make(map[string]string)
+	flatten("", doc, values)
+	for path, value := range values {
+		env, ok := envByFile[path]
+		if !ok {
+			l.errs = append(l.errs, fmt.Errorf("%s: unknown setting", path))
+			continue
+		}
+		l.file[env] = value
+	}
+
+	return nil
+}
+
+// flatten converts nested maps into dotted paths, joining lists with commas
+func flatten(prefix string, value interface{}, out map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			flatten(path, child, out)
+		}
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+		out[prefix] = strings.Join(items, ",")
+	case nil:
+		out[prefix] = ""
+	default:
+		out[prefix] = fmt.Sprint(v)
+	}
+}
+
+// lookup returns the raw value of a setting from the environment or config
+// file; empty values count as unset. Secret settings are also read from
+// the file named by <key>_FILE, and secret references are resolved.
+func (l *loader) lookup(key string) (string, bool) {
+	if value, ok := l.pinned[key]; ok {
+		return value, value != ""
+	}
+
+	s := settingsByEnv[key]
+	value, ok := l.source(key, s.secret != nil)
+	if !ok || s.secret == nil {
+		return value, ok
+	}
+
+	resolved, _, err := l.resolver.Resolve(context.Background(), value)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: %w", key, err))
+		return "", false
+	}
+	resolved = strings.TrimSpace(resolved)
+	return resolved, resolved != ""
+}
+
+// source returns the raw value of a setting, in order of precedence: the
+// environment variable, the <key>_FILE environment variable for secrets,
+// then the config file. Environment variables may be set in .env.
+func (l *loader) source(key string, secret bool) (string, bool) {
+	if value, ok := l.env(key); ok && strings.TrimSpace(value) != "" {
+		return strings.TrimSpace(value), true
+	}
+	if path, ok := l.env(key + "_FILE"); ok && secret && strings.TrimSpace(path) != "" {
+		value, err := secrets.FileProvider{}.Secret(context.Background(), strings.TrimSpace(path))
+		if err != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s_FILE: %w", key, err))
+			return "", false
+		}
+		return value, value != ""
+	}
+	if value, ok := l.file[key]; ok && strings.TrimSpace(value) != "" {
+		return strings.TrimSpace(value), true
+	}
+	return "", false
+}
+
+func (l *loader) invalid(key, value, expected string) {
+	l.errs = append(l.errs, fmt.Errorf("%s: invalid value %q, expected %s", key, value, expected))
+}
+
+func (l *loader) getString(key, defaultValue string) string {
+	value, ok := l.lookup(key)
+	if !ok {
+		value = defaultValue
+	}
+	l.effective[key] = value
+	return value
+}
+
+func (l *loader) getInt(key string, defaultValue int) int {
+	valueStr, ok := l.lookup(key)
+	if !ok {
+		l.effective[key] = strconv.Itoa(defaultValue)
+		return defaultValue
+	}
+
+	l.effective[key] = valueStr
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		l.invalid(key, valueStr, "an integer")
+		return defaultValue
+	}
+	return value
+}
+
+func (l *loader) getUint64(key string, defaultValue uint64) uint64 {
+	valueStr, ok := l.lookup(key)
+	if !ok {
+		l.effective[key] = strconv.FormatUint(defaultValue, 10)
+		return defaultValue
+	}
+
+	l.effective[key] = valueStr
+	value, err := strconv.ParseUint(valueStr, 10, 64)
+	if err != nil {
+		l.invalid(key, valueStr, "a non-negative integer")
+		return defaultValue
+	}
+	return value
+}
+
+func (l *loader) getDuration(key string, defaultValue time.Duration) time.Duration {
+	valueStr, ok := l.lookup(key)
+	if !ok {
+		l.effective[key] = defaultValue.String()
+		return defaultValue
+	}
+
+	l.effective[key] = valueStr
+	value, err := time.ParseDuration(valueStr)
+	if err != nil {
+		l.invalid(key, valueStr, "a duration such as 10s or 5m")
+		return defaultValue
+	}
+	return value
+}
+
+// getTime parses a date (2006-01-02) or an RFC 3339 timestamp, in UTC
+func (l *loader) getTime(key string, defaultValue time.Time) time.Time {
+	valueStr, ok := l.lookup(key)
+	if !ok {
+		l.effective[key] = defaultValue.Format(time.RFC3339)
+		return defaultValue
+	}
+
+	l.effective[key] = valueStr
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if value, err := time.Parse(layout, valueStr); err == nil {
+			return value.UTC()
+		}
+	}
+	l.invalid(key, valueStr, "a date such as 2026-01-31 or an RFC 3339 timestamp")
+	return defaultValue
+}
+
+func (l *loader) getBool(key string, defaultValue bool) bool {
+	valueStr, ok := l.lookup(key)
+	if !ok {
+		l.effective[key] = strconv.FormatBool(defaultValue)
+		return defaultValue
+	}
+
+	l.effective[key] = valueStr
+	switch strings.ToLower(valueStr) {
+	case "true", "1", "yes", "y":
+		return true
+	case "false", "0", "no", "n":
+		return false
+	default:
+		l.invalid(key, valueStr, "true or false")
+		return defaultValue
+	}
+}
+
+func (l *loader) getList(key string, defaultValue []string) []string {
+	valueStr, ok := l.lookup(key)
+	if !ok {
+		l.effective[key] = strings.Join(defaultValue, ",")
+		return defaultValue
+	}
+
+	l.effective[key] = valueStr
+	return splitList(valueStr, ",")
+}
+
+// getDurationMap parses "key=duration" items
+func (l *loader) getDurationMap(key string) map[string]time.Duration {
+	values := make(map[string]time.Duration)
+	for _, item := range l.getList(key, nil) {
+		name, durationStr, ok := strings.Cut(item, "=")
+		if !ok {
+			l.invalid(key, item, "path=duration")
+			continue
+		}
+		value, err := time.ParseDuration(strings.TrimSpace(durationStr))
+		if err != nil {
+			l.invalid(key, item, "path=duration")
+			continue
+		}
+		values[strings.TrimSpace(name)] = value
+	}
+	return values
+}
+
+// getCIDRList parses a list of CIDRs or plain IPs
+func (l *loader) getCIDRList(key string, defaultValue []string) []string {
+	entries := l.getList(key, defaultValue)
+	for _, entry := range entries {
+		if _, _, err := net.ParseCIDR(entry); err != nil && net.ParseIP(entry) == nil {
+			l.invalid(key, entry, "an IP address or CIDR")
+		}
+	}
+	return entries
+}
+
+// splitList splits s by sep, trimming whitespace and dropping empty items
+func splitList(s, sep string) []string {
+	var items []string
+	for _, item := range strings.Split(s, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// valueSecret treats the whole value as secret
+func valueSecret(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return []string{value}
+}
+
+// urlSecrets returns a URL and its parts that commonly embed credentials:
+// the password, path segments that look like API keys and the query. Other
+// path segments, such as /ethereum, are left alone so they aren't redacted
+// from unrelated request paths.
+func urlSecrets(value string) []string {
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return valueSecret(value)
+	}
+
+	parts := []string{value}
+	if password, ok := u.User.Password(); ok {
+		parts = append(parts, password)
+	}
+	for _, segment := range strings.Split(u.Path, "/") {
+		if looksLikeKey(segment) {
+			parts = append(parts, segment)
+		}
+	}
+	if u.RawQuery != "" {
+		parts = append(parts, u.RawQuery)
+	}
+	return parts
+}
+
+// looksLikeKey reports whether s looks like a generated API key, such as the
+// hex or base62 project IDs of hosted node providers: long, mixing letters
+// and digits, and with high entropy
+func looksLikeKey(s string) bool {
+	if len(s) < 20 || !strings.ContainsAny(s, "0123456789") {
+		return false
+	}
+
+	counts := make(map[rune]int)
+	letters := false
+	for _, c := range s {
+		counts[c]++
+		letters = letters || unicode.IsLetter(c)
+	}
+	if !letters {
+		return false
+	}
+
+	// Shannon entropy in bits per character
+	entropy := 0.0
+	for _, n := range counts {
+		p := float64(n) / float64(len(s))
+		entropy -= p * math.Log2(p)
+	}
+	return entropy >= 3.5
+}
+
+// urlListSecrets returns the secrets of each URL in a list
+func urlListSecrets(value string) []string {
+	var parts []string
+	for _, u := range splitList(value, ",") {
+		parts = append(parts, urlSecrets(u)...)
+	}
+	return parts
+}
+
+// apiKeySecrets returns the keys of "key:user[:scopes]" entries
+func apiKeySecrets(value string) []string {
+	var keys []string
+	for _, entry := range splitList(value, ",") {
+		key, _, _ := strings.Cut(entry, ":")
+		keys = append(keys, strings.TrimSpace(key))
+	}
+	return keys
+}
+
+// redactAll hides a secret value entirely
+func redactAll(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "[REDACTED]"
+}
+
+// redactURL hides credentials, path and query of a URL, which commonly embed
+// provider API keys, keeping scheme and host for troubleshooting
+func redactURL(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return redactAll(value)
+	}
+
+	redacted := u.Scheme + "://" + u.Host
+	if u.User != nil || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+		redacted += "/[REDACTED]"
+	}
+	return redacted
+}
+
+// redactURLList redacts each URL in a list
+func redactURLList(value string) string {
+	urls := splitList(value, ",")
+	for i, u := range urls {
+		urls[i] = redactURL(u)
+	}
+	return strings.Join(urls, ",")
+}
+
+// redactAPIKeys hides the keys of "key:user[:scopes]" entries
+func redactAPIKeys(value string) string {
+	entries := splitList(value, ",")
+	for i, entry := range entries {
+		if _, rest, ok := strings.Cut(entry, ":"); ok {
+			entries[i] = "[REDACTED]:" + rest
+		} else {
+			entries[i] = "[REDACTED]"
+		}
+	}
+	return strings.Join(entries, ",")
+}
+
+// WriteRedacted writes the effective configuration as YAML in the config file
+// layout, with secrets redacted
+func (c *Config) WriteRedacted(w io.Writer) error {
+	doc := make(map[string]interface{})
+	for _, s := range settings {
+		value := c.effective[s.env]
+		if s.redact != nil {
+			value = s.redact(value)
+		}
+
+		var v interface{} = scalarValue(value)
+		if s.list {
+			items := splitList(value, ",")
+			if items == nil {
+				items = []string{}
+			}
+			v = items
+		}
+
+		// Build the nested document from the dotted path
+		node := doc
+		parts := strings.Split(s.file, ".")
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				node[part] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = v
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// scalarValue converts booleans and integers so they are written unquoted
+func scalarValue(value string) interface{} {
+	if value == "true" || value == "false" {
+		return value == "true"
+	}
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i
+	}
+	return value
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ValidationError lists every invalid setting found while loading configuration
+type ValidationError struct {
+	Errors []error
+}
+
+// Error returns all validation errors, one per line
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString("invalid configuration:")
+	for _, err := range e.Errors {
+		b.WriteString("\n  - ")
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the individual validation errors
+func (e *ValidationError) Unwrap() []error {
+	return e.Errors
+}
+
+// validate checks semantic constraints that parsing alone can't catch
+func (c *Config) validate() []error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	port, err := strconv.Atoi(c.Server.Port)
+	check(err == nil && port > 0 && port <= 65535, "PORT: must be a number between 1 and 65535")
+	grpcPort, err := strconv.Atoi(c.Server.GRPCPort)
+	check(err == nil && grpcPort >= 0 && grpcPort <= 65535, "GRPC_PORT: must be a number between 0 and 65535")
+	check(grpcPort == 0 || grpcPort != port, "GRPC_PORT: must differ from PORT")
+	check(oneOf(c.Server.Mode, "debug", "release", "test"), "GIN_MODE: must be debug, release or test")
+	check(c.Server.ReadTimeout > 0, "SERVER_READ_TIMEOUT: must be positive")
+	check(c.Server.WriteTimeout > 0, "SERVER_WRITE_TIMEOUT: must be positive")
+	check(c.Server.RequestTimeout > 0, "REQUEST_TIMEOUT: must be positive")
+	for path, timeout := range c.Server.RouteTimeouts {
+		check(strings.HasPrefix(path, "/"), "ROUTE_TIMEOUTS: route %q must start with /", path)
+		check(timeout >= 0, "ROUTE_TIMEOUTS: timeout for %q must not be negative", path)
+	}
+	check(c.Server.RateLimit.Limit > 0, "RATE_LIMIT: must be positive")
+	check(c.Server.RateLimit.Window > 0, "RATE_LIMIT_WINDOW: must be positive")
+	check(c.Server.Deprecation.Sunset.IsZero() || !c.Server.Deprecation.Sunset.Before(c.Server.Deprecation.Date),
+		"API_SUNSET_DATE: must not be before API_DEPRECATION_DATE")
+	check(oneOf(c.Server.ClientIP.Header, "Forwarded", "X-Forwarded-For", "X-Real-IP"),
+		"CLIENT_IP_HEADER: must be Forwarded, X-Forwarded-For or X-Real-IP")
+	check(c.Server.CORS.MaxAge >= 0, "CORS_MAX_AGE: must not be negative")
+
+	if jwt := c.Server.Auth.JWT; c.Server.Auth.Enabled && jwt.Enabled {
+		check(jwt.JWKS != "", "AUTH_JWT_JWKS: required when AUTH_JWT_ENABLED is true")
+		check(jwt.UserClaim != "", "AUTH_JWT_USER_CLAIM: must not be empty")
+	}
+
+	check(c.Ethereum.RPCURL != "", "ETHEREUM_RPC_URL: required")
+	check(c.Ethereum.RequestTimeout > 0, "ETHEREUM_REQUEST_TIMEOUT: must be positive")
+	check(c.Ethereum.RetryAttempts >= 0, "ETHEREUM_RETRY_ATTEMPTS: must not be negative")
+	check(c.Ethereum.RetryDelay >= 0, "ETHEREUM_RETRY_DELAY: must not be negative")
+
+	check(c.RPC.CacheTTL >= 0, "RPC_CACHE_TTL: must not be negative")
+	check(c.RPC.MaxBatchSize > 0, "RPC_MAX_BATCH_SIZE: must be positive")
+	check(c.GraphQL.MaxDepth > 0, "GRAPHQL_MAX_DEPTH: must be positive")
+	check(c.GraphQL.MaxCost > 0, "GRAPHQL_MAX_COST: must be positive")
+	check(c.GraphQL.MaxFields > 0, "GRAPHQL_MAX_FIELDS: must be positive")
+	check(c.Logs.ChunkSize > 0, "LOGS_CHUNK_SIZE: must be positive")
+	check(c.Logs.MaxBlockRange >= c.Logs.ChunkSize, "LOGS_MAX_BLOCK_RANGE: must not be less than LOGS_CHUNK_SIZE")
+	check(c.Logs.MaxResults > 0, "LOGS_MAX_RESULTS: must be positive")
+	check(c.Tracking.Confirmations > 0, "TX_CONFIRMATIONS: must be positive")
+	check(c.Tracking.Retention > 0, "TX_TRACKING_RETENTION: must be positive")
+	check(c.Tracking.MaxTracked > 0, "TX_TRACKING_MAX: must be positive")
+	check(c.Tracking.MaxPerClient > 0, "TX_TRACKING_MAX_PER_CLIENT: must be positive")
+
+	check(oneOf(c.Log.Level, "trace", "debug", "info", "warn", "warning", "error", "fatal", "panic"),
+		"LOG_LEVEL: must be one of debug, info, warn, error")
+	check(oneOf(c.Log.Format, "json", "text"), "LOG_FORMAT: must be json or text")
+	check(c.Log.OutputPath != "", "LOG_OUTPUT: must not be empty")
+
+	check(c.Health.CheckTimeout > 0, "HEALTH_CHECK_TIMEOUT: must be positive")
+	check(c.Health.MaxBlockAge >= 0, "HEALTH_MAX_BLOCK_AGE: must not be negative")
+
+	if c.Audit.Enabled {
+		check(c.Audit.FilePath != "", "AUDIT_LOG_PATH: required when AUDIT_ENABLED is true")
+		check(c.Audit.MaxSize >= 0, "AUDIT_LOG_MAX_SIZE_MB: must not be negative")
+		check(c.Audit.DBDSN == "" || oneOf(c.Audit.DBDriver, sql.Drivers()...),
+			"AUDIT_DB_DRIVER: must be one of the drivers compiled into the binary: %s", strings.Join(sql.Drivers(), ", "))
+		check(c.Audit.QueueSize > 0, "AUDIT_QUEUE_SIZE: must be positive")
+	}
+
+	check(c.Reload.WatchInterval >= 0, "CONFIG_WATCH_INTERVAL: must not be negative")
+
+	return errs
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if strings.EqualFold(value, a) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,204 @@
+package config
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Reload metrics, published on /debug/vars
+var (
+	reloadsTotal        = expvar.NewInt("config_reloads_total")
+	reloadFailuresTotal = expvar.NewInt("config_reload_failures_total")
+)
+
+// reloadable lists the settings applied at runtime; changes to any other
+// setting only take effect after a restart
+var reloadable = map[string]bool{
+	"RATE_LIMIT":                 true,
+	"RATE_LIMIT_WINDOW":          true,
+	"RATE_LIMIT_EXEMPT_CIDRS":    true,
+	"IP_ALLOW_CIDRS":             true,
+	"IP_DENY_CIDRS":              true,
+	"CORS_ALLOWED_ORIGINS":       true,
+	"CORS_ALLOWED_METHODS":       true,
+	"CORS_ALLOWED_HEADERS":       true,
+	"CORS_EXPOSED_HEADERS":       true,
+	"CORS_ALLOW_CREDENTIALS":     true,
+	"CORS_MAX_AGE":               true,
+	"AUTH_DEFAULT_SCOPES":        true,
+	"API_KEYS":                   true,
+	"ETHEREUM_RPC_URL":           true,
+	"ETHEREUM_FALLBACK_RPC_URLS": true,
+	"ETHEREUM_REQUEST_TIMEOUT":   true,
+	"ETHEREUM_RETRY_ATTEMPTS":    true,
+	"ETHEREUM_RETRY_DELAY":       true,
+	"LOG_LEVEL":                  true,
+}
+
+// Change describes a setting whose effective value differs between two
+// configurations. Secret values are redacted.
+type Change struct {
+	Setting    string
+	Old        string
+	New        string
+	Reloadable bool // false when the change requires a restart
+}
+
+// Diff returns the settings whose effective values differ from c to other
+func (c *Config) Diff(other *Config) []Change {
+	var changes []Change
+	for _, s := range settings {
+		oldValue, newValue := c.effective[s.env], other.effective[s.env]
+		if oldValue == newValue {
+			continue
+		}
+		if s.redact != nil {
+			oldValue, newValue = s.redact(oldValue), s.redact(newValue)
+		}
+		changes = append(changes, Change{
+			Setting:    s.env,
+			Old:        oldValue,
+			New:        newValue,
+			Reloadable: reloadable[s.env],
+		})
+	}
+	return changes
+}
+
+// running returns the raw running values of the changed settings that
+// require a restart
+func (c *Config) running(changes []Change) map[string]string {
+	values := make(map[string]string)
+	for _, change := range changes {
+		if !change.Reloadable {
+			values[change.Setting] = c.effective[change.Setting]
+		}
+	}
+	return values
+}
+
+// Watcher reloads the configuration on SIGHUP or when the config file
+// changes, and notifies subscribers of the new configuration
+type Watcher struct {
+	current  atomic.Pointer[Config]
+	interval time.Duration
+
+	mu          sync.Mutex // serializes reloads and guards subscribers
+	subscribers []func(cfg *Config, changes []Change)
+}
+
+// NewWatcher creates a watcher starting from cfg. The config file, if any,
+// is polled for changes every interval; zero disables polling.
+func NewWatcher(cfg *Config, interval time.Duration) *Watcher {
+	w := &Watcher{interval: interval}
+	w.current.Store(cfg)
+	return w
+}
+
+// Current returns the most recently loaded configuration
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// Subscribe registers fn to be called with the new configuration and the
+// changed settings after every successful reload
+func (w *Watcher) Subscribe(fn func(cfg *Config, changes []Change)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Reload loads and validates the configuration again. An invalid
+// configuration is rejected and the current one is kept. Subscribers are
+// only notified when a setting changed. Settings that require a restart
+// keep their running values, so they are reported on every reload until
+// the process restarts.
+func (w *Watcher) Reload() ([]Change, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	current := w.current.Load()
+	cfg, err := Load(current.file)
+	if err != nil {
+		reloadFailuresTotal.Add(1)
+		return nil, err
+	}
+	reloadsTotal.Add(1)
+
+	changes := current.Diff(cfg)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	if running := current.running(changes); len(running) > 0 {
+		if cfg, err = load(current.file, running); err != nil {
+			reloadFailuresTotal.Add(1)
+			return nil, err
+		}
+	}
+
+	w.current.Store(cfg)
+	for _, fn := range w.subscribers {
+		fn(cfg, changes)
+	}
+	return changes, nil
+}
+
+// Run reloads the configuration on SIGHUP and whenever the modification
+// time or size of the config file or the .env file changes, until ctx is
+// cancelled. Reload errors are passed to onError.
+func (w *Watcher) Run(ctx context.Context, onError func(error)) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if w.interval > 0 {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	paths := []string{".env", w.Current().file}
+	last := filesVersion(paths)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+		case <-tick:
+			version := filesVersion(paths)
+			if version == last {
+				continue
+			}
+			last = version
+		}
+
+		if _, err := w.Reload(); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+// filesVersion returns the modification times and sizes of paths, skipping
+// those that are empty or cannot be read
+func filesVersion(paths []string) string {
+	var b strings.Builder
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil {
+			fmt.Fprintf(&b, "%s:%d:%d;", path, info.ModTime().UnixNano(), info.Size())
+		}
+	}
+	return b.String()
+}
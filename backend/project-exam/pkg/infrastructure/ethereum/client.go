@@ -2,42 +2,211 @@ package ethereum
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/project-exam/pkg/infrastructure/config"
 )
 
-// Client wraps the Ethereum client with application-specific configuration
+// Client wraps the Ethereum client with application-specific configuration.
+// The underlying connection can be replaced at runtime with Reconnect, and
+// the timeout and retry settings with Configure.
 type Client struct {
-	EthClient  *ethclient.Client
-	Config     *config.EthereumConfig
-	TimeoutCtx func(context.Context) (context.Context, context.CancelFunc)
+	eth       atomic.Pointer[ethclient.Client]
+	fallbacks atomic.Pointer[[]*endpoint]
+	config    atomic.Pointer[config.EthereumConfig]
 }
 
 // NewClient creates a new Ethereum client
 func NewClient(cfg *config.EthereumConfig) (*Client, error) {
+	client, err := dial(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{}
+	c.config.Store(cfg)
+	c.eth.Store(client)
+	c.fallbacks.Store(newEndpoints(cfg.FallbackRPCURLs))
+
+	return c, nil
+}
+
+// dial connects to the Ethereum node
+func dial(cfg *config.EthereumConfig) (*ethclient.Client, error) {
 	// Create a timeout context for connecting to the Ethereum node
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.RequestTimeout)
 	defer cancel()
 
 	// Connect to the Ethereum node
-	client, err := ethclient.DialContext(ctx, cfg.RPCURL)
+	return ethclient.DialContext(ctx, cfg.RPCURL)
+}
+
+// Eth returns the current connection to the Ethereum node
+func (c *Client) Eth() *ethclient.Client {
+	return c.eth.Load()
+}
+
+// Config returns the current configuration
+func (c *Client) Config() *config.EthereumConfig {
+	return c.config.Load()
+}
+
+// TimeoutCtx returns a copy of ctx bounded by the current RequestTimeout
+func (c *Client) TimeoutCtx(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, c.Config().RequestTimeout)
+}
+
+// Configure atomically applies the timeout and retry settings of cfg to new
+// requests, keeping the current connections
+func (c *Client) Configure(cfg *config.EthereumConfig) {
+	c.config.Store(cfg)
+}
+
+// Reconnect dials the node configured in cfg and atomically swaps it in,
+// along with the rest of cfg. The previous connection is closed once
+// in-flight requests have had time to finish; on error the current
+// connection and configuration are kept.
+func (c *Client) Reconnect(cfg *config.EthereumConfig) error {
+	client, err := dial(cfg)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	// In-flight requests are bounded by the previous timeout
+	timeout := c.config.Swap(cfg).RequestTimeout
+
+	old := c.eth.Swap(client)
+	if old != nil {
+		time.AfterFunc(timeout, old.Close)
 	}
 
-	return &Client{
-		EthClient: client,
-		Config:    cfg,
-		TimeoutCtx: func(ctx context.Context) (context.Context, context.CancelFunc) {
-			return context.WithTimeout(ctx, cfg.RequestTimeout)
-		},
-	}, nil
+	oldFallbacks := c.fallbacks.Swap(newEndpoints(cfg.FallbackRPCURLs))
+	time.AfterFunc(timeout, func() { closeEndpoints(oldFallbacks) })
+
+	return nil
 }
 
 // Close closes the connection to the Ethereum client
 func (c *Client) Close() {
-	if c.EthClient != nil {
-		c.EthClient.Close()
+	if client := c.eth.Load(); client != nil {
+		client.Close()
+	}
+	closeEndpoints(c.fallbacks.Load())
+}
+
+// Call performs a raw JSON-RPC call with failover. When a node can't be
+// reached or is rate limiting, the fallback nodes are tried in order; each
+// round over all nodes is retried up to RetryAttempts times, RetryDelay
+// apart. Errors the node answers with are returned without failing over.
+// Every attempt is bounded by RequestTimeout.
+func (c *Client) Call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	cfg := c.Config()
+
+	var err error
+	for round := 0; round <= cfg.RetryAttempts; round++ {
+		if round > 0 {
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(cfg.RetryDelay):
+			}
+		}
+
+		if err = c.call(ctx, c.Eth().Client(), result, method, args...); !shouldFailover(ctx, err) {
+			return err
+		}
+
+		for _, fallback := range *c.fallbacks.Load() {
+			client, dialErr := fallback.dial(ctx, cfg.RequestTimeout)
+			if dialErr != nil {
+				err = dialErr
+				continue
+			}
+			if err = c.call(ctx, client, result, method, args...); !shouldFailover(ctx, err) {
+				return err
+			}
+		}
+	}
+	return err
+}
+
+// call performs a single attempt of a call, bounded by RequestTimeout
+func (c *Client) call(ctx context.Context, client *rpc.Client, result interface{}, method string, args ...interface{}) error {
+	ctx, cancel := c.TimeoutCtx(ctx)
+	defer cancel()
+	return client.CallContext(ctx, result, method, args...)
+}
+
+// errCodeLimitExceeded is the JSON-RPC error code providers use for rate limiting
+const errCodeLimitExceeded = -32005
+
+// shouldFailover reports whether a failed call should be tried on another
+// node: the node was unreachable, timed out or rate limited the request,
+// and the caller is still waiting
+func shouldFailover(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.ErrorCode() == errCodeLimitExceeded
+	}
+	return true
+}
+
+// endpoint is a fallback node, dialled on first use so an unreachable
+// fallback doesn't prevent startup
+type endpoint struct {
+	url    string
+	mu     sync.Mutex
+	client *rpc.Client
+}
+
+// newEndpoints creates the fallback endpoints for urls
+func newEndpoints(urls []string) *[]*endpoint {
+	endpoints := make([]*endpoint, 0, len(urls))
+	for _, url := range urls {
+		endpoints = append(endpoints, &endpoint{url: url})
+	}
+	return &endpoints
+}
+
+// dial returns the endpoint's client, connecting if needed
+func (e *endpoint) dial(ctx context.Context, timeout time.Duration) (*rpc.Client, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.client != nil {
+		return e.client, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, err := rpc.DialContext(ctx, e.url)
+	if err != nil {
+		return nil, err
+	}
+	e.client = client
+	return client, nil
+}
+
+// closeEndpoints closes the connections of dialled endpoints
+func closeEndpoints(endpoints *[]*endpoint) {
+	if endpoints == nil {
+		return
+	}
+	for _, e := range *endpoints {
+		e.mu.Lock()
+		if e.client != nil {
+			e.client.Close()
+		}
+		e.mu.Unlock()
 	}
 }
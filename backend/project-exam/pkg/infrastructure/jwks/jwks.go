@@ -0,0 +1,254 @@
+package jwks
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrKeyNotFound is returned when no key matches the requested key ID
+var ErrKeyNotFound = errors.New("signing key not found")
+
+// KeySet holds the public keys of a JSON Web Key Set loaded from a file or URL
+type KeySet struct {
+	source     string
+	httpClient *http.Client
+	minRefresh time.Duration
+
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey // map[kid]key
+	lastAttempt time.Time                   // start of the last refresh, successful or not
+
+	refreshes singleflight.Group // coalesces refreshes for unknown key IDs
+}
+
+// jsonWebKey is the wire format of a single key in a JWKS document
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// NewKeySet creates a key set from a file path or http(s) URL and loads it once.
+// refreshInterval controls background reloading; zero disables it.
+func NewKeySet(source string, refreshInterval time.Duration) (*KeySet, error) {
+	ks := &KeySet{
+		source:     source,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		minRefresh: 30 * time.Second,
+		keys:       make(map[string]crypto.PublicKey),
+	}
+
+	if err := ks.Refresh(context.Background()); err != nil {
+		return nil, err
+	}
+
+	// Periodically reload keys so rotated keys are picked up
+	if refreshInterval > 0 {
+		go func() {
+			for {
+				time.Sleep(refreshInterval)
+				_ = ks.Refresh(context.Background())
+			}
+		}()
+	}
+
+	return ks, nil
+}
+
+// Key returns the public key with the given key ID. When the key is unknown,
+// the set is reloaded to handle key rotation: at most once per minRefresh,
+// whether or not the reload succeeds, so tokens with made-up key IDs can't
+// flood the source, and concurrent requests share a single reload.
+func (ks *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok := ks.lookup(kid); ok {
+		return key, nil
+	}
+
+	// The reload is shared, so it must not be cancelled with the request that started it
+	ctx = context.WithoutCancel(ctx)
+	_, err, _ := ks.refreshes.Do("", func() (interface{}, error) {
+		ks.mu.RLock()
+		stale := time.Since(ks.lastAttempt) >= ks.minRefresh
+		ks.mu.RUnlock()
+
+		if !stale {
+			return nil, nil
+		}
+		return nil, ks.Refresh(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if key, ok := ks.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+// lookup finds a key by ID; an empty ID matches when the set has a single key
+func (ks *KeySet) lookup(kid string) (crypto.PublicKey, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if kid == "" && len(ks.keys) == 1 {
+		for _, key := range ks.keys {
+			return key, true
+		}
+	}
+
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+// Refresh reloads the key set from its source
+func (ks *KeySet) Refresh(ctx context.Context) error {
+	ks.mu.Lock()
+	ks.lastAttempt = time.Now()
+	ks.mu.Unlock()
+
+	data, err := ks.read(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load JWKS: %w", err)
+	}
+
+	keys, err := parse(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.mu.Unlock()
+
+	return nil
+}
+
+// read fetches the raw JWKS document
+func (ks *KeySet) read(ctx context.Context) ([]byte, error) {
+	if !isURL(ks.source) {
+		return os.ReadFile(ks.source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ks.source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ks.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// parse decodes a JWKS document into public keys, skipping unsupported entries
+func parse(data []byte) (map[string]crypto.PublicKey, error) {
+	var doc struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", jwk.Kid, err)
+		}
+		if key != nil {
+			keys[jwk.Kid] = key
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no usable signing keys")
+	}
+
+	return keys, nil
+}
+
+// publicKey converts the JWK into a Go public key; unsupported key types return nil
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
@@ -0,0 +1,141 @@
+package jwks
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testDocument returns a JWKS document with an RSA key for each key ID
+func testDocument(t *testing.T, kids ...string) []byte {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := make([]map[string]string, len(kids))
+	for i, kid := range kids {
+		keys[i] = map[string]string{
+			"kty": "RSA",
+			"kid": kid,
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}
+	}
+	doc, err := json.Marshal(map[string]interface{}{"keys": keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestKeySetRefreshOnUnknownKey(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		doc     = testDocument(t, "current")
+		failing bool
+		fetches atomic.Int32
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		time.Sleep(20 * time.Millisecond) // keep concurrent lookups waiting on the same fetch
+
+		mu.Lock()
+		defer mu.Unlock()
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write(doc)
+	}))
+	defer server.Close()
+
+	ks, err := NewKeySet(server.URL, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.Key(context.Background(), "current"); err != nil {
+		t.Fatalf("Key(current) error = %v", err)
+	}
+
+	// expire makes the next lookup of an unknown key ID refresh the set
+	expire := func() {
+		ks.mu.Lock()
+		ks.lastAttempt = time.Time{}
+		ks.mu.Unlock()
+	}
+	// lookupConcurrently looks up made-up key IDs at the same time
+	lookupConcurrently := func(n int) []error {
+		errs := make([]error, n)
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = ks.Key(context.Background(), fmt.Sprintf("random-%d", i))
+			}(i)
+		}
+		wg.Wait()
+		return errs
+	}
+
+	t.Run("unknown key IDs within minRefresh don't fetch", func(t *testing.T) {
+		before := fetches.Load()
+		for _, err := range lookupConcurrently(20) {
+			if !errors.Is(err, ErrKeyNotFound) {
+				t.Fatalf("Key() error = %v, want ErrKeyNotFound", err)
+			}
+		}
+		if got := fetches.Load() - before; got != 0 {
+			t.Fatalf("fetches = %d, want 0", got)
+		}
+	})
+
+	t.Run("failed refreshes are rate limited and coalesced", func(t *testing.T) {
+		mu.Lock()
+		failing = true
+		mu.Unlock()
+		expire()
+
+		before := fetches.Load()
+		for _, err := range lookupConcurrently(50) {
+			if err == nil {
+				t.Fatal("Key() of an unknown key ID succeeded")
+			}
+		}
+		lookupConcurrently(50)
+		if got := fetches.Load() - before; got != 1 {
+			t.Fatalf("fetches = %d, want 1", got)
+		}
+
+		// Known keys keep working while the source is down
+		if _, err := ks.Key(context.Background(), "current"); err != nil {
+			t.Fatalf("Key(current) error = %v", err)
+		}
+	})
+
+	t.Run("rotated keys are picked up", func(t *testing.T) {
+		mu.Lock()
+		failing = false
+		doc = testDocument(t, "current", "rotated")
+		mu.Unlock()
+		expire()
+
+		if _, err := ks.Key(context.Background(), "rotated"); err != nil {
+			t.Fatalf("Key(rotated) error = %v", err)
+		}
+	})
+}
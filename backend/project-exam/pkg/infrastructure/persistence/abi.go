@@ -0,0 +1,512 @@
+package persistence
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/domain/repository"
+)
+
+// signatures holds the function signatures of the bundled selector database
+//
+//go:embed abidata/signatures.txt
+var signatures string
+
+// panicSelector is the selector of Panic(uint256), the error the compiler's
+// checks revert with
+var panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+
+// abiRepository implements the ABIRepository interface with go-ethereum's
+// ABI codec. Registered ABIs are kept in memory and, when a directory is
+// configured, in one file per contract so they survive restarts.
+type abiRepository struct {
+	dir       string
+	selectors map[[4]byte][]abi.Method
+
+	mu   sync.RWMutex
+	abis map[string]*entity.ContractABI // by lowercase address
+}
+
+// NewABIRepository creates a new ABIRepository storing registered ABIs in
+// dir, or only in memory if dir is empty. ABIs already in dir are loaded.
+func NewABIRepository(dir string) (repository.ABIRepository, error) {
+	selectors, err := parseSignatures(signatures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load selector database: %w", err)
+	}
+
+	r := &abiRepository{
+		dir:       dir,
+		selectors: selectors,
+		abis:      make(map[string]*entity.ContractABI),
+	}
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create ABI registry directory: %w", err)
+		}
+		if err := r.load(); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// load reads the ABIs stored in the registry directory
+func (r *abiRepository) load() error {
+	paths, err := filepath.Glob(filepath.Join(r.dir, "0x*.json"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		address := strings.TrimSuffix(filepath.Base(path), ".json")
+		if !common.IsHexAddress(address) {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read ABI of %s: %w", address, err)
+		}
+		if _, err := parseABI(data); err != nil {
+			return fmt.Errorf("invalid ABI stored for %s: %w", address, err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+
+		r.abis[address] = &entity.ContractABI{
+			Address:   address,
+			ABI:       data,
+			UpdatedAt: info.ModTime(),
+		}
+	}
+	return nil
+}
+
+// SaveABI stores the ABI of a contract, replacing any previous one
+func (r *abiRepository) SaveABI(ctx context.Context, address string, abiJSON []byte) (*entity.ContractABI, error) {
+	if _, err := parseABI(abiJSON); err != nil {
+		return nil, err
+	}
+
+	address = strings.ToLower(address)
+	contractABI := &entity.ContractABI{
+		Address:   address,
+		ABI:       bytes.TrimSpace(abiJSON),
+		UpdatedAt: time.Now(),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.dir != "" {
+		// Write to a temporary file first so a crash can't leave a partial ABI behind
+		path := filepath.Join(r.dir, address+".json")
+		if err := os.WriteFile(path+".tmp", contractABI.ABI, 0o644); err != nil {
+			return nil, fmt.Errorf("failed to store ABI: %w", err)
+		}
+		if err := os.Rename(path+".tmp", path); err != nil {
+			return nil, fmt.Errorf("failed to store ABI: %w", err)
+		}
+	}
+
+	r.abis[address] = contractABI
+	return contractABI, nil
+}
+
+// GetABI returns the stored ABI of a contract, nil if there is none
+func (r *abiRepository) GetABI(ctx context.Context, address string) (*entity.ContractABI, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.abis[strings.ToLower(address)], nil
+}
+
+// DeleteABI removes the stored ABI of a contract
+func (r *abiRepository) DeleteABI(ctx context.Context, address string) error {
+	address = strings.ToLower(address)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.abis[address]; !ok {
+		return entity.NewError(entity.ErrCodeNotFound, "No ABI registered for this address", nil)
+	}
+	if r.dir != "" {
+		err := os.Remove(filepath.Join(r.dir, address+".json"))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to delete ABI: %w", err)
+		}
+	}
+
+	delete(r.abis, address)
+	return nil
+}
+
+// EventTopics returns the topic identifying each non-anonymous event of an ABI
+func (r *abiRepository) EventTopics(abiJSON []byte) ([]string, error) {
+	parsed, err := parseABI(abiJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	var topics []string
+	for _, event := range parsed.Events {
+		if !event.Anonymous {
+			topics = append(topics, event.ID.Hex())
+		}
+	}
+	return topics, nil
+}
+
+// DecodeLogs decodes logs with the events of an ABI, in order; nil for logs
+// that match no event of the ABI or can't be decoded with it
+func (r *abiRepository) DecodeLogs(abiJSON []byte, logs []entity.Log) ([]*entity.DecodedEvent, error) {
+	parsed, err := parseABI(abiJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*entity.DecodedEvent, len(logs))
+	for i, log := range logs {
+		events[i] = decodeLog(parsed, log)
+	}
+	return events, nil
+}
+
+// DecodeCall decodes call input with the functions of an ABI; nil when no
+// function of the ABI matches the input
+func (r *abiRepository) DecodeCall(abiJSON []byte, input []byte) (*entity.DecodedCall, error) {
+	parsed, err := parseABI(abiJSON)
+	if err != nil {
+		return nil, err
+	}
+	if len(input) < 4 {
+		return nil, nil
+	}
+
+	method, err := parsed.MethodById(input[:4])
+	if err != nil {
+		return nil, nil
+	}
+	values, err := method.Inputs.UnpackValues(input[4:])
+	if err != nil {
+		return nil, nil
+	}
+	return decodedCall(method.RawName, method.Sig, method.Inputs, input, values, entity.ABISourceRegistry), nil
+}
+
+// DecodeCallBySelector decodes call input with the signatures of the
+// selector database that have its selector. Selectors collide, so only a
+// signature whose arguments re-encode to the exact input is accepted.
+func (r *abiRepository) DecodeCallBySelector(input []byte) *entity.DecodedCall {
+	if len(input) < 4 {
+		return nil
+	}
+
+	for _, method := range r.selectors[[4]byte(input[:4])] {
+		values, err := method.Inputs.UnpackValues(input[4:])
+		if err != nil {
+			continue
+		}
+		if packed, err := method.Inputs.Pack(values...); err != nil || !bytes.Equal(packed, input[4:]) {
+			continue
+		}
+		return decodedCall(method.RawName, method.Sig, method.Inputs, input, values, entity.ABISourceSelector)
+	}
+	return nil
+}
+
+// DecodeRevert decodes revert data holding an Error(string), as require and
+// revert with a message raise, or a Panic(uint256), as failed asserts and
+// the compiler's checks raise
+func (r *abiRepository) DecodeRevert(data []byte) *entity.RevertReason {
+	message, err := abi.UnpackRevert(data)
+	if err != nil {
+		return nil
+	}
+
+	reason := &entity.RevertReason{Kind: entity.RevertKindError, Message: message}
+	if bytes.Equal(data[:4], panicSelector) {
+		reason.Kind = entity.RevertKindPanic
+		reason.PanicCode = new(big.Int).SetBytes(data[4:36])
+	}
+	return reason
+}
+
+// DecodeError decodes revert data with the custom errors of an ABI; nil
+// when no error of the ABI matches the data
+func (r *abiRepository) DecodeError(abiJSON []byte, data []byte) (*entity.DecodedCall, error) {
+	parsed, err := parseABI(abiJSON)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 4 {
+		return nil, nil
+	}
+
+	abiErr, err := parsed.ErrorByID([4]byte(data[:4]))
+	if err != nil {
+		return nil, nil
+	}
+	values, err := abiErr.Inputs.UnpackValues(data[4:])
+	if err != nil {
+		return nil, nil
+	}
+	return decodedCall(abiErr.Name, abiErr.Sig, abiErr.Inputs, data, values, entity.ABISourceRegistry), nil
+}
+
+// decodedCall builds the decoded call of input to a function, or the
+// decoded data of an error, from its unpacked argument values
+func decodedCall(name, signature string, inputs abi.Arguments, input []byte, values []interface{}, source entity.ABISource) *entity.DecodedCall {
+	call := &entity.DecodedCall{
+		Name:      name,
+		Signature: signature,
+		Selector:  hexutil.Encode(input[:4]),
+		Args:      make([]entity.DecodedArg, len(inputs)),
+		Source:    source,
+	}
+	for i, arg := range inputs {
+		call.Args[i] = entity.DecodedArg{
+			Name:  arg.Name,
+			Type:  arg.Type.String(),
+			Value: abiValue(arg.Type, values[i]),
+		}
+	}
+	return call
+}
+
+// parseSignatures parses the function signatures of the selector database,
+// indexing them by selector
+func parseSignatures(data string) (map[[4]byte][]abi.Method, error) {
+	selectors := make(map[[4]byte][]abi.Method)
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		method, err := parseSignature(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", line, err)
+		}
+		selector := [4]byte(method.ID)
+		selectors[selector] = append(selectors[selector], method)
+	}
+	return selectors, scanner.Err()
+}
+
+// parseSignature parses a canonical function signature such as
+// transfer(address,uint256). Arguments have no names; tuple components are
+// named arg0, arg1 and so on.
+func parseSignature(signature string) (abi.Method, error) {
+	paren := strings.IndexByte(signature, '(')
+	if paren <= 0 || !strings.HasSuffix(signature, ")") {
+		return abi.Method{}, errors.New("not a function signature")
+	}
+	name := signature[:paren]
+
+	types, err := parseSignatureTypes(signature[paren+1 : len(signature)-1])
+	if err != nil {
+		return abi.Method{}, err
+	}
+
+	inputs := make(abi.Arguments, len(types))
+	for i, marshaling := range types {
+		typ, err := abi.NewType(marshaling.Type, "", marshaling.Components)
+		if err != nil {
+			return abi.Method{}, err
+		}
+		inputs[i] = abi.Argument{Type: typ}
+	}
+
+	method := abi.NewMethod(name, name, abi.Function, "nonpayable", false, false, inputs, nil)
+	if method.Sig != signature {
+		return abi.Method{}, fmt.Errorf("not canonical, expected %s", method.Sig)
+	}
+	return method, nil
+}
+
+// parseSignatureTypes parses the comma-separated argument types of a
+// signature, where tuples are parenthesized lists of types
+func parseSignatureTypes(list string) ([]abi.ArgumentMarshaling, error) {
+	var types []abi.ArgumentMarshaling
+	for len(list) > 0 {
+		// Find the end of the type: the first comma outside parentheses
+		depth, end := 0, len(list)
+		for i, c := range list {
+			if c == '(' {
+				depth++
+			} else if c == ')' {
+				depth--
+			} else if c == ',' && depth == 0 {
+				end = i
+				break
+			}
+		}
+		if depth != 0 {
+			return nil, errors.New("unbalanced parentheses")
+		}
+
+		typ := list[:end]
+		if strings.HasPrefix(typ, "(") {
+			paren := strings.LastIndexByte(typ, ')')
+			components, err := parseSignatureTypes(typ[1:paren])
+			if err != nil {
+				return nil, err
+			}
+			for i := range components {
+				components[i].Name = fmt.Sprintf("arg%d", i)
+			}
+			types = append(types, abi.ArgumentMarshaling{Type: "tuple" + typ[paren+1:], Components: components})
+		} else {
+			types = append(types, abi.ArgumentMarshaling{Type: typ})
+		}
+
+		list = strings.TrimPrefix(list[end:], ",")
+	}
+	return types, nil
+}
+
+// parseABI parses a JSON ABI or a single entry of one
+func parseABI(abiJSON []byte) (*abi.ABI, error) {
+	abiJSON = bytes.TrimSpace(abiJSON)
+	if len(abiJSON) > 0 && abiJSON[0] == '{' {
+		abiJSON = append(append([]byte{'['}, abiJSON...), ']')
+	}
+
+	parsed, err := abi.JSON(bytes.NewReader(abiJSON))
+	if err != nil {
+		return nil, entity.NewError(entity.ErrCodeInvalidRequest, "Invalid ABI: must be a JSON ABI or a single entry of one", err)
+	}
+	return &parsed, nil
+}
+
+// decodeLog decodes a log with the event of the ABI its first topic
+// identifies, or returns nil
+func decodeLog(parsed *abi.ABI, log entity.Log) *entity.DecodedEvent {
+	if len(log.Topics) == 0 {
+		return nil
+	}
+	event, err := parsed.EventByID(common.HexToHash(log.Topics[0]))
+	if err != nil {
+		return nil
+	}
+
+	// The remaining topics hold the indexed arguments, in order
+	topics := log.Topics[1:]
+	indexed := 0
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed++
+		}
+	}
+	if len(topics) != indexed {
+		return nil
+	}
+
+	values, err := event.Inputs.UnpackValues(log.Data)
+	if err != nil {
+		return nil
+	}
+
+	decoded := &entity.DecodedEvent{
+		Name:      event.Name,
+		Signature: event.Sig,
+		Args:      make([]entity.DecodedArg, len(event.Inputs)),
+	}
+	for i, input := range event.Inputs {
+		arg := entity.DecodedArg{
+			Name:    input.Name,
+			Type:    input.Type.String(),
+			Indexed: input.Indexed,
+		}
+		if input.Indexed {
+			value, err := topicValue(input, common.HexToHash(topics[0]))
+			if err != nil {
+				return nil
+			}
+			arg.Value = value
+			topics = topics[1:]
+		} else {
+			arg.Value = abiValue(input.Type, values[0])
+			values = values[1:]
+		}
+		decoded.Args[i] = arg
+	}
+	return decoded
+}
+
+// topicValue decodes an indexed event argument from its topic. Arguments of
+// dynamic types only have their hash in the topic, which is returned as is.
+func topicValue(input abi.Argument, topic common.Hash) (interface{}, error) {
+	switch input.Type.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return topic.Hex(), nil
+	}
+
+	input.Name = "value"
+	out := make(map[string]interface{}, 1)
+	if err := abi.ParseTopicsIntoMap(out, abi.Arguments{input}, []common.Hash{topic}); err != nil {
+		return nil, err
+	}
+	return abiValue(input.Type, out["value"]), nil
+}
+
+// abiValue converts a value decoded by go-ethereum to its JSON-friendly
+// form, described on entity.DecodedArg
+func abiValue(typ abi.Type, value interface{}) interface{} {
+	switch v := value.(type) {
+	case common.Address:
+		return v.Hex()
+	case common.Hash:
+		return v.Hex()
+	case []byte:
+		return hexutil.Encode(v)
+	case string, bool:
+		return v
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(value))
+	switch typ.T {
+	case abi.IntTy, abi.UintTy:
+		return fmt.Sprint(value) // *big.Int or a sized Go integer, both printed in decimal
+	case abi.FixedBytesTy, abi.FunctionTy:
+		b := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+		return hexutil.Encode(b)
+	case abi.SliceTy, abi.ArrayTy:
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = abiValue(*typ.Elem, rv.Index(i).Interface())
+		}
+		return items
+	case abi.TupleTy:
+		fields := make(map[string]interface{}, len(typ.TupleElems))
+		for i, elem := range typ.TupleElems {
+			fields[typ.TupleRawNames[i]] = abiValue(*elem, rv.Field(i).Interface())
+		}
+		return fields
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,334 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/domain/repository"
+)
+
+// fileAuditRepository appends audit events as JSON lines to a file,
+// rotating it once it exceeds maxSize bytes
+type fileAuditRepository struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewFileAuditRepository creates an audit repository writing JSON lines to path.
+// maxSize of zero disables rotation; maxBackups of zero keeps every rotated file.
+func NewFileAuditRepository(path string, maxSize int64, maxBackups int) (repository.AuditRepository, error) {
+	r := &fileAuditRepository{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// open opens the audit file for appending
+func (r *fileAuditRepository) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o750); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat audit log: %w", err)
+	}
+
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// Record appends the event as a single JSON line
+func (r *fileAuditRepository) Record(ctx context.Context, event *entity.AuditEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return errors.New("audit log is closed")
+	}
+
+	if r.maxSize > 0 && r.size+int64(len(line)) > r.maxSize && r.size > 0 {
+		if err := r.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := r.file.Write(line)
+	r.size += int64(n)
+	return err
+}
+
+// rotate renames the current file with a timestamp suffix and starts a new one
+func (r *fileAuditRepository) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close audit log: %w", err)
+	}
+	r.file = nil
+
+	rotated := fmt.Sprintf("%s.%s", r.path, time.Now().UTC().Format("20060102T150405.000000000"))
+	if err := os.Rename(r.path, rotated); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	r.pruneBackups()
+	return nil
+}
+
+// pruneBackups removes the oldest rotated files beyond maxBackups
+func (r *fileAuditRepository) pruneBackups() {
+	if r.maxBackups <= 0 {
+		return
+	}
+
+	backups, err := filepath.Glob(r.path + ".*")
+	if err != nil || len(backups) <= r.maxBackups {
+		return
+	}
+
+	// Timestamp suffixes sort chronologically
+	sort.Strings(backups)
+	for _, backup := range backups[:len(backups)-r.maxBackups] {
+		_ = os.Remove(backup)
+	}
+}
+
+// Close syncs and closes the audit file
+func (r *fileAuditRepository) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+
+	err := r.file.Sync()
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	r.file = nil
+	return err
+}
+
+// sqlAuditRepository inserts audit events into an audit_log table
+type sqlAuditRepository struct {
+	db          *sql.DB
+	insertQuery string
+}
+
+// NewSQLAuditRepository creates an audit repository backed by a database/sql
+// driver. The driver must be registered by the binary (blank import), e.g.
+// "pgx". The audit_log table is created if missing.
+func NewSQLAuditRepository(driver, dsn string) (repository.AuditRepository, error) {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit database: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to audit database: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS audit_log (
+		time        TIMESTAMP    NOT NULL,
+		request_id  VARCHAR(64)  NOT NULL,
+		user_id     VARCHAR(255) NOT NULL,
+		method      VARCHAR(16)  NOT NULL,
+		route       VARCHAR(255) NOT NULL,
+		path        VARCHAR(1024) NOT NULL,
+		addresses   TEXT,
+		status      INTEGER      NOT NULL,
+		latency_ms  BIGINT       NOT NULL,
+		client_ip   VARCHAR(64)  NOT NULL
+	)`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create audit_log table: %w", err)
+	}
+
+	// PostgreSQL drivers use numbered placeholders
+	placeholders := "?, ?, ?, ?, ?, ?, ?, ?, ?, ?"
+	if driver == "postgres" || driver == "pgx" {
+		placeholders = "$1, $2, $3, $4, $5, $6, $7, $8, $9, $10"
+	}
+
+	return &sqlAuditRepository{
+		db: db,
+		insertQuery: `INSERT INTO audit_log
+			(time, request_id, user_id, method, route, path, addresses, status, latency_ms, client_ip)
+			VALUES (` + placeholders + `)`,
+	}, nil
+}
+
+// Record inserts the event as a new row
+func (r *sqlAuditRepository) Record(ctx context.Context, event *entity.AuditEvent) error {
+	_, err := r.db.ExecContext(ctx, r.insertQuery,
+		event.Time.UTC(),
+		event.RequestID,
+		event.UserID,
+		event.Method,
+		event.Route,
+		event.Path,
+		sql.NullString{String: strings.Join(event.Addresses, ","), Valid: len(event.Addresses) > 0},
+		event.Status,
+		event.Latency.Milliseconds(),
+		event.ClientIP,
+	)
+	return err
+}
+
+// Close closes the database connection pool
+func (r *sqlAuditRepository) Close() error {
+	return r.db.Close()
+}
+
+// multiAuditRepository fans audit events out to several repositories
+type multiAuditRepository struct {
+	repos []repository.AuditRepository
+}
+
+// NewMultiAuditRepository creates an audit repository recording to every given repository
+func NewMultiAuditRepository(repos ...repository.AuditRepository) repository.AuditRepository {
+	return &multiAuditRepository{repos: repos}
+}
+
+// Record records the event in every repository, returning all failures
+func (r *multiAuditRepository) Record(ctx context.Context, event *entity.AuditEvent) error {
+	var errs []error
+	for _, repo := range r.repos {
+		if err := repo.Record(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes every repository
+func (r *multiAuditRepository) Close() error {
+	var errs []error
+	for _, repo := range r.repos {
+		if err := repo.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ErrAuditQueueFull is returned when an event is dropped because the
+// background writer can't keep up
+var ErrAuditQueueFull = errors.New("audit queue is full")
+
+// auditWriteTimeout bounds a single background write
+const auditWriteTimeout = 5 * time.Second
+
+// asyncAuditRepository queues audit events for a background writer, so slow
+// sinks such as a database don't hold up requests
+type asyncAuditRepository struct {
+	repo    repository.AuditRepository
+	onError func(event *entity.AuditEvent, err error)
+
+	mu     sync.RWMutex // guards sends on queue against Close
+	queue  chan *entity.AuditEvent
+	closed bool
+	done   chan struct{}
+}
+
+// NewAsyncAuditRepository creates an audit repository queueing up to
+// queueSize events for repo. Events that arrive while the queue is full are
+// dropped with ErrAuditQueueFull; failed background writes are passed to
+// onError.
+func NewAsyncAuditRepository(repo repository.AuditRepository, queueSize int, onError func(event *entity.AuditEvent, err error)) repository.AuditRepository {
+	r := &asyncAuditRepository{
+		repo:    repo,
+		onError: onError,
+		queue:   make(chan *entity.AuditEvent, queueSize),
+		done:    make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// run writes queued events until the queue is closed
+func (r *asyncAuditRepository) run() {
+	defer close(r.done)
+
+	for event := range r.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), auditWriteTimeout)
+		if err := r.repo.Record(ctx, event); err != nil {
+			r.onError(event, err)
+		}
+		cancel()
+	}
+}
+
+// Record queues the event without waiting for it to be written
+func (r *asyncAuditRepository) Record(_ context.Context, event *entity.AuditEvent) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		return errors.New("audit log is closed")
+	}
+
+	select {
+	case r.queue <- event:
+		return nil
+	default:
+		return ErrAuditQueueFull
+	}
+}
+
+// Close writes the queued events and closes the underlying repository
+func (r *asyncAuditRepository) Close() error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.queue)
+	}
+	r.mu.Unlock()
+
+	<-r.done
+	return r.repo.Close()
+}
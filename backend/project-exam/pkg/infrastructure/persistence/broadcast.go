@@ -0,0 +1,93 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/project-exam/pkg/domain/entity"
+)
+
+// JSON-RPC error codes nodes reject transactions with: geth's generic server
+// error, used for transaction pool errors, and EIP-1474's transaction rejected
+const (
+	rpcCodeServerError         = -32000
+	rpcCodeTransactionRejected = -32003
+)
+
+// DecodeTransaction decodes a signed raw transaction, in its typed (EIP-2718)
+// or legacy RLP encoding, and recovers its sender
+func (r *ethereumRepository) DecodeTransaction(raw []byte) (*entity.SignedTransaction, error) {
+	var tx types.Transaction
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, entity.NewError(entity.ErrCodeInvalidRequest, "Invalid raw transaction: not a signed transaction encoding", err)
+	}
+
+	// The transaction's own chain ID selects the signer; it is checked against
+	// the network separately. Unprotected legacy transactions have none.
+	protected := tx.Protected() && tx.ChainId().Sign() > 0
+	signer := types.Signer(types.HomesteadSigner{})
+	if protected {
+		signer = types.LatestSignerForChainID(tx.ChainId())
+	} else if tx.Type() != types.LegacyTxType {
+		return nil, entity.NewError(entity.ErrCodeInvalidRequest, "Invalid raw transaction: chain ID must not be 0", nil)
+	}
+	from, err := types.Sender(signer, &tx)
+	if err != nil {
+		return nil, entity.NewError(entity.ErrCodeInvalidRequest, "Invalid raw transaction: invalid signature", err)
+	}
+
+	signed := &entity.SignedTransaction{
+		Transaction: entity.Transaction{
+			Hash:     tx.Hash().Hex(),
+			Type:     uint64(tx.Type()),
+			From:     from.Hex(),
+			Nonce:    tx.Nonce(),
+			Value:    tx.Value(),
+			Gas:      tx.Gas(),
+			GasPrice: tx.GasPrice(),
+			Input:    tx.Data(),
+		},
+		Cost: tx.Cost(),
+		Raw:  raw,
+	}
+	if tx.To() != nil {
+		signed.To = tx.To().Hex()
+	}
+	if tx.Type() >= types.DynamicFeeTxType {
+		signed.MaxFeePerGas = tx.GasFeeCap()
+		signed.MaxPriorityFeePerGas = tx.GasTipCap()
+	}
+	if protected {
+		signed.ChainID = tx.ChainId()
+	}
+	return signed, nil
+}
+
+// SendTransaction submits a signed transaction to the node's transaction
+// pool. Transactions the pool already holds are accepted again, so clients
+// can retry a submission safely.
+func (r *ethereumRepository) SendTransaction(ctx context.Context, tx *entity.SignedTransaction) error {
+	var signed types.Transaction
+	if err := signed.UnmarshalBinary(tx.Raw); err != nil {
+		return entity.NewError(entity.ErrCodeInvalidRequest, "Invalid raw transaction: not a signed transaction encoding", err)
+	}
+
+	ctx, cancel := r.client.TimeoutCtx(ctx)
+	defer cancel()
+
+	err := r.client.Eth().SendTransaction(ctx, &signed)
+
+	// Pool rejections, such as underpriced replacements, are the client's to fix
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && (rpcErr.ErrorCode() == rpcCodeServerError || rpcErr.ErrorCode() == rpcCodeTransactionRejected) {
+		if strings.Contains(rpcErr.Error(), "already known") {
+			return nil
+		}
+		return entity.NewError(entity.ErrCodeInvalidRequest, "Transaction rejected by the Ethereum node: "+rpcErr.Error(), err)
+	}
+	return upstreamError(err)
+}
@@ -0,0 +1,353 @@
+package persistence
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/project-exam/pkg/domain/entity"
+)
+
+// maxBatchSize is the largest JSON-RPC batch sent to the node; larger
+// requests are split, as providers limit batch sizes
+const maxBatchSize = 100
+
+// batchCall sends one JSON-RPC call per element in batches. Element errors
+// are left in the elements for the caller to inspect.
+func (r *ethereumRepository) batchCall(ctx context.Context, elems []rpc.BatchElem) error {
+	for start := 0; start < len(elems); start += maxBatchSize {
+		end := min(start+maxBatchSize, len(elems))
+
+		callCtx, cancel := r.client.TimeoutCtx(ctx)
+		err := r.client.Eth().Client().BatchCallContext(callCtx, elems[start:end])
+		cancel()
+		if err != nil {
+			return upstreamError(err)
+		}
+	}
+	return nil
+}
+
+// batchCallStrict is batchCall failing on the first element error
+func (r *ethereumRepository) batchCallStrict(ctx context.Context, elems []rpc.BatchElem) error {
+	if err := r.batchCall(ctx, elems); err != nil {
+		return err
+	}
+	for _, elem := range elems {
+		if elem.Error != nil {
+			return upstreamError(elem.Error)
+		}
+	}
+	return nil
+}
+
+// GetBalances returns the latest balances of addresses, in order
+func (r *ethereumRepository) GetBalances(ctx context.Context, addresses []string) ([]*big.Int, error) {
+	return r.balancesAt(ctx, addresses, "latest")
+}
+
+// balancesAt returns the balances of addresses at a block given as an
+// eth_getBalance block parameter, in order
+func (r *ethereumRepository) balancesAt(ctx context.Context, addresses []string, block interface{}) ([]*big.Int, error) {
+	results := make([]hexutil.Big, len(addresses))
+	elems := make([]rpc.BatchElem, len(addresses))
+	for i, address := range addresses {
+		elems[i] = rpc.BatchElem{Method: "eth_getBalance", Args: []interface{}{common.HexToAddress(address), block}, Result: &results[i]}
+	}
+	if err := r.batchCallStrict(ctx, elems); err != nil {
+		return nil, err
+	}
+
+	balances := make([]*big.Int, len(results))
+	for i := range results {
+		balances[i] = results[i].ToInt()
+	}
+	return balances, nil
+}
+
+// GetNonces returns the latest nonces of addresses, in order
+func (r *ethereumRepository) GetNonces(ctx context.Context, addresses []string) ([]uint64, error) {
+	results := make([]hexutil.Uint64, len(addresses))
+	elems := make([]rpc.BatchElem, len(addresses))
+	for i, address := range addresses {
+		elems[i] = rpc.BatchElem{Method: "eth_getTransactionCount", Args: []interface{}{common.HexToAddress(address), "latest"}, Result: &results[i]}
+	}
+	if err := r.batchCallStrict(ctx, elems); err != nil {
+		return nil, err
+	}
+
+	nonces := make([]uint64, len(results))
+	for i := range results {
+		nonces[i] = uint64(results[i])
+	}
+	return nonces, nil
+}
+
+// GetCodes returns the code at addresses, in order; empty for accounts without code
+func (r *ethereumRepository) GetCodes(ctx context.Context, addresses []string) ([][]byte, error) {
+	results := make([]hexutil.Bytes, len(addresses))
+	elems := make([]rpc.BatchElem, len(addresses))
+	for i, address := range addresses {
+		elems[i] = rpc.BatchElem{Method: "eth_getCode", Args: []interface{}{common.HexToAddress(address), "latest"}, Result: &results[i]}
+	}
+	if err := r.batchCallStrict(ctx, elems); err != nil {
+		return nil, err
+	}
+
+	codes := make([][]byte, len(results))
+	for i := range results {
+		codes[i] = results[i]
+	}
+	return codes, nil
+}
+
+// rpcBlock is a block as returned by eth_getBlockBy*, with transaction hashes
+type rpcBlock struct {
+	Number       hexutil.Uint64 `json:"number"`
+	Hash         common.Hash    `json:"hash"`
+	ParentHash   common.Hash    `json:"parentHash"`
+	Timestamp    hexutil.Uint64 `json:"timestamp"`
+	Miner        common.Address `json:"miner"`
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	GasLimit     hexutil.Uint64 `json:"gasLimit"`
+	BaseFee      *hexutil.Big   `json:"baseFeePerGas"`
+	Transactions []common.Hash  `json:"transactions"`
+}
+
+// GetBlocksByNumber returns blocks by number, in order; nil for blocks that don't exist yet
+func (r *ethereumRepository) GetBlocksByNumber(ctx context.Context, numbers []uint64) ([]*entity.Block, error) {
+	args := make([][]interface{}, len(numbers))
+	for i, number := range numbers {
+		args[i] = []interface{}{hexutil.Uint64(number), false}
+	}
+	return r.getBlocks(ctx, "eth_getBlockByNumber", args)
+}
+
+// GetBlocksByHash returns blocks by hash, in order; nil for unknown blocks
+func (r *ethereumRepository) GetBlocksByHash(ctx context.Context, hashes []string) ([]*entity.Block, error) {
+	args := make([][]interface{}, len(hashes))
+	for i, hash := range hashes {
+		args[i] = []interface{}{common.HexToHash(hash), false}
+	}
+	return r.getBlocks(ctx, "eth_getBlockByHash", args)
+}
+
+// getBlocks fetches blocks with the given method, one call per argument list
+func (r *ethereumRepository) getBlocks(ctx context.Context, method string, args [][]interface{}) ([]*entity.Block, error) {
+	results := make([]*rpcBlock, len(args))
+	elems := make([]rpc.BatchElem, len(args))
+	for i := range args {
+		elems[i] = rpc.BatchElem{Method: method, Args: args[i], Result: &results[i]}
+	}
+	if err := r.batchCallStrict(ctx, elems); err != nil {
+		return nil, err
+	}
+
+	blocks := make([]*entity.Block, len(results))
+	for i, b := range results {
+		if b == nil {
+			continue
+		}
+		blocks[i] = &entity.Block{
+			Number:       uint64(b.Number),
+			Hash:         b.Hash.Hex(),
+			ParentHash:   b.ParentHash.Hex(),
+			Timestamp:    time.Unix(int64(b.Timestamp), 0),
+			Miner:        b.Miner.Hex(),
+			GasUsed:      uint64(b.GasUsed),
+			GasLimit:     uint64(b.GasLimit),
+			BaseFee:      b.BaseFee.ToInt(),
+			Transactions: make([]string, len(b.Transactions)),
+		}
+		for j, hash := range b.Transactions {
+			blocks[i].Transactions[j] = hash.Hex()
+		}
+	}
+	return blocks, nil
+}
+
+// rpcTransaction is a transaction as returned by eth_getTransactionByHash.
+// It is decoded field by field so transaction types unknown to go-ethereum,
+// such as those of layer 2 networks, can still be read.
+type rpcTransaction struct {
+	Hash                 common.Hash     `json:"hash"`
+	Type                 hexutil.Uint64  `json:"type"`
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Value                *hexutil.Big    `json:"value"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Input                hexutil.Bytes   `json:"input"`
+	BlockHash            *common.Hash    `json:"blockHash"`
+	BlockNumber          *hexutil.Uint64 `json:"blockNumber"`
+	TransactionIndex     *hexutil.Uint64 `json:"transactionIndex"`
+}
+
+// GetTransactions returns transactions by hash, in order; nil for unknown transactions
+func (r *ethereumRepository) GetTransactions(ctx context.Context, hashes []string) ([]*entity.Transaction, error) {
+	results := make([]*rpcTransaction, len(hashes))
+	elems := make([]rpc.BatchElem, len(hashes))
+	for i, hash := range hashes {
+		elems[i] = rpc.BatchElem{Method: "eth_getTransactionByHash", Args: []interface{}{common.HexToHash(hash)}, Result: &results[i]}
+	}
+	if err := r.batchCallStrict(ctx, elems); err != nil {
+		return nil, err
+	}
+
+	txs := make([]*entity.Transaction, len(results))
+	for i, tx := range results {
+		if tx == nil {
+			continue
+		}
+		txs[i] = &entity.Transaction{
+			Hash:                 tx.Hash.Hex(),
+			Type:                 uint64(tx.Type),
+			From:                 tx.From.Hex(),
+			Nonce:                uint64(tx.Nonce),
+			Value:                bigOrZero(tx.Value),
+			Gas:                  uint64(tx.Gas),
+			GasPrice:             tx.GasPrice.ToInt(),
+			MaxFeePerGas:         tx.MaxFeePerGas.ToInt(),
+			MaxPriorityFeePerGas: tx.MaxPriorityFeePerGas.ToInt(),
+			Input:                tx.Input,
+		}
+		if tx.To != nil {
+			txs[i].To = tx.To.Hex()
+		}
+		if tx.BlockHash != nil && tx.BlockNumber != nil {
+			number, index := uint64(*tx.BlockNumber), uint64(0)
+			if tx.TransactionIndex != nil {
+				index = uint64(*tx.TransactionIndex)
+			}
+			txs[i].BlockHash = tx.BlockHash.Hex()
+			txs[i].BlockNumber = &number
+			txs[i].Index = &index
+		}
+	}
+	return txs, nil
+}
+
+// rpcReceipt is a receipt as returned by eth_getTransactionReceipt
+type rpcReceipt struct {
+	TransactionHash   common.Hash     `json:"transactionHash"`
+	BlockHash         common.Hash     `json:"blockHash"`
+	BlockNumber       hexutil.Uint64  `json:"blockNumber"`
+	Status            hexutil.Uint64  `json:"status"`
+	GasUsed           hexutil.Uint64  `json:"gasUsed"`
+	CumulativeGasUsed hexutil.Uint64  `json:"cumulativeGasUsed"`
+	EffectiveGasPrice *hexutil.Big    `json:"effectiveGasPrice"`
+	ContractAddress   *common.Address `json:"contractAddress"`
+	Logs              []rpcLog        `json:"logs"`
+}
+
+// rpcLog is a log as returned in receipts and by eth_getLogs
+type rpcLog struct {
+	Address          common.Address `json:"address"`
+	Topics           []common.Hash  `json:"topics"`
+	Data             hexutil.Bytes  `json:"data"`
+	BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+	BlockHash        common.Hash    `json:"blockHash"`
+	TransactionHash  common.Hash    `json:"transactionHash"`
+	TransactionIndex hexutil.Uint64 `json:"transactionIndex"`
+	Index            hexutil.Uint64 `json:"logIndex"`
+	Removed          bool           `json:"removed"`
+}
+
+// entity converts the log to a domain log
+func (l rpcLog) entity() entity.Log {
+	topics := make([]string, len(l.Topics))
+	for i, topic := range l.Topics {
+		topics[i] = topic.Hex()
+	}
+	return entity.Log{
+		Address:          l.Address.Hex(),
+		Topics:           topics,
+		Data:             l.Data,
+		BlockNumber:      uint64(l.BlockNumber),
+		BlockHash:        l.BlockHash.Hex(),
+		TransactionHash:  l.TransactionHash.Hex(),
+		TransactionIndex: uint64(l.TransactionIndex),
+		Index:            uint64(l.Index),
+		Removed:          l.Removed,
+	}
+}
+
+// GetReceipts returns the receipts of transactions by hash, in order; nil
+// for transactions that aren't mined
+func (r *ethereumRepository) GetReceipts(ctx context.Context, hashes []string) ([]*entity.Receipt, error) {
+	results := make([]*rpcReceipt, len(hashes))
+	elems := make([]rpc.BatchElem, len(hashes))
+	for i, hash := range hashes {
+		elems[i] = rpc.BatchElem{Method: "eth_getTransactionReceipt", Args: []interface{}{common.HexToHash(hash)}, Result: &results[i]}
+	}
+	if err := r.batchCallStrict(ctx, elems); err != nil {
+		return nil, err
+	}
+
+	receipts := make([]*entity.Receipt, len(results))
+	for i, receipt := range results {
+		if receipt == nil {
+			continue
+		}
+		receipts[i] = &entity.Receipt{
+			TransactionHash:   receipt.TransactionHash.Hex(),
+			BlockHash:         receipt.BlockHash.Hex(),
+			BlockNumber:       uint64(receipt.BlockNumber),
+			Status:            uint64(receipt.Status),
+			GasUsed:           uint64(receipt.GasUsed),
+			CumulativeGasUsed: uint64(receipt.CumulativeGasUsed),
+			EffectiveGasPrice: receipt.EffectiveGasPrice.ToInt(),
+			Logs:              make([]entity.Log, len(receipt.Logs)),
+		}
+		if receipt.ContractAddress != nil {
+			receipts[i].ContractAddress = receipt.ContractAddress.Hex()
+		}
+		for j, log := range receipt.Logs {
+			receipts[i].Logs[j] = log.entity()
+		}
+	}
+	return receipts, nil
+}
+
+// CallContracts performs read-only calls at the latest block. A call that
+// reverts fails on its own, in its result.
+func (r *ethereumRepository) CallContracts(ctx context.Context, calls []entity.ContractCall) ([]entity.CallResult, error) {
+	results := make([]hexutil.Bytes, len(calls))
+	elems := make([]rpc.BatchElem, len(calls))
+	for i, call := range calls {
+		to := common.HexToAddress(call.To)
+		elems[i] = rpc.BatchElem{
+			Method: "eth_call",
+			Args:   []interface{}{map[string]interface{}{"to": to, "data": hexutil.Bytes(call.Data)}, "latest"},
+			Result: &results[i],
+		}
+	}
+	if err := r.batchCall(ctx, elems); err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.CallResult, len(calls))
+	for i, elem := range elems {
+		if elem.Error != nil {
+			out[i].Err = upstreamError(elem.Error)
+			continue
+		}
+		out[i].Output = results[i]
+	}
+	return out, nil
+}
+
+// bigOrZero returns the value of a decoded big integer, or zero when absent
+func bigOrZero(b *hexutil.Big) *big.Int {
+	if b == nil {
+		return new(big.Int)
+	}
+	return b.ToInt()
+}
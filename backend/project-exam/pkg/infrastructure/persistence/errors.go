@@ -0,0 +1,57 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	goethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/project-exam/pkg/domain/entity"
+)
+
+// upstreamError classifies an error returned by the Ethereum node into a
+// domain error. Messages are generic so internal RPC URLs are never exposed;
+// the original error is kept as the wrapped cause for logging.
+func upstreamError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var domainErr *entity.Error
+	if errors.As(err, &domainErr) {
+		return err
+	}
+
+	if errors.Is(err, goethereum.NotFound) {
+		return entity.NewError(entity.ErrCodeNotFound, "Resource not found", err)
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return entity.NewError(entity.ErrCodeUpstreamTimeout, "Ethereum node request timed out", err)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return entity.NewError(entity.ErrCodeUpstreamTimeout, "Ethereum node request timed out", err)
+	}
+
+	// The node answered with a JSON-RPC error
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return entity.NewError(entity.ErrCodeUpstreamError, "Ethereum node returned an error", err)
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		if httpErr.StatusCode == http.StatusRequestTimeout || httpErr.StatusCode == http.StatusGatewayTimeout {
+			return entity.NewError(entity.ErrCodeUpstreamTimeout, "Ethereum node request timed out", err)
+		}
+		return entity.NewError(entity.ErrCodeUpstreamUnavailable, "Ethereum node is unavailable", err)
+	}
+
+	// Anything else is a transport failure (connection refused, DNS, TLS, ...)
+	return entity.NewError(entity.ErrCodeUpstreamUnavailable, "Ethereum node is unavailable", err)
+}
@@ -2,11 +2,14 @@ package persistence
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/project-exam/pkg/domain/entity"
 	"github.com/project-exam/pkg/domain/repository"
@@ -30,7 +33,8 @@ func (r *ethereumRepository) GetGasPrice(ctx context.Context) (*big.Int, error)
 	ctx, cancel := r.client.TimeoutCtx(ctx)
 	defer cancel()
 
-	return r.client.EthClient.SuggestGasPrice(ctx)
+	gasPrice, err := r.client.Eth().SuggestGasPrice(ctx)
+	return gasPrice, upstreamError(err)
 }
 
 // GetCurrentBlock returns the latest block number
@@ -38,7 +42,8 @@ func (r *ethereumRepository) GetCurrentBlock(ctx context.Context) (uint64, error
 	ctx, cancel := r.client.TimeoutCtx(ctx)
 	defer cancel()
 
-	return r.client.EthClient.BlockNumber(ctx)
+	blockNumber, err := r.client.Eth().BlockNumber(ctx)
+	return blockNumber, upstreamError(err)
 }
 
 // GetAddressBalance returns the balance for the given address
@@ -47,7 +52,177 @@ func (r *ethereumRepository) GetAddressBalance(ctx context.Context, address stri
 	defer cancel()
 
 	ethAddress := common.HexToAddress(address)
-	return r.client.EthClient.BalanceAt(ctx, ethAddress, nil) // nil = latest block
+	balance, err := r.client.Eth().BalanceAt(ctx, ethAddress, nil) // nil = latest block
+	return balance, upstreamError(err)
+}
+
+// GetNonce returns the number of transactions mined from the given address
+func (r *ethereumRepository) GetNonce(ctx context.Context, address string) (uint64, error) {
+	ctx, cancel := r.client.TimeoutCtx(ctx)
+	defer cancel()
+
+	nonce, err := r.client.Eth().NonceAt(ctx, common.HexToAddress(address), nil) // nil = latest block
+	return nonce, upstreamError(err)
+}
+
+// GetPendingNonce returns the number of transactions from the given address,
+// including those pending in the node's pool
+func (r *ethereumRepository) GetPendingNonce(ctx context.Context, address string) (uint64, error) {
+	ctx, cancel := r.client.TimeoutCtx(ctx)
+	defer cancel()
+
+	nonce, err := r.client.Eth().PendingNonceAt(ctx, common.HexToAddress(address))
+	return nonce, upstreamError(err)
+}
+
+// GetCode returns the code at the given address, empty for accounts without code
+func (r *ethereumRepository) GetCode(ctx context.Context, address string) ([]byte, error) {
+	ctx, cancel := r.client.TimeoutCtx(ctx)
+	defer cancel()
+
+	code, err := r.client.Eth().CodeAt(ctx, common.HexToAddress(address), nil) // nil = latest block
+	return code, upstreamError(err)
+}
+
+// GetStorageAt returns the 32-byte value of a storage slot of the given address
+func (r *ethereumRepository) GetStorageAt(ctx context.Context, address, slot string) ([]byte, error) {
+	ctx, cancel := r.client.TimeoutCtx(ctx)
+	defer cancel()
+
+	value, err := r.client.Eth().StorageAt(ctx, common.HexToAddress(address), common.HexToHash(slot), nil) // nil = latest block
+	return value, upstreamError(err)
+}
+
+// GetChainID returns the chain ID of the connected network
+func (r *ethereumRepository) GetChainID(ctx context.Context) (*big.Int, error) {
+	ctx, cancel := r.client.TimeoutCtx(ctx)
+	defer cancel()
+
+	chainID, err := r.client.Eth().ChainID(ctx)
+	return chainID, upstreamError(err)
+}
+
+// GetSyncProgress returns the node's sync progress, or nil when it is fully synced
+func (r *ethereumRepository) GetSyncProgress(ctx context.Context) (*entity.SyncProgress, error) {
+	ctx, cancel := r.client.TimeoutCtx(ctx)
+	defer cancel()
+
+	progress, err := r.client.Eth().SyncProgress(ctx)
+	if err != nil {
+		return nil, upstreamError(err)
+	}
+	if progress == nil {
+		return nil, nil
+	}
+
+	return &entity.SyncProgress{
+		CurrentBlock: progress.CurrentBlock,
+		HighestBlock: progress.HighestBlock,
+	}, nil
+}
+
+// GetLatestHeader returns the header of the latest block
+func (r *ethereumRepository) GetLatestHeader(ctx context.Context) (*entity.BlockHeader, error) {
+	ctx, cancel := r.client.TimeoutCtx(ctx)
+	defer cancel()
+
+	header, err := r.client.Eth().HeaderByNumber(ctx, nil) // nil = latest block
+	if err != nil {
+		return nil, upstreamError(err)
+	}
+
+	return blockHeader(header), nil
+}
+
+// headPollInterval is how often the head block is polled when the node
+// doesn't support subscriptions, e.g. when connected over HTTP
+const headPollInterval = 2 * time.Second
+
+// SubscribeNewHeads streams the header of every new head block until ctx is
+// cancelled. Nodes connected over WebSocket or IPC push new headers; other
+// nodes are polled. The channel is closed when the stream ends.
+func (r *ethereumRepository) SubscribeNewHeads(ctx context.Context) (<-chan *entity.BlockHeader, error) {
+	headers := make(chan *types.Header, 16)
+	sub, err := r.client.Eth().SubscribeNewHead(ctx, headers)
+	if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+		return r.pollNewHeads(ctx), nil
+	}
+	if err != nil {
+		return nil, upstreamError(err)
+	}
+
+	out := make(chan *entity.BlockHeader)
+	go func() {
+		defer close(out)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sub.Err():
+				// The connection was lost; clients resubscribe
+				return
+			case header := <-headers:
+				select {
+				case out <- blockHeader(header):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// pollNewHeads emits the latest header whenever the head block number
+// advances. Failed polls are retried on the next tick.
+func (r *ethereumRepository) pollNewHeads(ctx context.Context) <-chan *entity.BlockHeader {
+	out := make(chan *entity.BlockHeader)
+	go func() {
+		defer close(out)
+
+		ticker := time.NewTicker(headPollInterval)
+		defer ticker.Stop()
+
+		var last uint64
+		if header, err := r.GetLatestHeader(ctx); err == nil {
+			last = header.Number
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			header, err := r.GetLatestHeader(ctx)
+			if err != nil || header.Number <= last {
+				continue
+			}
+			last = header.Number
+
+			select {
+			case out <- header:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// blockHeader converts a go-ethereum header to a domain block header
+func blockHeader(header *types.Header) *entity.BlockHeader {
+	return &entity.BlockHeader{
+		Number:    header.Number.Uint64(),
+		Hash:      header.Hash().Hex(),
+		Timestamp: time.Unix(int64(header.Time), 0),
+		BaseFee:   header.BaseFee,
+	}
 }
 
 // GetAddressInfo retrieves all required information for an address in a single call
@@ -97,7 +272,7 @@ func (r *ethereumRepository) GetAddressInfo(ctx context.Context, address string)
 	for i := 0; i < 3; i++ {
 		select {
 		case <-ctx.Done():
-			return nil, fmt.Errorf("context cancelled: %w", ctx.Err())
+			return nil, upstreamError(ctx.Err())
 		case err := <-errCh:
 			return nil, err
 		case gasPrice = <-gasPriceCh:
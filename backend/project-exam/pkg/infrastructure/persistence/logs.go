@@ -0,0 +1,67 @@
+package persistence
+
+import (
+	"context"
+	"math/big"
+
+	goethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/project-exam/pkg/domain/entity"
+)
+
+// FilterLogs returns the logs matching a filter in a single eth_getLogs
+// call. Block ranges must be given by number.
+func (r *ethereumRepository) FilterLogs(ctx context.Context, filter entity.LogFilter) ([]entity.Log, error) {
+	query := goethereum.FilterQuery{
+		Addresses: make([]common.Address, len(filter.Addresses)),
+		Topics:    make([][]common.Hash, len(filter.Topics)),
+	}
+	for i, address := range filter.Addresses {
+		query.Addresses[i] = common.HexToAddress(address)
+	}
+	for i, alternatives := range filter.Topics {
+		for _, topic := range alternatives {
+			query.Topics[i] = append(query.Topics[i], common.HexToHash(topic))
+		}
+	}
+	if filter.BlockHash != "" {
+		hash := common.HexToHash(filter.BlockHash)
+		query.BlockHash = &hash
+	} else {
+		if filter.FromBlock.Number != nil {
+			query.FromBlock = new(big.Int).SetUint64(*filter.FromBlock.Number)
+		}
+		if filter.ToBlock.Number != nil {
+			query.ToBlock = new(big.Int).SetUint64(*filter.ToBlock.Number)
+		}
+	}
+
+	ctx, cancel := r.client.TimeoutCtx(ctx)
+	defer cancel()
+
+	logs, err := r.client.Eth().FilterLogs(ctx, query)
+	if err != nil {
+		return nil, upstreamError(err)
+	}
+
+	result := make([]entity.Log, len(logs))
+	for i, log := range logs {
+		topics := make([]string, len(log.Topics))
+		for j, topic := range log.Topics {
+			topics[j] = topic.Hex()
+		}
+		result[i] = entity.Log{
+			Address:          log.Address.Hex(),
+			Topics:           topics,
+			Data:             log.Data,
+			BlockNumber:      log.BlockNumber,
+			BlockHash:        log.BlockHash.Hex(),
+			TransactionHash:  log.TxHash.Hex(),
+			TransactionIndex: uint64(log.TxIndex),
+			Index:            uint64(log.Index),
+			Removed:          log.Removed,
+		}
+	}
+	return result, nil
+}
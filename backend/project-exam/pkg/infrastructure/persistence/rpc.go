@@ -0,0 +1,53 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/domain/repository"
+	"github.com/project-exam/pkg/infrastructure/ethereum"
+)
+
+// rpcRepository implements the RPCRepository interface
+type rpcRepository struct {
+	client *ethereum.Client
+}
+
+// NewRPCRepository creates a new RPCRepository. Calls fail over to the
+// client's fallback nodes.
+func NewRPCRepository(client *ethereum.Client) repository.RPCRepository {
+	return &rpcRepository{
+		client: client,
+	}
+}
+
+// Call performs a JSON-RPC call with failover and returns its raw result
+func (r *rpcRepository) Call(ctx context.Context, method string, params []json.RawMessage) (json.RawMessage, error) {
+	args := make([]interface{}, len(params))
+	for i, param := range params {
+		args[i] = param
+	}
+
+	var result json.RawMessage
+	err := r.client.Call(ctx, &result, method, args...)
+	if err == nil {
+		return result, nil
+	}
+
+	// Pass errors answered by the node through to the caller unchanged
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		rpcError := &entity.RPCError{Code: rpcErr.ErrorCode(), Message: rpcErr.Error()}
+		var dataErr rpc.DataError
+		if errors.As(err, &dataErr) {
+			rpcError.Data = dataErr.ErrorData()
+		}
+		return nil, rpcError
+	}
+
+	return nil, upstreamError(err)
+}
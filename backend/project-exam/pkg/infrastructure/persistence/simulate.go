@@ -0,0 +1,310 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/project-exam/pkg/domain/entity"
+)
+
+// rpcCodeExecutionReverted is the JSON-RPC error code of calls that revert
+// with data, which the error carries
+const rpcCodeExecutionReverted = 3
+
+// rpcAccountOverride is an account of the state override set of eth_call
+type rpcAccountOverride struct {
+	Nonce     *hexutil.Uint64              `json:"nonce,omitempty"`
+	Code      *hexutil.Bytes               `json:"code,omitempty"`
+	Balance   *hexutil.Big                 `json:"balance,omitempty"`
+	State     *map[common.Hash]common.Hash `json:"state,omitempty"` // a pointer, as an empty state clears the storage
+	StateDiff map[common.Hash]common.Hash  `json:"stateDiff,omitempty"`
+}
+
+// rpcBlockOverrides is the block override set of eth_call
+type rpcBlockOverrides struct {
+	Number       *hexutil.Big    `json:"number,omitempty"`
+	Time         *hexutil.Uint64 `json:"time,omitempty"`
+	GasLimit     *hexutil.Uint64 `json:"gasLimit,omitempty"`
+	FeeRecipient *common.Address `json:"feeRecipient,omitempty"`
+	PrevRandao   *common.Hash    `json:"prevRandao,omitempty"`
+	BaseFee      *hexutil.Big    `json:"baseFeePerGas,omitempty"`
+}
+
+// rpcCallFrame is a call of a debug_traceCall result with the callTracer
+type rpcCallFrame struct {
+	Type    string          `json:"type"`
+	From    common.Address  `json:"from"`
+	To      *common.Address `json:"to"`
+	Value   *hexutil.Big    `json:"value"`
+	GasUsed hexutil.Uint64  `json:"gasUsed"`
+	Error   string          `json:"error"`
+	Calls   []rpcCallFrame  `json:"calls"`
+}
+
+// SimulateTransaction executes a transaction with eth_call on the state of
+// the selected block. A debug_traceCall in the same batch tells the gas it
+// uses and the value its internal calls move; nodes without the debug API
+// only give a gas estimate of transactions that succeed.
+func (r *ethereumRepository) SimulateTransaction(ctx context.Context, sim *entity.Simulation) (*entity.SimulationExecution, error) {
+	header, err := r.headerAt(ctx, sim.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	args := callArgs(sim)
+	block := rpc.BlockNumberOrHashWithHash(header.Hash(), false)
+	stateOverrides := stateOverrides(sim.StateOverrides)
+	blockOverrides := blockOverrides(sim.BlockOverrides)
+
+	// eth_call takes the override sets as optional trailing parameters, and
+	// debug_traceCall in its tracer config
+	callParams := []interface{}{args, block}
+	traceConfig := map[string]interface{}{"tracer": "callTracer"}
+	if stateOverrides != nil || blockOverrides != nil {
+		callParams = append(callParams, stateOverrides)
+	}
+	if stateOverrides != nil {
+		traceConfig["stateOverrides"] = stateOverrides
+	}
+	if blockOverrides != nil {
+		callParams = append(callParams, blockOverrides)
+		traceConfig["blockOverrides"] = blockOverrides
+	}
+
+	var output hexutil.Bytes
+	var trace rpcCallFrame
+	elems := []rpc.BatchElem{
+		{Method: "eth_call", Args: callParams, Result: &output},
+		{Method: "debug_traceCall", Args: []interface{}{args, block, traceConfig}, Result: &trace},
+	}
+	if err := r.batchCall(ctx, elems); err != nil {
+		return nil, err
+	}
+
+	exec := &entity.SimulationExecution{
+		Block:        blockHeader(header),
+		From:         common.HexToAddress(sim.From).Hex(),
+		FeeRecipient: header.Coinbase.Hex(),
+		BaseFee:      header.BaseFee,
+		Output:       output,
+	}
+	if blockOverrides != nil {
+		if blockOverrides.FeeRecipient != nil {
+			exec.FeeRecipient = blockOverrides.FeeRecipient.Hex()
+		}
+		if blockOverrides.BaseFee != nil {
+			exec.BaseFee = blockOverrides.BaseFee.ToInt()
+		}
+	}
+	if err := elems[0].Error; err != nil && !executionFailed(err, exec) {
+		return nil, upstreamError(err)
+	}
+
+	if elems[1].Error == nil {
+		gasUsed := uint64(trace.GasUsed)
+		exec.GasUsed = &gasUsed
+		exec.Traced = true
+		exec.Transfers = trace.transfers(nil)
+		exec.Called = trace.called(make(map[common.Address]bool), nil)
+		return exec, nil
+	}
+
+	// Without a trace, only the transaction's own value transfer is known
+	if exec.Error == "" {
+		if sim.To != "" && sim.Value != nil && sim.Value.Sign() > 0 {
+			exec.Transfers = []entity.ValueTransfer{{
+				From:  exec.From,
+				To:    common.HexToAddress(sim.To).Hex(),
+				Value: sim.Value,
+			}}
+		}
+		if gas, err := r.estimateGas(ctx, callParams); err == nil {
+			exec.GasUsed = &gas
+		}
+	}
+	return exec, nil
+}
+
+// estimateGas estimates the gas of a transaction with eth_estimateGas, which
+// takes the same parameters as eth_call
+func (r *ethereumRepository) estimateGas(ctx context.Context, params []interface{}) (uint64, error) {
+	ctx, cancel := r.client.TimeoutCtx(ctx)
+	defer cancel()
+
+	var gas hexutil.Uint64
+	if err := r.client.Eth().Client().CallContext(ctx, &gas, "eth_estimateGas", params...); err != nil {
+		return 0, upstreamError(err)
+	}
+	return uint64(gas), nil
+}
+
+// executionFailed reports whether an eth_call error is the transaction
+// failing to execute, such as a revert or running out of gas, rather than
+// the node failing, and records the failure in exec
+func executionFailed(err error, exec *entity.SimulationExecution) bool {
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+
+	switch rpcErr.ErrorCode() {
+	case rpcCodeExecutionReverted:
+		exec.Reverted = true
+	case rpcCodeServerError:
+		// Reverts without data and failed pre-checks, e.g. insufficient funds
+		exec.Reverted = strings.HasPrefix(rpcErr.Error(), "execution reverted")
+	default:
+		return false
+	}
+	exec.Error = rpcErr.Error()
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			exec.Output, _ = hexutil.Decode(data)
+		}
+	}
+	return true
+}
+
+// transfers appends the value moved by a call and its subcalls to
+// transfers. Failed calls had their transfers reverted with them.
+func (f *rpcCallFrame) transfers(transfers []entity.ValueTransfer) []entity.ValueTransfer {
+	if f.Error != "" {
+		return transfers
+	}
+
+	// Delegate calls and callcode run other code with the caller's own balance
+	switch f.Type {
+	case "CALL", "CREATE", "CREATE2", "SELFDESTRUCT":
+		if value := bigOrZero(f.Value); value.Sign() > 0 && f.To != nil {
+			transfers = append(transfers, entity.ValueTransfer{From: f.From.Hex(), To: f.To.Hex(), Value: value})
+		}
+	}
+	for i := range f.Calls {
+		transfers = f.Calls[i].transfers(transfers)
+	}
+	return transfers
+}
+
+// called appends the addresses a call and its subcalls called to called,
+// each once, in call order
+func (f *rpcCallFrame) called(seen map[common.Address]bool, called []string) []string {
+	if f.To != nil && f.Type != "SELFDESTRUCT" && !seen[*f.To] {
+		seen[*f.To] = true
+		called = append(called, f.To.Hex())
+	}
+	for i := range f.Calls {
+		called = f.Calls[i].called(seen, called)
+	}
+	return called
+}
+
+// callArgs builds the transaction object of eth_call for a simulation
+func callArgs(sim *entity.Simulation) map[string]interface{} {
+	args := map[string]interface{}{"from": common.HexToAddress(sim.From)}
+	if sim.To != "" {
+		args["to"] = common.HexToAddress(sim.To)
+	}
+	if len(sim.Data) > 0 {
+		args["data"] = hexutil.Bytes(sim.Data)
+	}
+	if sim.Value != nil {
+		args["value"] = (*hexutil.Big)(sim.Value)
+	}
+	if sim.Gas > 0 {
+		args["gas"] = hexutil.Uint64(sim.Gas)
+	}
+	if sim.GasPrice != nil {
+		args["gasPrice"] = (*hexutil.Big)(sim.GasPrice)
+	}
+	if sim.MaxFeePerGas != nil {
+		args["maxFeePerGas"] = (*hexutil.Big)(sim.MaxFeePerGas)
+	}
+	if sim.MaxPriorityFeePerGas != nil {
+		args["maxPriorityFeePerGas"] = (*hexutil.Big)(sim.MaxPriorityFeePerGas)
+	}
+	return args
+}
+
+// stateOverrides builds the state override set of eth_call, nil without overrides
+func stateOverrides(overrides map[string]*entity.AccountOverride) map[common.Address]*rpcAccountOverride {
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	out := make(map[common.Address]*rpcAccountOverride, len(overrides))
+	for address, override := range overrides {
+		account := &rpcAccountOverride{
+			Balance:   (*hexutil.Big)(override.Balance),
+			StateDiff: storageOverride(override.StateDiff),
+		}
+		if override.Nonce != nil {
+			nonce := hexutil.Uint64(*override.Nonce)
+			account.Nonce = &nonce
+		}
+		if override.Code != nil {
+			code := hexutil.Bytes(override.Code)
+			account.Code = &code
+		}
+		if override.State != nil {
+			state := storageOverride(override.State)
+			if state == nil {
+				state = map[common.Hash]common.Hash{}
+			}
+			account.State = &state
+		}
+		out[common.HexToAddress(address)] = account
+	}
+	return out
+}
+
+// storageOverride converts storage slots and values to hashes, nil for none
+func storageOverride(slots map[string]string) map[common.Hash]common.Hash {
+	if len(slots) == 0 {
+		return nil
+	}
+
+	out := make(map[common.Hash]common.Hash, len(slots))
+	for slot, value := range slots {
+		out[common.HexToHash(slot)] = common.HexToHash(value)
+	}
+	return out
+}
+
+// blockOverrides builds the block override set of eth_call, nil without overrides
+func blockOverrides(overrides *entity.BlockOverrides) *rpcBlockOverrides {
+	if overrides == nil {
+		return nil
+	}
+
+	out := &rpcBlockOverrides{
+		BaseFee: (*hexutil.Big)(overrides.BaseFee),
+	}
+	if overrides.Number != nil {
+		out.Number = (*hexutil.Big)(new(big.Int).SetUint64(*overrides.Number))
+	}
+	if overrides.Time != nil {
+		t := hexutil.Uint64(*overrides.Time)
+		out.Time = &t
+	}
+	if overrides.GasLimit != nil {
+		gasLimit := hexutil.Uint64(*overrides.GasLimit)
+		out.GasLimit = &gasLimit
+	}
+	if overrides.FeeRecipient != "" {
+		feeRecipient := common.HexToAddress(overrides.FeeRecipient)
+		out.FeeRecipient = &feeRecipient
+	}
+	if overrides.PrevRandao != "" {
+		prevRandao := common.HexToHash(overrides.PrevRandao)
+		out.PrevRandao = &prevRandao
+	}
+	return out
+}
@@ -0,0 +1,252 @@
+package persistence
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	goethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+
+	"github.com/project-exam/pkg/domain/entity"
+)
+
+// blockTagNumbers maps block tags to the block numbers the client encodes them as
+var blockTagNumbers = map[entity.BlockTag]rpc.BlockNumber{
+	entity.BlockTagLatest:    rpc.LatestBlockNumber,
+	entity.BlockTagSafe:      rpc.SafeBlockNumber,
+	entity.BlockTagFinalized: rpc.FinalizedBlockNumber,
+	entity.BlockTagEarliest:  rpc.EarliestBlockNumber,
+}
+
+// headerAt returns the header of the selected block. State is then read at
+// the block's hash, so a new head block can't change the block read from.
+func (r *ethereumRepository) headerAt(ctx context.Context, block entity.BlockSelector) (*types.Header, error) {
+	ctx, cancel := r.client.TimeoutCtx(ctx)
+	defer cancel()
+
+	var header *types.Header
+	var err error
+	switch {
+	case block.Hash != "":
+		header, err = r.client.Eth().HeaderByHash(ctx, common.HexToHash(block.Hash))
+	case block.Number != nil:
+		header, err = r.client.Eth().HeaderByNumber(ctx, new(big.Int).SetUint64(*block.Number))
+	default:
+		number, ok := blockTagNumbers[block.Tag]
+		if !ok {
+			number = rpc.LatestBlockNumber
+		}
+		header, err = r.client.Eth().HeaderByNumber(ctx, big.NewInt(number.Int64()))
+	}
+	if errors.Is(err, goethereum.NotFound) {
+		return nil, entity.NewError(entity.ErrCodeNotFound, "Block not found", err)
+	}
+	return header, upstreamError(err)
+}
+
+// GetBlockHeader returns the header of the selected block
+func (r *ethereumRepository) GetBlockHeader(ctx context.Context, block entity.BlockSelector) (*entity.BlockHeader, error) {
+	header, err := r.headerAt(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	return blockHeader(header), nil
+}
+
+// GetStorageAtBlock returns the value of a storage slot of the given address
+// at the selected block
+func (r *ethereumRepository) GetStorageAtBlock(ctx context.Context, address, slot string, block entity.BlockSelector) (*entity.StorageSlot, error) {
+	header, err := r.headerAt(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := r.client.TimeoutCtx(ctx)
+	defer cancel()
+
+	value, err := r.client.Eth().StorageAtHash(ctx, common.HexToAddress(address), common.HexToHash(slot), header.Hash())
+	if err != nil {
+		return nil, upstreamError(err)
+	}
+
+	return &entity.StorageSlot{
+		Address: address,
+		Slot:    slot,
+		Value:   common.BytesToHash(value).Bytes(),
+		Block:   blockHeader(header),
+	}, nil
+}
+
+// GetBalancesAtBlock returns the balances of addresses at the selected
+// block, in order. Blocks not selected by hash are resolved to one first, so
+// every balance is read at the same block.
+func (r *ethereumRepository) GetBalancesAtBlock(ctx context.Context, addresses []string, block entity.BlockSelector) ([]*big.Int, error) {
+	hash := common.HexToHash(block.Hash)
+	if block.Hash == "" {
+		header, err := r.headerAt(ctx, block)
+		if err != nil {
+			return nil, err
+		}
+		hash = header.Hash()
+	}
+	return r.balancesAt(ctx, addresses, rpc.BlockNumberOrHashWithHash(hash, false))
+}
+
+// rpcAccountProof is the result of eth_getProof
+type rpcAccountProof struct {
+	Balance      *hexutil.Big      `json:"balance"`
+	Nonce        hexutil.Uint64    `json:"nonce"`
+	CodeHash     common.Hash       `json:"codeHash"`
+	StorageHash  common.Hash       `json:"storageHash"`
+	AccountProof []hexutil.Bytes   `json:"accountProof"`
+	StorageProof []rpcStorageProof `json:"storageProof"`
+}
+
+// rpcStorageProof is the proof of a storage slot in an eth_getProof result
+type rpcStorageProof struct {
+	Value *hexutil.Big    `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
+// GetProof returns the Merkle proof of an account and storage slots at the
+// selected block, verified against the block's state root. Proofs that
+// don't verify fail with an upstream error.
+func (r *ethereumRepository) GetProof(ctx context.Context, address string, slots []string, block entity.BlockSelector) (*entity.AccountProof, error) {
+	header, err := r.headerAt(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]common.Hash, len(slots))
+	for i, slot := range slots {
+		keys[i] = common.HexToHash(slot)
+	}
+
+	callCtx, cancel := r.client.TimeoutCtx(ctx)
+	defer cancel()
+
+	var result rpcAccountProof
+	err = r.client.Eth().Client().CallContext(callCtx, &result, "eth_getProof",
+		common.HexToAddress(address), keys, rpc.BlockNumberOrHashWithHash(header.Hash(), false))
+	if err != nil {
+		return nil, upstreamError(err)
+	}
+	if len(result.StorageProof) != len(keys) {
+		return nil, entity.NewError(entity.ErrCodeUpstreamError, "Ethereum node returned an invalid proof",
+			fmt.Errorf("got %d storage proofs for %d slots", len(result.StorageProof), len(keys)))
+	}
+
+	if err := verifyAccountProof(header.Root, common.HexToAddress(address), keys, &result); err != nil {
+		return nil, entity.NewError(entity.ErrCodeUpstreamError, "Ethereum node returned an invalid proof", err)
+	}
+
+	proof := &entity.AccountProof{
+		Address:       address,
+		Block:         blockHeader(header),
+		StateRoot:     header.Root.Hex(),
+		Balance:       bigOrZero(result.Balance),
+		Nonce:         uint64(result.Nonce),
+		CodeHash:      result.CodeHash.Hex(),
+		StorageHash:   result.StorageHash.Hex(),
+		AccountProof:  proofNodes(result.AccountProof),
+		StorageProofs: make([]entity.StorageProof, len(keys)),
+		Verified:      true,
+	}
+	for i, storageProof := range result.StorageProof {
+		proof.StorageProofs[i] = entity.StorageProof{
+			Slot:  slots[i],
+			Value: common.BigToHash(bigOrZero(storageProof.Value)).Bytes(),
+			Proof: proofNodes(storageProof.Proof),
+		}
+	}
+	return proof, nil
+}
+
+// verifyAccountProof checks that the account and storage values of an
+// eth_getProof result are proven by its trie nodes, starting from stateRoot
+func verifyAccountProof(stateRoot common.Hash, address common.Address, keys []common.Hash, result *rpcAccountProof) error {
+	encoded, err := trie.VerifyProof(stateRoot, crypto.Keccak256(address.Bytes()), proofDB(result.AccountProof))
+	if err != nil {
+		return fmt.Errorf("account proof: %w", err)
+	}
+
+	balance := bigOrZero(result.Balance)
+	if encoded == nil {
+		// The account doesn't exist. Nodes report its code and storage
+		// hashes either as zero or as those of empty code and storage.
+		if result.Nonce != 0 || balance.Sign() != 0 ||
+			(result.CodeHash != types.EmptyCodeHash && result.CodeHash != (common.Hash{})) ||
+			(result.StorageHash != types.EmptyRootHash && result.StorageHash != (common.Hash{})) {
+			return errors.New("account proof: proven absent account has state")
+		}
+	} else {
+		account, err := types.FullAccount(encoded)
+		if err != nil {
+			return fmt.Errorf("account proof: %w", err)
+		}
+		if account.Nonce != uint64(result.Nonce) || account.Balance.ToBig().Cmp(balance) != 0 ||
+			!bytes.Equal(account.CodeHash, result.CodeHash.Bytes()) || account.Root != result.StorageHash {
+			return errors.New("account proof: proven account differs from the reported one")
+		}
+	}
+
+	for i, storageProof := range result.StorageProof {
+		value := bigOrZero(storageProof.Value)
+
+		// Accounts without storage have no storage trie to prove against
+		if result.StorageHash == types.EmptyRootHash || result.StorageHash == (common.Hash{}) {
+			if value.Sign() != 0 {
+				return fmt.Errorf("storage proof of slot %s: value set in empty storage", keys[i].Hex())
+			}
+			continue
+		}
+
+		encoded, err := trie.VerifyProof(result.StorageHash, crypto.Keccak256(keys[i].Bytes()), proofDB(storageProof.Proof))
+		if err != nil {
+			return fmt.Errorf("storage proof of slot %s: %w", keys[i].Hex(), err)
+		}
+
+		// Slots are stored as RLP strings without leading zeros; absent slots are zero
+		proven := new(big.Int)
+		if encoded != nil {
+			var content []byte
+			if err := rlp.DecodeBytes(encoded, &content); err != nil {
+				return fmt.Errorf("storage proof of slot %s: %w", keys[i].Hex(), err)
+			}
+			proven.SetBytes(content)
+		}
+		if proven.Cmp(value) != 0 {
+			return fmt.Errorf("storage proof of slot %s: proven value differs from the reported one", keys[i].Hex())
+		}
+	}
+	return nil
+}
+
+// proofDB indexes the trie nodes of a proof by hash, as trie.VerifyProof
+// looks them up
+func proofDB(nodes []hexutil.Bytes) *memorydb.Database {
+	db := memorydb.New()
+	for _, node := range nodes {
+		_ = db.Put(crypto.Keccak256(node), node) // memorydb only fails once closed
+	}
+	return db
+}
+
+// proofNodes converts the trie nodes of a proof to byte slices
+func proofNodes(nodes []hexutil.Bytes) [][]byte {
+	out := make([][]byte, len(nodes))
+	for i, node := range nodes {
+		out[i] = node
+	}
+	return out
+}
@@ -0,0 +1,169 @@
+package persistence
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// proofFixture is an eth_getProof result recorded from a geth node, with the
+// address and slot keys it was requested for
+type proofFixture struct {
+	rpcAccountProof
+	Address      common.Address `json:"address"`
+	StorageProof []struct {
+		rpcStorageProof
+		Key string `json:"key"` // as requested, e.g. "0x1"
+	} `json:"storageProof"`
+}
+
+// loadProofs reads the state root and the eth_getProof results of
+// testdata/proofs.json: a contract with storage, including a slot that is
+// unset, an account without storage and an absent account
+func loadProofs(t *testing.T) (common.Hash, map[string]*proofFixture) {
+	t.Helper()
+
+	data, err := os.ReadFile("testdata/proofs.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fixture struct {
+		StateRoot common.Hash              `json:"stateRoot"`
+		Proofs    map[string]*proofFixture `json:"proofs"`
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		t.Fatal(err)
+	}
+	return fixture.StateRoot, fixture.Proofs
+}
+
+// result returns a copy of the fixture's eth_getProof result that can be
+// tampered with, and the slot keys it proves
+func (f *proofFixture) result() (*rpcAccountProof, []common.Hash) {
+	result := f.rpcAccountProof
+	result.Balance = (*hexutil.Big)(new(big.Int).Set(f.Balance.ToInt()))
+
+	keys := make([]common.Hash, len(f.StorageProof))
+	result.StorageProof = make([]rpcStorageProof, len(f.StorageProof))
+	for i, storageProof := range f.StorageProof {
+		keys[i] = common.HexToHash(storageProof.Key)
+		result.StorageProof[i] = rpcStorageProof{
+			Value: (*hexutil.Big)(new(big.Int).Set(storageProof.Value.ToInt())),
+			Proof: storageProof.Proof,
+		}
+	}
+	return &result, keys
+}
+
+func TestVerifyAccountProof(t *testing.T) {
+	stateRoot, proofs := loadProofs(t)
+
+	tests := []struct {
+		name    string
+		account string
+		address common.Address // address the proof is checked for, if not the fixture's
+		tamper  func(result *rpcAccountProof)
+		wantErr bool
+	}{
+		{name: "valid proof", account: "contract"},
+		{name: "account without storage", account: "eoa"},
+		{name: "absent account", account: "absent"},
+
+		// Account fields
+		{
+			name:    "tampered balance",
+			account: "contract",
+			tamper: func(result *rpcAccountProof) {
+				result.Balance.ToInt().Add(result.Balance.ToInt(), big.NewInt(1))
+			},
+			wantErr: true,
+		},
+		{
+			name:    "tampered nonce",
+			account: "eoa",
+			tamper:  func(result *rpcAccountProof) { result.Nonce++ },
+			wantErr: true,
+		},
+		{
+			name:    "tampered storage hash",
+			account: "contract",
+			tamper:  func(result *rpcAccountProof) { result.StorageHash = types.EmptyRootHash },
+			wantErr: true,
+		},
+		{
+			name:    "proof of another address",
+			account: "eoa",
+			address: proofs["contract"].Address,
+			wantErr: true,
+		},
+		{
+			name:    "tampered account proof",
+			account: "contract",
+			tamper: func(result *rpcAccountProof) {
+				result.AccountProof = result.AccountProof[:len(result.AccountProof)-1]
+			},
+			wantErr: true,
+		},
+
+		// Storage values
+		{
+			name:    "tampered storage value",
+			account: "contract",
+			tamper: func(result *rpcAccountProof) {
+				result.StorageProof[0].Value = (*hexutil.Big)(big.NewInt(0x3ea))
+			},
+			wantErr: true,
+		},
+		{
+			name:    "value reported for an unset slot",
+			account: "contract",
+			tamper: func(result *rpcAccountProof) {
+				result.StorageProof[2].Value = (*hexutil.Big)(big.NewInt(1))
+			},
+			wantErr: true,
+		},
+		{
+			name:    "value reported in empty storage",
+			account: "eoa",
+			tamper: func(result *rpcAccountProof) {
+				result.StorageProof[0].Value = (*hexutil.Big)(big.NewInt(1))
+			},
+			wantErr: true,
+		},
+		{
+			name:    "absent account reported with a balance",
+			account: "absent",
+			tamper: func(result *rpcAccountProof) {
+				result.Balance = (*hexutil.Big)(big.NewInt(1))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := proofs[tt.account]
+			result, keys := fixture.result()
+			if tt.tamper != nil {
+				tt.tamper(result)
+			}
+			address := fixture.Address
+			if tt.address != (common.Address{}) {
+				address = tt.address
+			}
+
+			err := verifyAccountProof(stateRoot, address, keys, result)
+			if tt.wantErr && err == nil {
+				t.Fatal("verifyAccountProof() succeeded, want an error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("verifyAccountProof() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrNotFound is returned when a referenced secret does not exist
+var ErrNotFound = errors.New("secret not found")
+
+// Provider looks up secrets by name. The meaning of the name depends on the
+// provider: a file path, an environment variable or a Vault path.
+type Provider interface {
+	Secret(ctx context.Context, name string) (string, error)
+}
+
+// Resolver resolves secret references of the form scheme://name using the
+// provider registered for the scheme, e.g. file:///run/secrets/rpc_url,
+// env://INFURA_URL or vault://secret/data/ethereum#rpcUrl
+type Resolver struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+}
+
+// NewResolver creates a resolver with the file:// and env:// providers registered
+func NewResolver() *Resolver {
+	r := &Resolver{providers: make(map[string]Provider)}
+	r.Register("file", FileProvider{})
+	r.Register("env", EnvProvider{})
+	return r
+}
+
+// Register sets the provider used for references with the given scheme
+func (r *Resolver) Register(scheme string, provider Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[scheme] = provider
+}
+
+// Resolve returns the secret value referenced by value. Values that are not
+// references to a registered scheme are returned unchanged with ok false.
+func (r *Resolver) Resolve(ctx context.Context, value string) (secret string, ok bool, err error) {
+	scheme, name, found := strings.Cut(value, "://")
+	if !found {
+		return value, false, nil
+	}
+
+	r.mu.RLock()
+	provider, registered := r.providers[scheme]
+	r.mu.RUnlock()
+	if !registered {
+		return value, false, nil
+	}
+
+	secret, err = provider.Secret(ctx, name)
+	if err != nil {
+		return "", true, fmt.Errorf("failed to resolve %s:// secret: %w", scheme, err)
+	}
+	return secret, true, nil
+}
+
+// FileProvider reads secrets from files, such as Docker or Kubernetes secret
+// mounts. Surrounding whitespace, including a trailing newline, is trimmed.
+type FileProvider struct{}
+
+// Secret returns the trimmed contents of the file at path
+func (FileProvider) Secret(_ context.Context, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: file %s", ErrNotFound, path)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// EnvProvider reads secrets from other environment variables
+type EnvProvider struct {
+	Fallback map[string]string // variables used when unset in the environment, e.g. from a .env file
+}
+
+// Secret returns the value of the environment variable name
+func (p EnvProvider) Secret(_ context.Context, name string) (string, error) {
+	if value, ok := os.LookupEnv(name); ok {
+		return value, nil
+	}
+	if value, ok := p.Fallback[name]; ok {
+		return value, nil
+	}
+	return "", fmt.Errorf("%w: environment variable %s", ErrNotFound, name)
+}
+
+// Redactor replaces known secret values with a placeholder. It is safe for
+// concurrent use and its secrets can be replaced at runtime.
+type Redactor struct {
+	mu       sync.RWMutex
+	replacer *strings.Replacer
+}
+
+// minSecretLength is the shortest value that is redacted; shorter values
+// would mangle unrelated text
+const minSecretLength = 6
+
+// NewRedactor creates a redactor for the given secrets
+func NewRedactor(secrets ...string) *Redactor {
+	r := &Redactor{}
+	r.Set(secrets)
+	return r
+}
+
+// Set replaces the secrets to redact
+func (r *Redactor) Set(secrets []string) {
+	unique := make(map[string]bool, len(secrets))
+	for _, secret := range secrets {
+		if len(secret) >= minSecretLength {
+			unique[secret] = true
+		}
+	}
+
+	// Replace longer secrets first so a secret containing another is hidden whole
+	sorted := make([]string, 0, len(unique))
+	for secret := range unique {
+		sorted = append(sorted, secret)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+
+	pairs := make([]string, 0, 2*len(sorted))
+	for _, secret := range sorted {
+		pairs = append(pairs, secret, "[REDACTED]")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.replacer = strings.NewReplacer(pairs...)
+}
+
+// Redact returns s with every known secret replaced by [REDACTED]
+func (r *Redactor) Redact(s string) string {
+	r.mu.RLock()
+	replacer := r.replacer
+	r.mu.RUnlock()
+	return replacer.Replace(s)
+}
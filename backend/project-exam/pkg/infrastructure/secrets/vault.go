@@ -0,0 +1,93 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// VaultProvider reads secrets from a HashiCorp Vault compatible server over
+// its HTTP API, such as a local `vault server -dev` instance. Names have the
+// form path#field, e.g. secret/data/ethereum#rpcUrl; both KV version 1 and
+// version 2 responses are understood.
+type VaultProvider struct {
+	addr       string
+	token      string
+	httpClient *http.Client
+}
+
+// NewVaultProvider creates a provider for the Vault server at addr,
+// authenticating with token
+func NewVaultProvider(addr, token string) *VaultProvider {
+	return &VaultProvider{
+		addr:       strings.TrimRight(addr, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// vaultResponse is the envelope of a Vault read; KV version 2 nests the
+// secret's data under data.data
+type vaultResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []string               `json:"errors"`
+}
+
+// Secret reads the field of the secret at path
+func (p *VaultProvider) Secret(ctx context.Context, name string) (string, error) {
+	path, field, ok := strings.Cut(name, "#")
+	if !ok || path == "" || field == "" {
+		return "", errors.New("vault reference must have the form path#field")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.addr+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read vault response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("%w: vault path %s", ErrNotFound, path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned status %d for %s", resp.StatusCode, path)
+	}
+
+	var parsed vaultResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return "", fmt.Errorf("invalid vault response: %w", err)
+	}
+
+	data := parsed.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, hasMetadata := data["metadata"]; hasMetadata {
+			data = nested
+		}
+	}
+
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("%w: field %s of vault path %s", ErrNotFound, field, path)
+	}
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("field %s of vault path %s is not a string", field, path)
+	}
+	return str, nil
+}
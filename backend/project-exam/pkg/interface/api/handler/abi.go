@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/interface/api/response"
+	"github.com/project-exam/pkg/interface/validator"
+	"github.com/project-exam/pkg/usecase"
+)
+
+// ABIHandler handles requests to the ABI registry
+type ABIHandler struct {
+	useCase   usecase.ABIUseCase
+	validator *validator.EthereumValidator
+}
+
+// NewABIHandler creates a new ABIHandler
+func NewABIHandler(useCase usecase.ABIUseCase, validator *validator.EthereumValidator) *ABIHandler {
+	return &ABIHandler{
+		useCase:   useCase,
+		validator: validator,
+	}
+}
+
+// SaveABI handles the request to register the ABI of a contract. The body
+// is the JSON ABI itself.
+func (h *ABIHandler) SaveABI(c *gin.Context) {
+	address, ok := h.address(c)
+	if !ok {
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.BadRequest(c, "Request body too large", err)
+			return
+		}
+		response.BadRequest(c, "Failed to read request body", err)
+		return
+	}
+
+	contractABI, err := h.useCase.SaveABI(c.Request.Context(), address, body)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, response.FormatContractABI(contractABI))
+}
+
+// GetABI handles the request to get the registered ABI of a contract
+func (h *ABIHandler) GetABI(c *gin.Context) {
+	address, ok := h.address(c)
+	if !ok {
+		return
+	}
+
+	contractABI, err := h.useCase.GetABI(c.Request.Context(), address)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, response.FormatContractABI(contractABI))
+}
+
+// DeleteABI handles the request to remove the registered ABI of a contract
+func (h *ABIHandler) DeleteABI(c *gin.Context) {
+	address, ok := h.address(c)
+	if !ok {
+		return
+	}
+
+	if err := h.useCase.DeleteABI(c.Request.Context(), address); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// address validates and formats the address of a request, responding with
+// an error when it is invalid
+func (h *ABIHandler) address(c *gin.Context) (string, bool) {
+	address := c.Param("address")
+	if !h.validator.IsValidAddress(address) {
+		response.Error(c, entity.ErrInvalidAddress)
+		return "", false
+	}
+	return h.validator.FormatAddress(address), true
+}
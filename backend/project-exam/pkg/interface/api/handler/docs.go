@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files/v2"
+
+	"github.com/project-exam/pkg/interface/api/openapi"
+)
+
+// swaggerInitializer points Swagger UI at the served specification. It is
+// served as a file rather than inline so the Content-Security-Policy can
+// stay free of 'unsafe-inline' scripts.
+const swaggerInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: "../openapi.json",
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// docsContentSecurityPolicy relaxes the default policy for Swagger UI, which
+// uses inline styles and data: images
+const docsContentSecurityPolicy = "default-src 'self'; style-src 'self' 'unsafe-inline'; img-src 'self' data:"
+
+// DocsHandler serves the OpenAPI document and Swagger UI
+type DocsHandler struct {
+	spec []byte
+}
+
+// NewDocsHandler creates a new DocsHandler serving doc
+func NewDocsHandler(doc *openapi.Document) (*DocsHandler, error) {
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &DocsHandler{spec: spec}, nil
+}
+
+// Spec serves the OpenAPI document as JSON
+func (h *DocsHandler) Spec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", h.spec)
+}
+
+// UI serves Swagger UI from the embedded distribution. The route must
+// declare a *filepath parameter.
+func (h *DocsHandler) UI(c *gin.Context) {
+	name := strings.TrimPrefix(c.Param("filepath"), "/")
+	if name == "" {
+		name = "index.html"
+	}
+
+	c.Header("Content-Security-Policy", docsContentSecurityPolicy)
+
+	if name == "swagger-initializer.js" {
+		c.Data(http.StatusOK, "application/javascript; charset=utf-8", []byte(swaggerInitializer))
+		return
+	}
+
+	http.ServeFileFS(c.Writer, c.Request, swaggerFiles.FS, name)
+}
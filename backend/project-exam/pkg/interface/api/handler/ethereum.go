@@ -1,8 +1,13 @@
 package handler
 
 import (
+	"fmt"
+	"net/http"
+	"strings"
+
 	"github.com/gin-gonic/gin"
 
+	"github.com/project-exam/pkg/domain/entity"
 	"github.com/project-exam/pkg/interface/api/response"
 	"github.com/project-exam/pkg/interface/validator"
 	"github.com/project-exam/pkg/usecase"
@@ -28,28 +33,166 @@ func (h *EthereumHandler) GetAddressInfo(c *gin.Context) {
 
 	// Validate Ethereum address
 	if !h.validator.IsValidAddress(address) {
-		response.BadRequest(c, "Invalid Ethereum address format", nil)
+		response.Error(c, entity.ErrInvalidAddress)
 		return
 	}
 
 	// Format address (ensures proper casing, etc.)
 	address = h.validator.FormatAddress(address)
 
+	version := response.Version(c)
+
+	// Answer conditional requests from the head block and pending nonce
+	// alone while neither has changed
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" {
+		revision, err := h.useCase.GetAddressRevision(c.Request.Context(), address)
+		if err == nil {
+			etag := addressETag(version, address, revision)
+			if etagMatches(ifNoneMatch, etag) {
+				c.Header("ETag", etag)
+				c.AbortWithStatus(http.StatusNotModified)
+				return
+			}
+		}
+	}
+
 	// Get address information from use case
 	addressInfo, err := h.useCase.GetAddressInfo(c.Request.Context(), address)
 	if err != nil {
-		response.InternalServerError(c, err)
+		response.Error(c, err)
 		return
 	}
 
-	// Format and return successful response
-	formattedResponse := response.FormatAddressInfo(addressInfo)
+	// Format and return successful response in the requested version's format
+	formattedResponse := response.FormatAddressInfoFor(version, addressInfo)
+	c.Header("ETag", addressETag(version, address, &entity.AddressRevision{
+		Block:        addressInfo.CurrentBlock,
+		PendingNonce: addressInfo.Nonce.Pending,
+	}))
 	response.Success(c, formattedResponse)
 }
 
-// HealthCheck handles health check requests
-func (h *EthereumHandler) HealthCheck(c *gin.Context) {
-	c.JSON(200, gin.H{
-		"status": "ok",
-	})
+// GetContractInfo handles the request to describe the contract at an address,
+// resolving the implementation of proxies
+func (h *EthereumHandler) GetContractInfo(c *gin.Context) {
+	address := c.Param("address")
+
+	if !h.validator.IsValidAddress(address) {
+		response.Error(c, entity.ErrInvalidAddress)
+		return
+	}
+	address = h.validator.FormatAddress(address)
+
+	contractInfo, err := h.useCase.GetContractInfo(c.Request.Context(), address)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, response.FormatContractInfo(contractInfo))
+}
+
+// GetStorage handles the request to read a storage slot of an address at
+// the block selected by the block query parameter
+func (h *EthereumHandler) GetStorage(c *gin.Context) {
+	address := c.Param("address")
+
+	if !h.validator.IsValidAddress(address) {
+		response.Error(c, entity.ErrInvalidAddress)
+		return
+	}
+	address = h.validator.FormatAddress(address)
+
+	slot, ok := h.validator.ParseSlot(c.Param("slot"))
+	if !ok {
+		response.Error(c, errInvalidSlot)
+		return
+	}
+
+	block, ok := h.validator.ParseBlock(c.Query("block"))
+	if !ok {
+		response.Error(c, errInvalidBlock)
+		return
+	}
+
+	storage, err := h.useCase.GetStorage(c.Request.Context(), address, slot, block)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, response.FormatStorageSlot(storage))
+}
+
+// GetProof handles the request to prove an address and the storage slots
+// listed in the slots query parameter at the block selected by the block
+// query parameter
+func (h *EthereumHandler) GetProof(c *gin.Context) {
+	address := c.Param("address")
+
+	if !h.validator.IsValidAddress(address) {
+		response.Error(c, entity.ErrInvalidAddress)
+		return
+	}
+	address = h.validator.FormatAddress(address)
+
+	// Slots may be given comma-separated, repeated, or both
+	slots := []string{}
+	for _, param := range c.QueryArray("slots") {
+		for _, raw := range strings.Split(param, ",") {
+			if raw = strings.TrimSpace(raw); raw == "" {
+				continue
+			}
+			slot, ok := h.validator.ParseSlot(raw)
+			if !ok {
+				response.Error(c, errInvalidSlot)
+				return
+			}
+			slots = append(slots, slot)
+		}
+	}
+
+	block, ok := h.validator.ParseBlock(c.Query("block"))
+	if !ok {
+		response.Error(c, errInvalidBlock)
+		return
+	}
+
+	proof, err := h.useCase.GetProof(c.Request.Context(), address, slots, block)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, response.FormatAccountProof(proof))
+}
+
+// Errors of state queries with invalid parameters
+var (
+	errInvalidSlot  = entity.NewError(entity.ErrCodeInvalidRequest, "Invalid storage slot: use a hex or decimal number of up to 32 bytes", nil)
+	errInvalidBlock = entity.NewError(entity.ErrCodeInvalidRequest, "Invalid block: use a block number or hash, or latest, safe, finalized or earliest", nil)
+)
+
+// addressETag derives the entity tag of an address response from the API
+// version, the block it was read at and the pending nonce, so it changes as
+// soon as a transaction of the address enters the pool. It is weak because
+// gas price and timestamp may vary within a block.
+func addressETag(version, address string, revision *entity.AddressRevision) string {
+	return fmt.Sprintf(`W/"%s-%s-%d-%d"`, version, address, revision.Block, revision.PendingNonce)
+}
+
+// etagMatches reports whether an If-None-Match header matches the ETag using
+// weak comparison
+func etagMatches(ifNoneMatch, etag string) bool {
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+
+	opaque := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == opaque {
+			return true
+		}
+	}
+	return false
 }
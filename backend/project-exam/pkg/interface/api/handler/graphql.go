@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-exam/pkg/interface/api/middleware"
+	"github.com/project-exam/pkg/interface/graphql"
+)
+
+// GraphQLRequest is a GraphQL request, sent as a JSON body or, for GET, as
+// query parameters with variables JSON-encoded
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLHandler serves GraphQL queries
+type GraphQLHandler struct {
+	schema *graphql.Schema
+}
+
+// NewGraphQLHandler creates a new GraphQLHandler
+func NewGraphQLHandler(schema *graphql.Schema) *GraphQLHandler {
+	return &GraphQLHandler{
+		schema: schema,
+	}
+}
+
+// Handle executes a query. Like other GraphQL servers it answers with status
+// 200 whenever the query could be executed, reporting field errors in the
+// response; requests that can't be read get 400.
+func (h *GraphQLHandler) Handle(c *gin.Context) {
+	var req GraphQLRequest
+	if c.Request.Method == http.MethodGet {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				graphQLError(c, "variables must be a JSON object")
+				return
+			}
+		}
+	} else if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			graphQLError(c, "request body too large")
+			return
+		}
+		graphQLError(c, "request body must be a JSON object")
+		return
+	}
+	if req.Query == "" {
+		graphQLError(c, "query is required")
+		return
+	}
+
+	resp, addresses := h.schema.Exec(c.Request.Context(), req.Query, req.OperationName, req.Variables)
+	middleware.SetAuditAddresses(c, addresses...)
+
+	// Record the causes of field errors for the request log; responses only carry safe messages
+	for _, err := range resp.Errors {
+		if err.ResolverError != nil {
+			_ = c.Error(err.ResolverError)
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// graphQLError rejects a request that couldn't be read, in the GraphQL
+// response format
+func graphQLError(c *gin.Context, message string) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"errors": []gin.H{{"message": message}},
+	})
+}
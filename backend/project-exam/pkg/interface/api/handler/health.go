@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/interface/api/response"
+	"github.com/project-exam/pkg/usecase"
+)
+
+// HealthHandler handles liveness and readiness probes
+type HealthHandler struct {
+	useCase usecase.HealthUseCase
+	timeout time.Duration
+}
+
+// NewHealthHandler creates a new HealthHandler. timeout bounds the readiness checks.
+func NewHealthHandler(useCase usecase.HealthUseCase, timeout time.Duration) *HealthHandler {
+	return &HealthHandler{
+		useCase: useCase,
+		timeout: timeout,
+	}
+}
+
+// Live reports that the process is running and able to serve requests.
+// It never checks dependencies, so a node outage doesn't restart the service.
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+	})
+}
+
+// Ready reports whether upstream dependencies are healthy, returning 503 when any check fails
+func (h *HealthHandler) Ready(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.timeout)
+	defer cancel()
+
+	report := h.useCase.CheckReadiness(ctx)
+
+	status := http.StatusOK
+	if report.Status != entity.HealthStatusUp {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, response.FormatHealthReport(report))
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/interface/api/middleware"
+	"github.com/project-exam/pkg/interface/api/response"
+	"github.com/project-exam/pkg/interface/validator"
+	"github.com/project-exam/pkg/usecase"
+)
+
+// Limits of a log query filter; nodes apply similar ones to eth_getLogs
+const (
+	maxLogAddresses = 100
+	maxLogTopics    = 4   // positions, as logs have at most 4 topics
+	maxTopicOptions = 100 // alternatives per position
+)
+
+// LogsRequest is a query for event logs. Like eth_getLogs, address and each
+// topic position take a single value or a list of alternatives, topic
+// positions may be null to match anything, and blocks are numbers, hex
+// quantities or tags. blockHash replaces fromBlock and toBlock.
+type LogsRequest struct {
+	Address   json.RawMessage   `json:"address,omitempty"`
+	Topics    []json.RawMessage `json:"topics,omitempty"`
+	FromBlock json.RawMessage   `json:"fromBlock,omitempty"`
+	ToBlock   json.RawMessage   `json:"toBlock,omitempty"`
+	BlockHash string            `json:"blockHash,omitempty"`
+	// Event is a JSON ABI, or a single event entry of one, to decode logs with
+	Event json.RawMessage `json:"event,omitempty"`
+}
+
+// LogsHandler handles event log queries
+type LogsHandler struct {
+	useCase   usecase.LogsUseCase
+	validator *validator.EthereumValidator
+}
+
+// NewLogsHandler creates a new LogsHandler
+func NewLogsHandler(useCase usecase.LogsUseCase, validator *validator.EthereumValidator) *LogsHandler {
+	return &LogsHandler{
+		useCase:   useCase,
+		validator: validator,
+	}
+}
+
+// GetLogs handles the request to query event logs
+func (h *LogsHandler) GetLogs(c *gin.Context) {
+	var req LogsRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.BadRequest(c, "Request body too large", err)
+			return
+		}
+		response.BadRequest(c, "Request body must be a JSON object", err)
+		return
+	}
+
+	filter, err := h.parseFilter(&req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	middleware.SetAuditAddresses(c, filter.Addresses...)
+
+	var eventABI []byte
+	if !isNull(req.Event) {
+		eventABI = req.Event
+	}
+
+	result, err := h.useCase.GetLogs(c.Request.Context(), filter, eventABI)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, response.FormatLogs(result))
+}
+
+// parseFilter validates a logs request and converts it to a log filter
+func (h *LogsHandler) parseFilter(req *LogsRequest) (entity.LogFilter, error) {
+	var filter entity.LogFilter
+
+	addresses, err := stringOrList(req.Address)
+	if err != nil {
+		return filter, invalidLogsRequest("address must be an address or a list of addresses")
+	}
+	if len(addresses) > maxLogAddresses {
+		return filter, invalidLogsRequest("at most %d addresses can be queried at once", maxLogAddresses)
+	}
+	for _, address := range addresses {
+		if !h.validator.IsValidAddress(address) {
+			return filter, entity.ErrInvalidAddress
+		}
+		filter.Addresses = append(filter.Addresses, h.validator.FormatAddress(address))
+	}
+
+	if len(req.Topics) > maxLogTopics {
+		return filter, invalidLogsRequest("topics has at most %d positions", maxLogTopics)
+	}
+	for i, position := range req.Topics {
+		topics, err := stringOrList(position)
+		if err != nil || len(topics) > maxTopicOptions {
+			return filter, invalidLogsRequest("topics[%d] must be null, a topic or a list of up to %d topics", i, maxTopicOptions)
+		}
+		for _, topic := range topics {
+			if !h.validator.IsValidHash(topic) {
+				return filter, invalidLogsRequest("topics[%d] must hold 0x-prefixed 32-byte hex topics", i)
+			}
+		}
+		filter.Topics = append(filter.Topics, topics)
+	}
+
+	if req.BlockHash != "" {
+		if !isNull(req.FromBlock) || !isNull(req.ToBlock) {
+			return filter, invalidLogsRequest("blockHash can't be combined with fromBlock or toBlock")
+		}
+		if !h.validator.IsValidHash(req.BlockHash) {
+			return filter, invalidLogsRequest("blockHash must be a 0x-prefixed 32-byte hex hash")
+		}
+		filter.BlockHash = req.BlockHash
+		return filter, nil
+	}
+
+	if filter.FromBlock, err = h.parseBlock(req.FromBlock); err != nil {
+		return filter, invalidLogsRequest("fromBlock must be a block number, latest, safe, finalized or earliest")
+	}
+	if filter.ToBlock, err = h.parseBlock(req.ToBlock); err != nil {
+		return filter, invalidLogsRequest("toBlock must be a block number, latest, safe, finalized or earliest")
+	}
+	return filter, nil
+}
+
+// parseBlock parses a range end given as a JSON number or string; null
+// selects the latest block. Range ends can't be block hashes, as blockHash
+// selects a single block.
+func (h *LogsHandler) parseBlock(raw json.RawMessage) (entity.BlockSelector, error) {
+	if isNull(raw) {
+		return entity.LatestBlock, nil
+	}
+
+	var block string
+	var number json.Number
+	if err := json.Unmarshal(raw, &number); err == nil {
+		block = number.String()
+	} else if err := json.Unmarshal(raw, &block); err != nil {
+		return entity.BlockSelector{}, err
+	}
+
+	selector, ok := h.validator.ParseBlock(block)
+	if !ok || selector.Hash != "" {
+		return entity.BlockSelector{}, errors.New("invalid block")
+	}
+	return selector, nil
+}
+
+// stringOrList decodes a JSON value that is null, a string or a list of
+// strings. Null decodes to no strings.
+func stringOrList(raw json.RawMessage) ([]string, error) {
+	if isNull(raw) {
+		return nil, nil
+	}
+
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return []string{single}, nil
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// isNull reports whether a JSON value is absent or null
+func isNull(raw json.RawMessage) bool {
+	raw = bytes.TrimSpace(raw)
+	return len(raw) == 0 || bytes.Equal(raw, []byte("null"))
+}
+
+// invalidLogsRequest is the error of a logs request with an invalid filter
+func invalidLogsRequest(format string, args ...interface{}) error {
+	return entity.NewError(entity.ErrCodeInvalidRequest, "Invalid log filter: "+fmt.Sprintf(format, args...), nil)
+}
@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/interface/api/middleware"
+	"github.com/project-exam/pkg/interface/api/response"
+	"github.com/project-exam/pkg/usecase"
+)
+
+// RPCRequest is a JSON-RPC 2.0 request. A request without an ID is a
+// notification, which gets no response.
+type RPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// nullID is the ID of responses to requests whose ID couldn't be read
+var nullID = json.RawMessage("null")
+
+// CallLimit records n calls made by the client of a request and returns how
+// many of them are within its rate limit
+type CallLimit func(c *gin.Context, n int) int
+
+// RPCHandler serves the JSON-RPC proxy
+type RPCHandler struct {
+	useCase      usecase.RPCUseCase
+	maxBatchSize int
+	callLimit    CallLimit
+}
+
+// NewRPCHandler creates a new RPCHandler accepting batches of up to maxBatchSize requests
+func NewRPCHandler(useCase usecase.RPCUseCase, maxBatchSize int) *RPCHandler {
+	return &RPCHandler{
+		useCase:      useCase,
+		maxBatchSize: maxBatchSize,
+		callLimit:    func(_ *gin.Context, n int) int { return n },
+	}
+}
+
+// SetCallLimit rate limits the calls of each request. The request itself is
+// expected to be charged one call by the rate limiting middleware, so only
+// the calls after the first are charged here.
+func (h *RPCHandler) SetCallLimit(limit CallLimit) {
+	h.callLimit = limit
+}
+
+// Handle serves a single JSON-RPC request or a batch. Responses always have
+// status 200 with errors reported in JSON-RPC terms, as Ethereum libraries
+// expect; a batch of notifications only gets 204.
+func (h *RPCHandler) Handle(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			rpcError(c, entity.NewRPCError(entity.RPCCodeInvalidRequest, "request body too large"))
+			return
+		}
+		rpcError(c, entity.NewRPCError(entity.RPCCodeParseError, "failed to read request body"))
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+	batch := len(body) > 0 && body[0] == '['
+
+	var requests []json.RawMessage
+	if batch {
+		if err := json.Unmarshal(body, &requests); err != nil {
+			rpcError(c, entity.NewRPCError(entity.RPCCodeParseError, "parse error"))
+			return
+		}
+		if len(requests) == 0 {
+			rpcError(c, entity.NewRPCError(entity.RPCCodeInvalidRequest, "empty batch"))
+			return
+		}
+		if len(requests) > h.maxBatchSize {
+			rpcError(c, entity.NewRPCError(entity.RPCCodeInvalidRequest, "batch of %d requests exceeds the limit of %d", len(requests), h.maxBatchSize))
+			return
+		}
+	} else {
+		if !json.Valid(body) {
+			rpcError(c, entity.NewRPCError(entity.RPCCodeParseError, "parse error"))
+			return
+		}
+		requests = []json.RawMessage{body}
+	}
+
+	// Validate every request, forwarding only the valid ones
+	responses := make([]*response.RPCResponse, len(requests))
+	ids := make([]json.RawMessage, len(requests))
+	var calls []entity.RPCCall
+	var callIndexes []int
+	for i, raw := range requests {
+		call, id, rpcErr := parseRPCRequest(raw)
+		ids[i] = id
+		if rpcErr != nil {
+			responses[i] = response.NewRPCErrorResponse(id, rpcErr)
+			continue
+		}
+		calls = append(calls, call)
+		callIndexes = append(callIndexes, i)
+	}
+
+	// Count every call of a batch against the rate limit, answering the
+	// calls past it with a limit exceeded error instead of forwarding them
+	if len(calls) > 1 {
+		allowed := 1 + h.callLimit(c, len(calls)-1)
+		for _, i := range callIndexes[allowed:] {
+			responses[i] = response.NewRPCErrorResponse(ids[i],
+				entity.NewRPCError(entity.RPCCodeLimitExceeded, "rate limit exceeded"))
+		}
+		calls, callIndexes = calls[:allowed], callIndexes[:allowed]
+	}
+
+	middleware.SetAuditAddresses(c, rpcAddresses(calls)...)
+
+	for j, result := range h.useCase.Forward(c.Request.Context(), calls) {
+		i := callIndexes[j]
+		if result.Error != nil {
+			_ = c.Error(result.Error)
+			responses[i] = response.NewRPCErrorResponse(ids[i], toRPCError(result.Error))
+			continue
+		}
+		responses[i] = response.NewRPCResultResponse(ids[i], result.Result)
+	}
+
+	// Notifications get no response
+	var out []*response.RPCResponse
+	for i, resp := range responses {
+		if len(ids[i]) > 0 {
+			out = append(out, resp)
+		}
+	}
+
+	switch {
+	case len(out) == 0:
+		c.Status(http.StatusNoContent)
+	case batch:
+		c.JSON(http.StatusOK, out)
+	default:
+		c.JSON(http.StatusOK, out[0])
+	}
+}
+
+// parseRPCRequest validates a JSON-RPC request and converts it to a call.
+// The returned ID is empty for notifications and null when unreadable.
+func parseRPCRequest(raw json.RawMessage) (entity.RPCCall, json.RawMessage, *entity.RPCError) {
+	var req RPCRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return entity.RPCCall{}, nullID, entity.NewRPCError(entity.RPCCodeInvalidRequest, "invalid request")
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		id := req.ID
+		if len(id) == 0 {
+			id = nullID
+		}
+		return entity.RPCCall{}, id, entity.NewRPCError(entity.RPCCodeInvalidRequest, "invalid request")
+	}
+
+	call := entity.RPCCall{Method: req.Method}
+	params := bytes.TrimSpace(req.Params)
+	if len(params) > 0 && !bytes.Equal(params, []byte("null")) {
+		// Ethereum methods take positional parameters only
+		if err := json.Unmarshal(params, &call.Params); err != nil {
+			return entity.RPCCall{}, req.ID, entity.NewRPCError(entity.RPCCodeInvalidParams, "params must be an array")
+		}
+	}
+
+	return call, req.ID, nil
+}
+
+// rpcAddresses returns the addresses JSON-RPC calls look up: the account of
+// state queries, the sender and recipient of calls and the contracts of
+// log filters
+func rpcAddresses(calls []entity.RPCCall) []string {
+	var addresses []string
+	for _, call := range calls {
+		if len(call.Params) == 0 {
+			continue
+		}
+
+		switch call.Method {
+		case "eth_getBalance", "eth_getCode", "eth_getTransactionCount", "eth_getStorageAt", "eth_getProof":
+			var address string
+			if json.Unmarshal(call.Params[0], &address) == nil {
+				addresses = append(addresses, address)
+			}
+		case "eth_call", "eth_estimateGas", "eth_createAccessList":
+			var tx struct {
+				From string `json:"from"`
+				To   string `json:"to"`
+			}
+			if json.Unmarshal(call.Params[0], &tx) == nil {
+				addresses = append(addresses, tx.From, tx.To)
+			}
+		case "eth_getLogs":
+			var filter struct {
+				Address json.RawMessage `json:"address"`
+			}
+			if json.Unmarshal(call.Params[0], &filter) == nil {
+				logAddresses, _ := stringOrList(filter.Address)
+				addresses = append(addresses, logAddresses...)
+			}
+		}
+	}
+	return addresses
+}
+
+// toRPCError converts an error from the use case to a JSON-RPC error. Domain
+// errors keep only their client-safe message, with the API error code as data.
+func toRPCError(err error) *entity.RPCError {
+	var rpcErr *entity.RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+
+	return &entity.RPCError{
+		Code:    entity.RPCCodeServerError,
+		Message: entity.SafeMessage(err),
+		Data:    map[string]string{"code": string(entity.ErrorCodeOf(err))},
+	}
+}
+
+// rpcError sends a single error response to a request that couldn't be read
+func rpcError(c *gin.Context, err *entity.RPCError) {
+	c.JSON(http.StatusOK, response.NewRPCErrorResponse(nullID, err))
+}
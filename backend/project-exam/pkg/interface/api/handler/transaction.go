@@ -0,0 +1,385 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/interface/api/middleware"
+	"github.com/project-exam/pkg/interface/api/response"
+	"github.com/project-exam/pkg/interface/validator"
+	"github.com/project-exam/pkg/usecase"
+)
+
+// errInvalidTxHash is the error of a request with an invalid transaction hash
+var errInvalidTxHash = entity.NewError(entity.ErrCodeInvalidRequest, "Invalid transaction hash: use a 0x-prefixed 32-byte hex hash", nil)
+
+// SendTransactionRequest is a signed transaction to broadcast
+type SendTransactionRequest struct {
+	// RawTransaction is the 0x-prefixed hex encoding of the signed transaction,
+	// as eth_sendRawTransaction takes it
+	RawTransaction string `json:"rawTransaction"`
+}
+
+// zeroAddress is the sender of simulations that don't set one, as in eth_call
+const zeroAddress = "0x0000000000000000000000000000000000000000"
+
+// Limits of a simulation's state overrides
+const (
+	maxOverriddenAccounts = 100
+	maxOverriddenSlots    = 1000 // per account
+)
+
+// SimulateRequest is a transaction to simulate, in the form eth_call takes
+// it. Amounts of wei are decimal or 0x-prefixed hex strings.
+type SimulateRequest struct {
+	From                 string `json:"from,omitempty"` // the zero address if empty
+	To                   string `json:"to,omitempty"`   // empty for a contract creation
+	Value                string `json:"value,omitempty"`
+	Data                 string `json:"data,omitempty"`
+	Gas                  uint64 `json:"gas,omitempty"`
+	GasPrice             string `json:"gasPrice,omitempty"`
+	MaxFeePerGas         string `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas,omitempty"`
+	// Block is a block number, hash or tag; the latest block if empty
+	Block          string                            `json:"block,omitempty"`
+	StateOverrides map[string]AccountOverrideRequest `json:"stateOverrides,omitempty"`
+	BlockOverrides *BlockOverridesRequest            `json:"blockOverrides,omitempty"`
+}
+
+// AccountOverrideRequest replaces parts of an account's state. Storage
+// slots and values are decimal or hex numbers of up to 32 bytes.
+type AccountOverrideRequest struct {
+	Balance string  `json:"balance,omitempty"`
+	Nonce   *uint64 `json:"nonce,omitempty"`
+	Code    *string `json:"code,omitempty"`
+	// State replaces the whole storage; an empty object clears it
+	State     map[string]string `json:"state,omitempty"`
+	StateDiff map[string]string `json:"stateDiff,omitempty"`
+}
+
+// BlockOverridesRequest replaces fields of the block a simulation executes in
+type BlockOverridesRequest struct {
+	Number       *uint64 `json:"number,omitempty"`
+	Time         *uint64 `json:"time,omitempty"` // unix seconds
+	GasLimit     *uint64 `json:"gasLimit,omitempty"`
+	BaseFee      string  `json:"baseFee,omitempty"`
+	FeeRecipient string  `json:"feeRecipient,omitempty"`
+	PrevRandao   string  `json:"prevRandao,omitempty"`
+}
+
+// TransactionHandler handles transaction lookups, broadcasts and simulations
+type TransactionHandler struct {
+	useCase   usecase.TransactionUseCase
+	tracker   usecase.TrackerUseCase
+	validator *validator.EthereumValidator
+}
+
+// NewTransactionHandler creates a new TransactionHandler
+func NewTransactionHandler(useCase usecase.TransactionUseCase, tracker usecase.TrackerUseCase, validator *validator.EthereumValidator) *TransactionHandler {
+	return &TransactionHandler{
+		useCase:   useCase,
+		tracker:   tracker,
+		validator: validator,
+	}
+}
+
+// GetTransaction handles the request to get a transaction with its receipt,
+// decoding its input and logs
+func (h *TransactionHandler) GetTransaction(c *gin.Context) {
+	hash := c.Param("hash")
+
+	if !h.validator.IsValidHash(hash) {
+		response.Error(c, errInvalidTxHash)
+		return
+	}
+
+	details, err := h.useCase.GetTransaction(c.Request.Context(), hash)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, response.FormatTransaction(details))
+}
+
+// GetTransactionStatus handles the request to get the lifecycle state of a
+// transaction, tracking it from then on
+func (h *TransactionHandler) GetTransactionStatus(c *gin.Context) {
+	hash := c.Param("hash")
+
+	if !h.validator.IsValidHash(hash) {
+		response.Error(c, errInvalidTxHash)
+		return
+	}
+
+	status, err := h.tracker.GetStatus(c.Request.Context(), hash, middleware.Client(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, response.FormatTransactionStatus(status))
+}
+
+// SendTransaction handles the request to validate and broadcast a signed
+// raw transaction
+func (h *TransactionHandler) SendTransaction(c *gin.Context) {
+	var req SendTransactionRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.BadRequest(c, "Request body too large", err)
+			return
+		}
+		response.BadRequest(c, "Request body must be a JSON object", err)
+		return
+	}
+
+	raw, ok := h.validator.ParseHexBytes(req.RawTransaction)
+	if !ok || len(raw) == 0 {
+		response.Error(c, entity.NewError(entity.ErrCodeInvalidRequest, "rawTransaction must be 0x-prefixed hex", nil))
+		return
+	}
+
+	tx, err := h.useCase.SendTransaction(c.Request.Context(), raw)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, response.FormatSentTransaction(tx))
+}
+
+// SimulateTransaction handles the request to simulate a transaction with
+// state and block overrides
+func (h *TransactionHandler) SimulateTransaction(c *gin.Context) {
+	var req SimulateRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.BadRequest(c, "Request body too large", err)
+			return
+		}
+		response.BadRequest(c, "Request body must be a JSON object", err)
+		return
+	}
+
+	sim, err := h.parseSimulation(&req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	middleware.SetAuditAddresses(c, simulationAddresses(&req, sim)...)
+
+	result, err := h.useCase.SimulateTransaction(c.Request.Context(), sim)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, response.FormatSimulation(result))
+}
+
+// parseSimulation validates a simulation request and converts it to a simulation
+func (h *TransactionHandler) parseSimulation(req *SimulateRequest) (*entity.Simulation, error) {
+	sim := &entity.Simulation{From: zeroAddress, Gas: req.Gas}
+
+	if req.From != "" {
+		if !h.validator.IsValidAddress(req.From) {
+			return nil, invalidSimulation("from must be an address")
+		}
+		sim.From = h.validator.FormatAddress(req.From)
+	}
+	if req.To != "" {
+		if !h.validator.IsValidAddress(req.To) {
+			return nil, invalidSimulation("to must be an address")
+		}
+		sim.To = h.validator.FormatAddress(req.To)
+	}
+	if req.Data != "" {
+		data, ok := h.validator.ParseHexBytes(req.Data)
+		if !ok {
+			return nil, invalidSimulation("data must be 0x-prefixed hex")
+		}
+		sim.Data = data
+	}
+	if sim.To == "" && len(sim.Data) == 0 {
+		return nil, invalidSimulation("to is required, unless data holds the init code of a contract to create")
+	}
+
+	var err error
+	if sim.Value, err = h.parseWei("value", req.Value); err != nil {
+		return nil, err
+	}
+	if sim.GasPrice, err = h.parseWei("gasPrice", req.GasPrice); err != nil {
+		return nil, err
+	}
+	if sim.MaxFeePerGas, err = h.parseWei("maxFeePerGas", req.MaxFeePerGas); err != nil {
+		return nil, err
+	}
+	if sim.MaxPriorityFeePerGas, err = h.parseWei("maxPriorityFeePerGas", req.MaxPriorityFeePerGas); err != nil {
+		return nil, err
+	}
+	if sim.GasPrice != nil && (sim.MaxFeePerGas != nil || sim.MaxPriorityFeePerGas != nil) {
+		return nil, invalidSimulation("gasPrice can't be combined with maxFeePerGas or maxPriorityFeePerGas")
+	}
+
+	var ok bool
+	if sim.Block, ok = h.validator.ParseBlock(req.Block); !ok {
+		return nil, invalidSimulation("block must be a block number, hash, latest, safe, finalized or earliest")
+	}
+
+	if sim.StateOverrides, err = h.parseStateOverrides(req.StateOverrides); err != nil {
+		return nil, err
+	}
+	if sim.BlockOverrides, err = h.parseBlockOverrides(req.BlockOverrides); err != nil {
+		return nil, err
+	}
+	return sim, nil
+}
+
+// simulationAddresses returns the addresses a simulation looks up: its
+// sender unless it defaulted to the zero address, its recipient and the
+// overridden accounts
+func simulationAddresses(req *SimulateRequest, sim *entity.Simulation) []string {
+	var addresses []string
+	if req.From != "" {
+		addresses = append(addresses, sim.From)
+	}
+	if sim.To != "" {
+		addresses = append(addresses, sim.To)
+	}
+
+	overridden := make([]string, 0, len(sim.StateOverrides))
+	for address := range sim.StateOverrides {
+		overridden = append(overridden, address)
+	}
+	sort.Strings(overridden)
+	return append(addresses, overridden...)
+}
+
+// parseStateOverrides validates the state overrides of a simulation request
+func (h *TransactionHandler) parseStateOverrides(req map[string]AccountOverrideRequest) (map[string]*entity.AccountOverride, error) {
+	if len(req) == 0 {
+		return nil, nil
+	}
+	if len(req) > maxOverriddenAccounts {
+		return nil, invalidSimulation("at most %d accounts can be overridden", maxOverriddenAccounts)
+	}
+
+	overrides := make(map[string]*entity.AccountOverride, len(req))
+	for address, override := range req {
+		if !h.validator.IsValidAddress(address) {
+			return nil, invalidSimulation("stateOverrides must be keyed by address")
+		}
+		address = h.validator.FormatAddress(address)
+		if _, ok := overrides[address]; ok {
+			return nil, invalidSimulation("stateOverrides has %s more than once", address)
+		}
+
+		account := &entity.AccountOverride{Nonce: override.Nonce}
+		var err error
+		if account.Balance, err = h.parseWei("stateOverrides["+address+"].balance", override.Balance); err != nil {
+			return nil, err
+		}
+		if override.Code != nil {
+			code, ok := h.validator.ParseHexBytes(*override.Code)
+			if !ok {
+				return nil, invalidSimulation("stateOverrides[%s].code must be 0x-prefixed hex", address)
+			}
+			account.Code = code
+		}
+
+		if override.State != nil && override.StateDiff != nil {
+			return nil, invalidSimulation("stateOverrides[%s] can't set both state and stateDiff", address)
+		}
+		if len(override.State)+len(override.StateDiff) > maxOverriddenSlots {
+			return nil, invalidSimulation("at most %d storage slots of an account can be overridden", maxOverriddenSlots)
+		}
+		if account.State, err = h.parseStorage(address, "state", override.State); err != nil {
+			return nil, err
+		}
+		if account.StateDiff, err = h.parseStorage(address, "stateDiff", override.StateDiff); err != nil {
+			return nil, err
+		}
+		overrides[address] = account
+	}
+	return overrides, nil
+}
+
+// parseStorage validates overridden storage slots and values, converting
+// both to 32-byte hex. Nil storage stays nil.
+func (h *TransactionHandler) parseStorage(address, field string, storage map[string]string) (map[string]string, error) {
+	if storage == nil {
+		return nil, nil
+	}
+
+	parsed := make(map[string]string, len(storage))
+	for slot, value := range storage {
+		parsedSlot, ok := h.validator.ParseSlot(slot)
+		if !ok {
+			return nil, invalidSimulation("stateOverrides[%s].%s must be keyed by slots of up to 32 bytes", address, field)
+		}
+		parsedValue, ok := h.validator.ParseSlot(value)
+		if !ok {
+			return nil, invalidSimulation("stateOverrides[%s].%s must hold values of up to 32 bytes", address, field)
+		}
+		parsed[parsedSlot] = parsedValue
+	}
+	return parsed, nil
+}
+
+// parseBlockOverrides validates the block overrides of a simulation request
+func (h *TransactionHandler) parseBlockOverrides(req *BlockOverridesRequest) (*entity.BlockOverrides, error) {
+	if req == nil {
+		return nil, nil
+	}
+
+	overrides := &entity.BlockOverrides{
+		Number:   req.Number,
+		Time:     req.Time,
+		GasLimit: req.GasLimit,
+	}
+	var err error
+	if overrides.BaseFee, err = h.parseWei("blockOverrides.baseFee", req.BaseFee); err != nil {
+		return nil, err
+	}
+	if req.FeeRecipient != "" {
+		if !h.validator.IsValidAddress(req.FeeRecipient) {
+			return nil, invalidSimulation("blockOverrides.feeRecipient must be an address")
+		}
+		overrides.FeeRecipient = h.validator.FormatAddress(req.FeeRecipient)
+	}
+	if req.PrevRandao != "" {
+		if !h.validator.IsValidHash(req.PrevRandao) {
+			return nil, invalidSimulation("blockOverrides.prevRandao must be 0x-prefixed 32-byte hex")
+		}
+		overrides.PrevRandao = strings.ToLower(req.PrevRandao)
+	}
+	return overrides, nil
+}
+
+// parseWei parses an optional amount of wei; empty amounts are nil
+func (h *TransactionHandler) parseWei(field, amount string) (*big.Int, error) {
+	if amount == "" {
+		return nil, nil
+	}
+	value, ok := h.validator.ParseQuantity(amount)
+	if !ok {
+		return nil, invalidSimulation("%s must be an amount of wei, as a decimal or 0x-prefixed hex string", field)
+	}
+	return value, nil
+}
+
+// invalidSimulation is the error of a simulation request with an invalid field
+func invalidSimulation(format string, args ...interface{}) error {
+	return entity.NewError(entity.ErrCodeInvalidRequest, "Invalid simulation: "+fmt.Sprintf(format, args...), nil)
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/project-exam/pkg/domain/entity"
+)
+
+// AccessControl applies the client IP resolution, IP filter, rate limit and
+// authentication of the /api group to requests arriving over other
+// transports, such as gRPC, so every transport enforces the same policy
+type AccessControl struct {
+	resolver       *ClientIPResolver
+	allow          *IPList
+	deny           *IPList
+	rateLimiter    *RateLimiter
+	authenticators []Authenticator // nil when authentication is disabled
+}
+
+// NewAccessControl creates an access control sharing the given components
+// with the Gin middleware. authenticators is nil when authentication is
+// disabled.
+func NewAccessControl(resolver *ClientIPResolver, allow, deny *IPList, rateLimiter *RateLimiter, authenticators []Authenticator) *AccessControl {
+	return &AccessControl{
+		resolver:       resolver,
+		allow:          allow,
+		deny:           deny,
+		rateLimiter:    rateLimiter,
+		authenticators: authenticators,
+	}
+}
+
+// ClientIP returns the client IP of the request
+func (a *AccessControl) ClientIP(r *http.Request) string {
+	return a.resolver.Resolve(r)
+}
+
+// Check applies the access policy to a request from clientIP requiring the
+// given scopes. It returns the authenticated principal, or nil when
+// authentication is disabled, and a domain error when access is refused.
+func (a *AccessControl) Check(r *http.Request, clientIP string, scopes ...string) (*Principal, error) {
+	ip := net.ParseIP(clientIP)
+	if a.deny.Contains(ip) || (a.allow.Len() > 0 && !a.allow.Contains(ip)) {
+		return nil, entity.NewError(entity.ErrCodeForbidden, "Access denied", nil)
+	}
+
+	if !a.rateLimiter.Allow(clientIP) {
+		return nil, entity.NewError(entity.ErrCodeRateLimited, "Rate limit exceeded. Try again later.", nil)
+	}
+
+	if a.authenticators == nil {
+		return nil, nil
+	}
+
+	for _, authenticator := range a.authenticators {
+		if !authenticator.HasCredentials(r) {
+			continue
+		}
+
+		principal, err := authenticator.Verify(r)
+		if err != nil {
+			return nil, entity.NewError(entity.ErrCodeUnauthorized, "Unauthorized", err)
+		}
+
+		if missing := MissingScopes(principal.Scopes, scopes); len(missing) > 0 {
+			return principal, entity.NewError(entity.ErrCodeForbidden, "Insufficient scope", nil)
+		}
+		return principal, nil
+	}
+
+	return nil, entity.NewError(entity.ErrCodeUnauthorized, "Unauthorized", ErrMissingCredentials)
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/domain/repository"
+)
+
+// SetAuditAddresses records addresses a request looks up outside its
+// :address path parameter, such as those in a JSON-RPC call or a request
+// body, for the audit log
+func SetAuditAddresses(c *gin.Context, addresses ...string) {
+	existing, _ := c.Get(string(AuditAddressesKey))
+	recorded, _ := existing.([]string)
+	c.Set(string(AuditAddressesKey), append(recorded, addresses...))
+}
+
+// auditAddresses returns the addresses a request looked up: its :address
+// path parameter and those recorded with SetAuditAddresses, without
+// duplicates
+func auditAddresses(c *gin.Context) []string {
+	addresses := []string{c.Param("address")}
+	if recorded, ok := c.Get(string(AuditAddressesKey)); ok {
+		addresses = append(addresses, recorded.([]string)...)
+	}
+
+	var unique []string
+	seen := make(map[string]bool, len(addresses))
+	for _, address := range addresses {
+		key := strings.ToLower(address)
+		if address == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, address)
+	}
+	return unique
+}
+
+// AuditLog records every authenticated request to the audit repository.
+// It must run after an authentication middleware has stored the user ID;
+// unauthenticated requests are not recorded.
+func AuditLog(repo repository.AuditRepository, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		startTime := time.Now()
+
+		c.Next()
+
+		userID := c.GetString(string(UserIDKey))
+		if userID == "" {
+			return
+		}
+
+		event := &entity.AuditEvent{
+			Time:      startTime.UTC(),
+			RequestID: c.GetString(string(RequestIDKey)),
+			UserID:    userID,
+			Method:    c.Request.Method,
+			Route:     c.FullPath(),
+			Path:      c.Request.URL.Path,
+			Addresses: auditAddresses(c),
+			Status:    c.Writer.Status(),
+			Latency:   time.Since(startTime),
+			ClientIP:  ClientIP(c),
+		}
+
+		// Don't tie the write to the request context, which may already be cancelled
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := repo.Record(ctx, event); err != nil {
+			logger.WithError(err).WithField("request_id", event.RequestID).Error("Failed to record audit event")
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-exam/pkg/interface/api/response"
+)
+
+// ClientIPKey is the context key holding the resolved client IP
+const ClientIPKey contextKey = "clientIP"
+
+// IPList is a concurrency-safe set of IP networks
+type IPList struct {
+	mu   sync.RWMutex
+	nets map[string]*net.IPNet // map[normalized CIDR]network
+}
+
+// NewIPList creates a list from CIDRs or plain IPs
+func NewIPList(entries ...string) (*IPList, error) {
+	l := &IPList{nets: make(map[string]*net.IPNet)}
+	for _, entry := range entries {
+		if err := l.Add(entry); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// Add adds a CIDR ("10.0.0.0/8") or a single IP ("203.0.113.7") to the list
+func (l *IPList) Add(entry string) error {
+	ipNet, err := parseCIDR(entry)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.nets[ipNet.String()] = ipNet
+	return nil
+}
+
+// Remove removes a CIDR or single IP from the list
+func (l *IPList) Remove(entry string) {
+	ipNet, err := parseCIDR(entry)
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.nets, ipNet.String())
+}
+
+// Replace atomically replaces the list's networks, leaving it unchanged on error
+func (l *IPList) Replace(entries []string) error {
+	nets := make(map[string]*net.IPNet, len(entries))
+	for _, entry := range entries {
+		ipNet, err := parseCIDR(entry)
+		if err != nil {
+			return err
+		}
+		nets[ipNet.String()] = ipNet
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.nets = nets
+	return nil
+}
+
+// Contains reports whether ip falls in any network of the list
+func (l *IPList) Contains(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, ipNet := range l.nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of networks in the list
+func (l *IPList) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.nets)
+}
+
+// parseCIDR parses a CIDR, treating a plain IP as a single-host network
+func parseCIDR(entry string) (*net.IPNet, error) {
+	entry = strings.TrimSpace(entry)
+	if !strings.Contains(entry, "/") {
+		ip := net.ParseIP(entry)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP address %q", entry)
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+
+	_, ipNet, err := net.ParseCIDR(entry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CIDR %q: %w", entry, err)
+	}
+	return ipNet, nil
+}
+
+// ClientIPResolver determines the real client IP of a request. The
+// forwarding header is only honoured when the direct peer is a trusted proxy,
+// and is walked from the nearest hop outwards so clients can't spoof their
+// address by prepending entries. Only the one header the trusted proxy sets
+// is read: proxies pass other headers through unchanged, so a client could
+// set them to any address.
+type ClientIPResolver struct {
+	trustedProxies *IPList
+	header         string // "Forwarded", "X-Forwarded-For" or "X-Real-IP"
+}
+
+// NewClientIPResolver creates a resolver trusting the given proxy CIDRs and
+// reading the forwarding header they set
+func NewClientIPResolver(trustedProxies []string, header string) (*ClientIPResolver, error) {
+	trusted, err := NewIPList(trustedProxies...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClientIPResolver{
+		trustedProxies: trusted,
+		header:         header,
+	}, nil
+}
+
+// Resolve returns the client IP of the request
+func (r *ClientIPResolver) Resolve(req *http.Request) string {
+	remoteIP := remoteAddrIP(req.RemoteAddr)
+	if remoteIP == nil {
+		return strings.TrimSpace(req.RemoteAddr)
+	}
+	if !r.trustedProxies.Contains(remoteIP) {
+		return remoteIP.String()
+	}
+
+	// Walk right to left, skipping our own trusted proxies. An unparseable
+	// hop can't be trusted to have been added by a proxy, so the nearest
+	// trusted hop before it is as far as the chain can be followed.
+	client := remoteIP
+	hops := forwardedHops(r.header, req.Header.Values(r.header))
+	for i := len(hops) - 1; i >= 0; i-- {
+		if hops[i] == nil {
+			break
+		}
+		client = hops[i]
+		if !r.trustedProxies.Contains(client) {
+			break
+		}
+	}
+	return client.String()
+}
+
+// Middleware stores the resolved client IP in the context
+func (r *ClientIPResolver) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(string(ClientIPKey), r.Resolve(c.Request))
+		c.Next()
+	}
+}
+
+// ClientIP returns the client IP resolved by ClientIPResolver, falling back to
+// Gin's own resolution when the resolver middleware isn't installed
+func ClientIP(c *gin.Context) string {
+	if ip := c.GetString(string(ClientIPKey)); ip != "" {
+		return ip
+	}
+	return c.ClientIP()
+}
+
+// ClientID identifies a client for per-client quotas: by user ID when the
+// request was authenticated, otherwise by IP
+func ClientID(userID, clientIP string) string {
+	if userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + clientIP
+}
+
+// Client returns the ClientID of a request
+func Client(c *gin.Context) string {
+	return ClientID(c.GetString(string(UserIDKey)), ClientIP(c))
+}
+
+// forwardedHops parses the addresses listed in a forwarding header, client
+// first. Unparseable or obfuscated hops ("unknown", "_hidden") are nil.
+func forwardedHops(header string, values []string) []net.IP {
+	var hops []net.IP
+	for _, value := range values {
+		for _, element := range strings.Split(value, ",") {
+			addr := strings.TrimSpace(element)
+
+			// RFC 7239: Forwarded: for=192.0.2.60;proto=http, for="[2001:db8::1]:4711"
+			if strings.EqualFold(header, "Forwarded") {
+				addr = ""
+				for _, pair := range strings.Split(element, ";") {
+					key, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+					if ok && strings.EqualFold(key, "for") {
+						addr = strings.Trim(val, `"`)
+					}
+				}
+			}
+
+			hops = append(hops, parseHostIP(addr))
+		}
+	}
+	return hops
+}
+
+// parseHostIP parses an IP optionally wrapped in brackets and followed by a port
+func parseHostIP(addr string) net.IP {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	return net.ParseIP(strings.Trim(addr, "[]"))
+}
+
+// remoteAddrIP parses the peer IP from http.Request.RemoteAddr
+func remoteAddrIP(remoteAddr string) net.IP {
+	return parseHostIP(strings.TrimSpace(remoteAddr))
+}
+
+// IPFilter rejects requests from denied networks, and when the allow list is
+// non-empty, from any network not on it
+func IPFilter(allow, deny *IPList) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ip := net.ParseIP(ClientIP(c))
+
+		if deny.Contains(ip) || (allow.Len() > 0 && !allow.Contains(ip)) {
+			response.Forbidden(c, "Access denied", nil)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// Supported content encodings, in order of server preference
+var supportedEncodings = []string{"br", "gzip"}
+
+// Compress middleware compresses responses with brotli or gzip, negotiated via
+// Accept-Encoding. Bodies smaller than minSize in their first write, already
+// encoded bodies and responses without a body are sent as is.
+func Compress(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		cw := &compressWriter{
+			ResponseWriter: c.Writer,
+			encoding:       encoding,
+			minSize:        minSize,
+		}
+		c.Writer = cw
+		defer cw.close()
+
+		c.Next()
+	}
+}
+
+// negotiateEncoding picks the preferred supported encoding accepted by the client
+func negotiateEncoding(acceptEncoding string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	accepted := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(name))] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, encoding := range supportedEncodings {
+		q, ok := accepted[encoding]
+		if !ok {
+			q, ok = accepted["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
+}
+
+// compressWriter compresses the body written through it. Whether to compress
+// is decided on the first write, once status and headers are known.
+type compressWriter struct {
+	gin.ResponseWriter
+
+	encoding string
+	minSize  int
+	decided  bool
+	encoder  io.WriteCloser
+}
+
+// Write compresses data into the underlying writer
+func (cw *compressWriter) Write(data []byte) (int, error) {
+	if !cw.decided {
+		cw.decide(len(data))
+	}
+	if cw.encoder == nil {
+		return cw.ResponseWriter.Write(data)
+	}
+	return cw.encoder.Write(data)
+}
+
+// WriteString compresses s into the underlying writer
+func (cw *compressWriter) WriteString(s string) (int, error) {
+	return cw.Write([]byte(s))
+}
+
+// Flush flushes buffered compressed data to the client
+func (cw *compressWriter) Flush() {
+	if flusher, ok := cw.encoder.(interface{ Flush() error }); ok {
+		_ = flusher.Flush()
+	}
+	cw.ResponseWriter.Flush()
+}
+
+// decide starts compression unless the response shouldn't be compressed
+func (cw *compressWriter) decide(firstWriteSize int) {
+	cw.decided = true
+
+	header := cw.ResponseWriter.Header()
+	status := cw.ResponseWriter.Status()
+	if header.Get("Content-Encoding") != "" ||
+		status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified ||
+		firstWriteSize < cw.minSize {
+		return
+	}
+
+	header.Set("Content-Encoding", cw.encoding)
+	header.Del("Content-Length")
+
+	switch cw.encoding {
+	case "br":
+		cw.encoder = brotli.NewWriterLevel(cw.ResponseWriter, brotli.DefaultCompression)
+	case "gzip":
+		cw.encoder = gzip.NewWriter(cw.ResponseWriter)
+	}
+}
+
+// close flushes the remaining compressed data
+func (cw *compressWriter) close() {
+	if cw.encoder != nil {
+		_ = cw.encoder.Close()
+	}
+}
@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSOptions configures the Cross-Origin Resource Sharing policy
+type CORSOptions struct {
+	// AllowedOrigins lists exact origins ("https://app.example.com"), wildcard
+	// subdomain patterns ("https://*.example.com") or "*" for any origin
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// originMatcher matches request origins against the configured patterns
+type originMatcher struct {
+	any      bool
+	exact    map[string]bool
+	wildcard []wildcardOrigin
+}
+
+// wildcardOrigin is a pattern like "https://*.example.com" split around the "*"
+type wildcardOrigin struct {
+	prefix string
+	suffix string
+}
+
+func newOriginMatcher(origins []string) *originMatcher {
+	m := &originMatcher{exact: make(map[string]bool)}
+	for _, origin := range origins {
+		origin = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+		switch {
+		case origin == "*":
+			m.any = true
+		case strings.Contains(origin, "://*."):
+			i := strings.Index(origin, "*")
+			m.wildcard = append(m.wildcard, wildcardOrigin{prefix: origin[:i], suffix: origin[i+1:]})
+		case origin != "":
+			m.exact[origin] = true
+		}
+	}
+	return m
+}
+
+// allowed reports whether the origin matches an exact origin or wildcard pattern
+func (m *originMatcher) allowed(origin string) bool {
+	origin = strings.ToLower(origin)
+	if m.any || m.exact[origin] {
+		return true
+	}
+
+	for _, w := range m.wildcard {
+		if !strings.HasPrefix(origin, w.prefix) || !strings.HasSuffix(origin, w.suffix) {
+			continue
+		}
+		// The wildcard must cover at least one subdomain label and nothing else
+		sub := origin[len(w.prefix) : len(origin)-len(w.suffix)]
+		if sub != "" && !strings.ContainsAny(sub, "/:@?#") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CORS middleware for handling Cross-Origin Resource Sharing.
+//
+// Allowed origins are echoed back with "Vary: Origin" so shared caches keep
+// per-origin responses. A "*" origin is only sent literally when credentials
+// are disabled, since browsers reject "*" on credentialed requests.
+// The policy can be replaced at runtime with Update.
+type CORS struct {
+	policy atomic.Pointer[corsPolicy]
+}
+
+// corsPolicy is the precomputed form of CORSOptions
+type corsPolicy struct {
+	matcher     *originMatcher
+	literalAny  bool
+	credentials bool
+	methods     string
+	headers     string
+	exposed     string
+	maxAge      string
+}
+
+// NewCORS creates a CORS middleware with the given options
+func NewCORS(opts CORSOptions) *CORS {
+	cors := &CORS{}
+	cors.Update(opts)
+	return cors
+}
+
+// Update atomically replaces the CORS policy
+func (cors *CORS) Update(opts CORSOptions) {
+	matcher := newOriginMatcher(opts.AllowedOrigins)
+	literalAny := matcher.any && !opts.AllowCredentials
+	if matcher.any && opts.AllowCredentials {
+		// Never reflect arbitrary origins with credentials
+		matcher.any = false
+	}
+
+	maxAge := ""
+	if opts.MaxAge > 0 {
+		maxAge = strconv.Itoa(int(opts.MaxAge.Seconds()))
+	}
+
+	cors.policy.Store(&corsPolicy{
+		matcher:     matcher,
+		literalAny:  literalAny,
+		credentials: opts.AllowCredentials,
+		methods:     strings.Join(opts.AllowedMethods, ", "),
+		headers:     strings.Join(opts.AllowedHeaders, ", "),
+		exposed:     strings.Join(opts.ExposedHeaders, ", "),
+		maxAge:      maxAge,
+	})
+}
+
+// Handler returns the CORS middleware
+func (cors *CORS) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p := cors.policy.Load()
+		matcher, literalAny := p.matcher, p.literalAny
+		methods, headers, exposed, maxAge := p.methods, p.headers, p.exposed, p.maxAge
+
+		origin := c.GetHeader("Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		// The response depends on the Origin header unless every origin gets "*"
+		if !literalAny {
+			c.Writer.Header().Add("Vary", "Origin")
+		}
+		if preflight {
+			c.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+			c.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		// Not a cross-origin request
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		if !literalAny && !matcher.allowed(origin) {
+			if preflight {
+				// Answer without CORS headers so the browser blocks the request
+				c.AbortWithStatus(http.StatusNoContent)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if literalAny {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		if p.credentials && !literalAny {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		// Handle preflight requests
+		if preflight {
+			c.Header("Access-Control-Allow-Methods", methods)
+			if headers != "" {
+				c.Header("Access-Control-Allow-Headers", headers)
+			}
+			if maxAge != "" {
+				c.Header("Access-Control-Max-Age", maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		if exposed != "" {
+			c.Header("Access-Control-Expose-Headers", exposed)
+		}
+
+		c.Next()
+	}
+}
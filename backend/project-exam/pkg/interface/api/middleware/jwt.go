@@ -0,0 +1,273 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-exam/pkg/infrastructure/jwks"
+)
+
+// JWT validation errors. Clients only see ErrInvalidToken; the others are
+// logged as its cause.
+var (
+	ErrInvalidToken     = errors.New("invalid token")
+	ErrMalformedToken   = errors.New("malformed bearer token")
+	ErrInvalidSignature = errors.New("invalid token signature")
+	ErrTokenExpired     = errors.New("token has expired")
+	ErrMissingExpiry    = errors.New("token has no expiry")
+	ErrTokenNotYetValid = errors.New("token is not valid yet")
+	ErrInvalidIssuer    = errors.New("invalid token issuer")
+	ErrInvalidAudience  = errors.New("invalid token audience")
+	ErrMissingSubject   = errors.New("token has no user claim")
+)
+
+// JWTOptions configures how bearer tokens are validated and mapped to a principal
+type JWTOptions struct {
+	Issuer      string        // expected "iss" claim, empty to skip
+	Audience    string        // expected "aud" claim, empty to skip
+	UserClaim   string        // claim holding the user ID, e.g. "sub" or "email"
+	ScopesClaim string        // claim holding the scopes, e.g. "scope" or "scp"
+	Leeway      time.Duration // allowed clock skew for exp/nbf
+	// AllowMissingExpiry accepts tokens without an "exp" claim, which never expire
+	AllowMissingExpiry bool
+}
+
+// JWTAuth authenticates requests carrying an "Authorization: Bearer" JWT
+// signed by a key from the configured JWKS
+type JWTAuth struct {
+	keys *jwks.KeySet
+	opts JWTOptions
+}
+
+// jwtHeader is the decoded JOSE header of a token
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// NewJWTAuth creates a new bearer token authenticator
+func NewJWTAuth(keys *jwks.KeySet, opts JWTOptions) *JWTAuth {
+	if opts.UserClaim == "" {
+		opts.UserClaim = "sub"
+	}
+	if opts.ScopesClaim == "" {
+		opts.ScopesClaim = "scope"
+	}
+
+	return &JWTAuth{
+		keys: keys,
+		opts: opts,
+	}
+}
+
+// Authenticate returns a middleware accepting only bearer tokens
+func (j *JWTAuth) Authenticate() gin.HandlerFunc {
+	return Authenticate(j)
+}
+
+// HasCredentials reports whether the request carries a bearer token
+func (j *JWTAuth) HasCredentials(r *http.Request) bool {
+	return bearerToken(r) != ""
+}
+
+// Verify validates the bearer token and maps its claims to a principal
+func (j *JWTAuth) Verify(r *http.Request) (*Principal, error) {
+	token := bearerToken(r)
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	key, err := j.keys.Key(r.Context(), header.Kid)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	if err := j.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	userID, _ := claims[j.opts.UserClaim].(string)
+	if userID == "" {
+		return nil, ErrMissingSubject
+	}
+
+	return &Principal{
+		UserID: userID,
+		Scopes: claimStrings(claims[j.opts.ScopesClaim]),
+	}, nil
+}
+
+// validateClaims checks the registered time, issuer and audience claims
+func (j *JWTAuth) validateClaims(claims map[string]interface{}) error {
+	now := time.Now()
+
+	switch exp := claims["exp"].(type) {
+	case float64:
+		if now.After(time.Unix(int64(exp), 0).Add(j.opts.Leeway)) {
+			return ErrTokenExpired
+		}
+	case nil:
+		if !j.opts.AllowMissingExpiry {
+			return ErrMissingExpiry
+		}
+	default:
+		return ErrMalformedToken
+	}
+
+	switch nbf := claims["nbf"].(type) {
+	case float64:
+		if now.Add(j.opts.Leeway).Before(time.Unix(int64(nbf), 0)) {
+			return ErrTokenNotYetValid
+		}
+	case nil:
+	default:
+		return ErrMalformedToken
+	}
+
+	if j.opts.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != j.opts.Issuer {
+			return ErrInvalidIssuer
+		}
+	}
+
+	if j.opts.Audience != "" {
+		found := false
+		for _, aud := range claimStrings(claims["aud"]) {
+			if aud == j.opts.Audience {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ErrInvalidAudience
+		}
+	}
+
+	return nil
+}
+
+// verifySignature checks the token signature with the given algorithm and key
+func verifySignature(alg string, key crypto.PublicKey, signingInput string, signature []byte) error {
+	var h hash.Hash
+	var hashFunc crypto.Hash
+	switch alg {
+	case "RS256", "PS256", "ES256":
+		h, hashFunc = sha256.New(), crypto.SHA256
+	case "RS384", "PS384", "ES384":
+		h, hashFunc = sha512.New384(), crypto.SHA384
+	case "RS512", "PS512", "ES512":
+		h, hashFunc = sha512.New(), crypto.SHA512
+	default:
+		// Rejects "none" and symmetric algorithms, which must never be accepted with a JWKS
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidSignature, alg)
+	}
+	h.Write([]byte(signingInput))
+	digest := h.Sum(nil)
+
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		var err error
+		switch alg[:2] {
+		case "RS":
+			err = rsa.VerifyPKCS1v15(pub, hashFunc, digest, signature)
+		case "PS":
+			err = rsa.VerifyPSS(pub, hashFunc, digest, signature, nil)
+		default:
+			err = errors.New("algorithm does not match key type")
+		}
+		if err != nil {
+			return ErrInvalidSignature
+		}
+		return nil
+
+	case *ecdsa.PublicKey:
+		if alg[:2] != "ES" {
+			return ErrInvalidSignature
+		}
+		// JWS ECDSA signatures are the fixed-size concatenation r || s
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return ErrInvalidSignature
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return ErrInvalidSignature
+		}
+		return nil
+
+	default:
+		return ErrInvalidSignature
+	}
+}
+
+// bearerToken extracts the token from the Authorization header
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(header[7:])
+}
+
+// decodeSegment decodes a base64url JSON token segment into v
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// claimStrings normalizes a claim that is either a space-separated string or a string array
+func claimStrings(claim interface{}) []string {
+	switch v := claim.(type) {
+	case string:
+		return strings.Fields(v)
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
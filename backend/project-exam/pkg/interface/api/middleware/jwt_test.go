@@ -0,0 +1,342 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/project-exam/pkg/infrastructure/jwks"
+)
+
+// testKeys are the signing keys of the test JWKS
+type testKeys struct {
+	rsa *rsa.PrivateKey
+	ec  *ecdsa.PrivateKey
+	set *jwks.KeySet
+}
+
+// newTestKeys generates an RSA and an EC key and serves them as a JWKS file
+func newTestKeys(t *testing.T) *testKeys {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b64 := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	doc, err := json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		{
+			"kty": "RSA", "kid": "rsa", "use": "sig",
+			"n": b64(rsaKey.N.Bytes()),
+			"e": b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+		},
+		{
+			"kty": "EC", "kid": "ec", "crv": "P-256",
+			"x": b64(ecKey.X.FillBytes(make([]byte, 32))),
+			"y": b64(ecKey.Y.FillBytes(make([]byte, 32))),
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, doc, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	set, err := jwks.NewKeySet(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testKeys{rsa: rsaKey, ec: ecKey, set: set}
+}
+
+// sign builds a token with the given header and claims, signed with alg by
+// the key it calls for. HS256 is signed with the DER encoding of the RSA
+// public key as secret, as in key confusion attacks; "none" is unsigned.
+func (k *testKeys) sign(t *testing.T, alg string, header, claims map[string]interface{}) string {
+	t.Helper()
+
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	input := encode(header) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(input))
+
+	var signature []byte
+	var err error
+	switch alg {
+	case "RS256":
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k.rsa, crypto.SHA256, digest[:])
+	case "PS256":
+		signature, err = rsa.SignPSS(rand.Reader, k.rsa, crypto.SHA256, digest[:], nil)
+	case "ES256":
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, k.ec, digest[:])
+		if err == nil {
+			signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+		}
+	case "HS256":
+		secret := x509.MarshalPKCS1PublicKey(&k.rsa.PublicKey)
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(input))
+		signature = mac.Sum(nil)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestJWTAuthVerify(t *testing.T) {
+	keys := newTestKeys(t)
+	now := time.Now().Unix()
+
+	// claims returns valid claims with the given ones changed; nil values remove a claim
+	claims := func(changes map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{
+			"sub":   "alice",
+			"scope": "read:address read:tx",
+			"iss":   "https://idp.example.com",
+			"aud":   "project-exam",
+			"exp":   now + 300,
+			"nbf":   now - 300,
+		}
+		for name, value := range changes {
+			if value == nil {
+				delete(c, name)
+			} else {
+				c[name] = value
+			}
+		}
+		return c
+	}
+	rs256 := map[string]interface{}{"alg": "RS256", "kid": "rsa"}
+
+	tests := []struct {
+		name    string
+		header  map[string]interface{}
+		signAlg string // algorithm actually signed with, if not the header's
+		claims  map[string]interface{}
+		opts    JWTOptions
+		tamper  func(token string) string
+		wantErr error
+	}{
+		{name: "valid RS256", header: rs256, claims: claims(nil)},
+		{name: "valid PS256", header: map[string]interface{}{"alg": "PS256", "kid": "rsa"}, claims: claims(nil)},
+		{name: "valid ES256", header: map[string]interface{}{"alg": "ES256", "kid": "ec"}, claims: claims(nil)},
+
+		// Algorithm confusion
+		{
+			name:    "alg none",
+			header:  map[string]interface{}{"alg": "none", "kid": "rsa"},
+			claims:  claims(nil),
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "HS256 with the public key as secret",
+			header:  map[string]interface{}{"alg": "HS256", "kid": "rsa"},
+			claims:  claims(nil),
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "RS256 signature claimed for an EC key",
+			header:  map[string]interface{}{"alg": "RS256", "kid": "ec"},
+			claims:  claims(nil),
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "ES256 claimed for an RSA key",
+			header:  map[string]interface{}{"alg": "ES256", "kid": "rsa"},
+			claims:  claims(nil),
+			wantErr: ErrInvalidSignature,
+		},
+
+		// Key IDs
+		{
+			name:    "unknown kid",
+			header:  map[string]interface{}{"alg": "RS256", "kid": "rotated"},
+			claims:  claims(nil),
+			wantErr: jwks.ErrKeyNotFound,
+		},
+		{
+			name:    "kid of another key",
+			header:  map[string]interface{}{"alg": "RS256", "kid": "ec"},
+			signAlg: "ES256",
+			claims:  claims(nil),
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "RS256 header over a PS256 signature",
+			header:  rs256,
+			signAlg: "PS256",
+			claims:  claims(nil),
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "missing kid with several keys",
+			header:  map[string]interface{}{"alg": "RS256"},
+			claims:  claims(nil),
+			wantErr: jwks.ErrKeyNotFound,
+		},
+		{
+			name:   "tampered claims",
+			header: rs256,
+			claims: claims(nil),
+			tamper: func(token string) string {
+				return swapClaims(t, token, claims(map[string]interface{}{"sub": "mallory"}))
+			},
+			wantErr: ErrInvalidSignature,
+		},
+
+		// Expiry and validity window
+		{name: "expired", header: rs256, claims: claims(map[string]interface{}{"exp": now - 60}), wantErr: ErrTokenExpired},
+		{
+			name:   "expired within leeway",
+			header: rs256,
+			claims: claims(map[string]interface{}{"exp": now - 10}),
+			opts:   JWTOptions{Leeway: 30 * time.Second},
+		},
+		{
+			name:    "expired beyond leeway",
+			header:  rs256,
+			claims:  claims(map[string]interface{}{"exp": now - 60}),
+			opts:    JWTOptions{Leeway: 30 * time.Second},
+			wantErr: ErrTokenExpired,
+		},
+		{name: "missing exp", header: rs256, claims: claims(map[string]interface{}{"exp": nil}), wantErr: ErrMissingExpiry},
+		{
+			name:   "missing exp allowed",
+			header: rs256,
+			claims: claims(map[string]interface{}{"exp": nil}),
+			opts:   JWTOptions{AllowMissingExpiry: true},
+		},
+		{name: "non-numeric exp", header: rs256, claims: claims(map[string]interface{}{"exp": "tomorrow"}), wantErr: ErrMalformedToken},
+		{name: "not valid yet", header: rs256, claims: claims(map[string]interface{}{"nbf": now + 60}), wantErr: ErrTokenNotYetValid},
+		{
+			name:   "not valid yet within leeway",
+			header: rs256,
+			claims: claims(map[string]interface{}{"nbf": now + 10}),
+			opts:   JWTOptions{Leeway: 30 * time.Second},
+		},
+
+		// Issuer and audience
+		{
+			name:   "expected issuer and audience",
+			header: rs256,
+			claims: claims(nil),
+			opts:   JWTOptions{Issuer: "https://idp.example.com", Audience: "project-exam"},
+		},
+		{
+			name:    "wrong issuer",
+			header:  rs256,
+			claims:  claims(map[string]interface{}{"iss": "https://evil.example.com"}),
+			opts:    JWTOptions{Issuer: "https://idp.example.com"},
+			wantErr: ErrInvalidIssuer,
+		},
+		{
+			name:    "missing issuer",
+			header:  rs256,
+			claims:  claims(map[string]interface{}{"iss": nil}),
+			opts:    JWTOptions{Issuer: "https://idp.example.com"},
+			wantErr: ErrInvalidIssuer,
+		},
+		{
+			name:    "wrong audience",
+			header:  rs256,
+			claims:  claims(map[string]interface{}{"aud": "other-api"}),
+			opts:    JWTOptions{Audience: "project-exam"},
+			wantErr: ErrInvalidAudience,
+		},
+		{
+			name:   "audience in a list",
+			header: rs256,
+			claims: claims(map[string]interface{}{"aud": []string{"other-api", "project-exam"}}),
+			opts:   JWTOptions{Audience: "project-exam"},
+		},
+		{
+			name:    "audience list without ours",
+			header:  rs256,
+			claims:  claims(map[string]interface{}{"aud": []string{"other-api"}}),
+			opts:    JWTOptions{Audience: "project-exam"},
+			wantErr: ErrInvalidAudience,
+		},
+
+		{name: "missing user claim", header: rs256, claims: claims(map[string]interface{}{"sub": nil}), wantErr: ErrMissingSubject},
+		{
+			name:    "malformed token",
+			header:  rs256,
+			claims:  claims(nil),
+			tamper:  func(string) string { return "not.a-token" },
+			wantErr: ErrMalformedToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alg, _ := tt.header["alg"].(string)
+			if tt.signAlg != "" {
+				alg = tt.signAlg
+			}
+			token := keys.sign(t, alg, tt.header, tt.claims)
+			if tt.tamper != nil {
+				token = tt.tamper(token)
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			principal, err := NewJWTAuth(keys.set, tt.opts).Verify(req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if principal.UserID != "alice" || len(principal.Scopes) != 2 {
+				t.Fatalf("Verify() = %+v, want alice with 2 scopes", principal)
+			}
+		})
+	}
+}
+
+// swapClaims replaces the claims of token, keeping its signature
+func swapClaims(t *testing.T, token string, claims map[string]interface{}) string {
+	t.Helper()
+
+	data, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(token, ".")
+	return parts[0] + "." + base64.RawURLEncoding.EncodeToString(data) + "." + parts[2]
+}
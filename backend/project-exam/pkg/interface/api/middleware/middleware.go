@@ -1,8 +1,9 @@
 package middleware
 
 import (
-	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"runtime/debug"
 	"strings"
@@ -23,30 +24,48 @@ type contextKey string
 const (
 	RequestIDKey contextKey = "requestID"
 	StartTimeKey contextKey = "startTime"
+	UserIDKey    contextKey = "userID"
+	ScopesKey    contextKey = "scopes"
+
+	AuditAddressesKey contextKey = "auditAddresses"
+)
+
+// Scopes that can be granted to API keys and required by routes
+const (
+	ScopeReadAddress    = "read:address"
+	ScopeReadTx         = "read:tx"
+	ScopeReadRPC        = "read:rpc" // JSON-RPC proxy
+	ScopeWriteBroadcast = "write:broadcast"
+	ScopeWriteABI       = "write:abi" // ABI registry changes
+	ScopeAdmin          = "admin"     // implies every other scope
 )
 
 // RateLimiter represents a simple IP-based rate limiter
 type RateLimiter struct {
-	ips          map[string][]time.Time
-	limit        int
-	window       time.Duration
-	mu           sync.Mutex
-	whitelistIPs map[string]bool // IPs that are exempt from rate limiting
+	ips       map[string][]time.Time
+	limit     int
+	window    time.Duration
+	mu        sync.Mutex
+	whitelist *IPList // networks that are exempt from rate limiting
 }
 
 // NewRateLimiter creates a new rate limiter
 func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
 	limiter := &RateLimiter{
-		ips:          make(map[string][]time.Time),
-		limit:        limit,
-		window:       window,
-		whitelistIPs: make(map[string]bool),
+		ips:       make(map[string][]time.Time),
+		limit:     limit,
+		window:    window,
+		whitelist: &IPList{nets: make(map[string]*net.IPNet)},
 	}
 
 	// Start a cleanup goroutine to prevent memory leaks
 	go func() {
 		for {
-			time.Sleep(window)
+			limiter.mu.Lock()
+			interval := limiter.window
+			limiter.mu.Unlock()
+
+			time.Sleep(interval)
 			limiter.cleanup()
 		}
 	}()
@@ -54,64 +73,82 @@ func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
 	return limiter
 }
 
-// AddToWhitelist adds an IP to the whitelist
-func (rl *RateLimiter) AddToWhitelist(ip string) {
+// SetLimit changes the limit and window; recorded requests are kept and
+// evaluated against the new window
+func (rl *RateLimiter) SetLimit(limit int, window time.Duration) {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
-	rl.whitelistIPs[ip] = true
+	rl.limit = limit
+	rl.window = window
 }
 
-// RemoveFromWhitelist removes an IP from the whitelist
-func (rl *RateLimiter) RemoveFromWhitelist(ip string) {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
-	delete(rl.whitelistIPs, ip)
+// SetWhitelist replaces the whitelist with the given IPs or CIDRs
+func (rl *RateLimiter) SetWhitelist(cidrs []string) error {
+	return rl.whitelist.Replace(cidrs)
+}
+
+// AddToWhitelist exempts an IP or CIDR from rate limiting
+func (rl *RateLimiter) AddToWhitelist(cidr string) error {
+	return rl.whitelist.Add(cidr)
+}
+
+// RemoveFromWhitelist removes an IP or CIDR from the whitelist
+func (rl *RateLimiter) RemoveFromWhitelist(cidr string) {
+	rl.whitelist.Remove(cidr)
 }
 
 // Limit returns a middleware for rate limiting
 func (rl *RateLimiter) Limit() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ip := c.ClientIP()
-
-		rl.mu.Lock()
-
-		// Skip rate limiting for whitelisted IPs
-		if rl.whitelistIPs[ip] {
-			rl.mu.Unlock()
-			c.Next()
+		if !rl.Allow(ClientIP(c)) {
+			response.TooManyRequests(c)
+			c.Abort()
 			return
 		}
 
-		// Initialize if this is the first request from this IP
-		if _, exists := rl.ips[ip]; !exists {
-			rl.ips[ip] = make([]time.Time, 0, rl.limit)
-		}
+		c.Next()
+	}
+}
 
-		// Remove timestamps outside the window
-		now := time.Now()
-		windowStart := now.Add(-rl.window)
+// Allow records a request from ip and reports whether it is within the limit
+func (rl *RateLimiter) Allow(ip string) bool {
+	return rl.AllowN(ip, 1) == 1
+}
 
-		validRequests := rl.ips[ip][:0]
-		for _, t := range rl.ips[ip] {
-			if t.After(windowStart) {
-				validRequests = append(validRequests, t)
-			}
-		}
+// AllowN records up to n requests from ip, such as the calls of a JSON-RPC
+// batch, and returns how many of them are within the limit
+func (rl *RateLimiter) AllowN(ip string, n int) int {
+	// Skip rate limiting for whitelisted IPs
+	if n <= 0 || rl.whitelist.Contains(net.ParseIP(ip)) {
+		return n
+	}
 
-		// Check if limit has been reached
-		if len(validRequests) >= rl.limit {
-			rl.mu.Unlock()
-			response.TooManyRequests(c)
-			c.Abort()
-			return
-		}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
 
-		// Add current request
-		rl.ips[ip] = append(validRequests, now)
-		rl.mu.Unlock()
+	// Initialize if this is the first request from this IP
+	if _, exists := rl.ips[ip]; !exists {
+		rl.ips[ip] = make([]time.Time, 0, rl.limit)
+	}
 
-		c.Next()
+	// Remove timestamps outside the window
+	now := time.Now()
+	windowStart := now.Add(-rl.window)
+
+	validRequests := rl.ips[ip][:0]
+	for _, t := range rl.ips[ip] {
+		if t.After(windowStart) {
+			validRequests = append(validRequests, t)
+		}
 	}
+
+	// Add as many requests as the limit leaves room for
+	allowed := min(n, max(rl.limit-len(validRequests), 0))
+	for i := 0; i < allowed; i++ {
+		validRequests = append(validRequests, now)
+	}
+	rl.ips[ip] = validRequests
+	return allowed
 }
 
 // cleanup removes old IP entries to prevent memory leaks
@@ -165,12 +202,17 @@ func RequestLogger(logger *logrus.Logger) gin.HandlerFunc {
 			"method":      c.Request.Method,
 			"path":        c.Request.URL.Path,
 			"status":      c.Writer.Status(),
-			"client_ip":   c.ClientIP(),
+			"client_ip":   ClientIP(c),
 			"duration_ms": duration.Milliseconds(),
 			"user_agent":  c.Request.UserAgent(),
 			"referer":     c.Request.Referer(),
 		})
 
+		// Attach errors recorded by handlers
+		if len(c.Errors) > 0 {
+			logEntry = logEntry.WithField("error", strings.Join(c.Errors.Errors(), "; "))
+		}
+
 		// Log based on status code
 		if c.Writer.Status() >= 500 {
 			logEntry.Error("Server error")
@@ -182,41 +224,6 @@ func RequestLogger(logger *logrus.Logger) gin.HandlerFunc {
 	}
 }
 
-// Timeout middleware aborts requests that take too long to process
-func Timeout(timeout time.Duration) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Create a context with timeout
-		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
-		defer cancel()
-
-		// Update request context
-		c.Request = c.Request.WithContext(ctx)
-
-		// Create a channel to signal when the request is complete
-		done := make(chan struct{})
-
-		// Process the request in a goroutine
-		go func() {
-			c.Next()
-			close(done)
-		}()
-
-		// Wait for the request to complete or timeout
-		select {
-		case <-done:
-			// Request completed in time
-			return
-		case <-ctx.Done():
-			// Request timed out
-			c.AbortWithStatusJSON(http.StatusRequestTimeout, gin.H{
-				"status":  "error",
-				"message": "Request timed out",
-			})
-			return
-		}
-	}
-}
-
 // Recovery middleware handles panics and recovers gracefully
 func Recovery(logger *logrus.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -234,10 +241,8 @@ func Recovery(logger *logrus.Logger) gin.HandlerFunc {
 				}).Error("Panic recovered")
 
 				// Respond with internal server error
-				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-					"status":  "error",
-					"message": "Internal server error",
-				})
+				response.InternalServerError(c, nil)
+				c.Abort()
 			}
 		}()
 
@@ -260,24 +265,56 @@ func SecurityHeaders() gin.HandlerFunc {
 	}
 }
 
+// Authentication errors
+var (
+	ErrMissingCredentials = errors.New("authentication credentials are required")
+	ErrInvalidAPIKey      = errors.New("invalid API key")
+)
+
+// Principal identifies the caller of an authenticated request
+type Principal struct {
+	UserID string
+	Scopes []string
+}
+
+// Authenticator verifies one kind of credentials carried by a request. It
+// works on the plain HTTP request so other transports, such as gRPC, can
+// share it.
+type Authenticator interface {
+	// HasCredentials reports whether the request carries credentials this authenticator handles
+	HasCredentials(r *http.Request) bool
+
+	// Verify validates the credentials and returns the authenticated principal
+	Verify(r *http.Request) (*Principal, error)
+}
+
 // APIKeyAuth middleware for API key authentication
 type APIKeyAuth struct {
-	apiKeys map[string]string // map[apiKey]userID
+	apiKeys map[string]apiKey // map[apiKey]key details
 	mu      sync.RWMutex
 }
 
+// apiKey holds the owner and granted scopes of an API key
+type apiKey struct {
+	userID string
+	scopes []string
+}
+
 // NewAPIKeyAuth creates a new API key authentication middleware
 func NewAPIKeyAuth() *APIKeyAuth {
 	return &APIKeyAuth{
-		apiKeys: make(map[string]string),
+		apiKeys: make(map[string]apiKey),
 	}
 }
 
-// AddAPIKey adds an API key for a user
-func (a *APIKeyAuth) AddAPIKey(apiKey, userID string) {
+// AddAPIKey adds an API key for a user with the given scopes
+func (a *APIKeyAuth) AddAPIKey(key, userID string, scopes ...string) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
-	a.apiKeys[apiKey] = userID
+	a.apiKeys[key] = apiKey{
+		userID: userID,
+		scopes: append([]string(nil), scopes...),
+	}
 }
 
 // RemoveAPIKey removes an API key
@@ -287,42 +324,138 @@ func (a *APIKeyAuth) RemoveAPIKey(apiKey string) {
 	delete(a.apiKeys, apiKey)
 }
 
+// ReplaceAPIKeys atomically replaces every API key with the given set
+func (a *APIKeyAuth) ReplaceAPIKeys(keys map[string]Principal) {
+	apiKeys := make(map[string]apiKey, len(keys))
+	for key, principal := range keys {
+		apiKeys[key] = apiKey{
+			userID: principal.UserID,
+			scopes: append([]string(nil), principal.Scopes...),
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.apiKeys = apiKeys
+}
+
+// HasCredentials reports whether the request carries an API key
+func (a *APIKeyAuth) HasCredentials(r *http.Request) bool {
+	return apiKeyFromRequest(r) != ""
+}
+
+// Verify looks up the API key and returns its owner and scopes
+func (a *APIKeyAuth) Verify(r *http.Request) (*Principal, error) {
+	a.mu.RLock()
+	details, exists := a.apiKeys[apiKeyFromRequest(r)]
+	a.mu.RUnlock()
+
+	if !exists {
+		return nil, ErrInvalidAPIKey
+	}
+
+	return &Principal{
+		UserID: details.userID,
+		Scopes: details.scopes,
+	}, nil
+}
+
 // Authenticate checks if a valid API key is provided
 func (a *APIKeyAuth) Authenticate() gin.HandlerFunc {
+	return Authenticate(a)
+}
+
+// rejection returns the error shown to clients whose credentials were
+// rejected, telling only which kind of credentials it was
+func rejection(err error) error {
+	if errors.Is(err, ErrInvalidAPIKey) {
+		return ErrInvalidAPIKey
+	}
+	return ErrInvalidToken
+}
+
+// apiKeyFromRequest gets the API key from header or query parameter
+func apiKeyFromRequest(r *http.Request) string {
+	key := r.Header.Get("X-API-Key")
+	if key == "" && r.URL != nil {
+		key = r.URL.Query().Get("api_key")
+	}
+	return key
+}
+
+// Authenticate returns a middleware that verifies the request with the first
+// authenticator whose credentials are present, and stores the resulting
+// principal's user ID and scopes in the context
+func Authenticate(authenticators ...Authenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get API key from header or query parameter
-		apiKey := c.GetHeader("X-API-Key")
-		if apiKey == "" {
-			apiKey = c.Query("api_key")
-		}
+		for _, authenticator := range authenticators {
+			if !authenticator.HasCredentials(c.Request) {
+				continue
+			}
 
-		if apiKey == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"status":  "error",
-				"message": "API key is required",
-			})
+			principal, err := authenticator.Verify(c.Request)
+			if err != nil {
+				// The cause, e.g. why the JWKS couldn't be loaded, may name
+				// internal hosts, so it is only logged
+				_ = c.Error(err)
+				response.Unauthorized(c, rejection(err))
+				c.Abort()
+				return
+			}
+
+			// Store user ID and granted scopes in context
+			c.Set(string(UserIDKey), principal.UserID)
+			c.Set(string(ScopesKey), principal.Scopes)
+
+			c.Next()
 			return
 		}
 
-		a.mu.RLock()
-		userID, exists := a.apiKeys[apiKey]
-		a.mu.RUnlock()
+		response.Unauthorized(c, ErrMissingCredentials)
+		c.Abort()
+	}
+}
 
-		if !exists {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"status":  "error",
-				"message": "Invalid API key",
+// RequireScopes rejects requests whose credentials lack any of the given scopes.
+// It must run after an authentication middleware has stored the granted scopes.
+func RequireScopes(required ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granted := c.GetStringSlice(string(ScopesKey))
+
+		if missing := MissingScopes(granted, required); len(missing) > 0 {
+			response.Forbidden(c, "Insufficient scope", gin.H{
+				"requiredScopes": required,
+				"missingScopes":  missing,
 			})
+			c.Abort()
 			return
 		}
 
-		// Store user ID in context
-		c.Set("userID", userID)
-
 		c.Next()
 	}
 }
 
+// MissingScopes returns the required scopes not covered by the granted scopes
+func MissingScopes(granted, required []string) []string {
+	var missing []string
+	for _, scope := range required {
+		if !hasScope(granted, scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// hasScope reports whether scope is covered by the granted scopes
+func hasScope(granted []string, scope string) bool {
+	for _, g := range granted {
+		if g == scope || g == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
 // CacheControl sets cache control headers
 func CacheControl(maxAge time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -334,30 +467,11 @@ func CacheControl(maxAge time.Duration) gin.HandlerFunc {
 	}
 }
 
-// CORS middleware for handling Cross-Origin Resource Sharing
-func CORS() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key")
-		c.Header("Access-Control-Expose-Headers", "Content-Length, Content-Type, X-Request-ID")
-		c.Header("Access-Control-Allow-Credentials", "true")
-
-		// Handle preflight requests
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusNoContent)
-			return
-		}
-
-		c.Next()
-	}
-}
-
 // ContentTypeEnforcer ensures correct content types for requests
 func ContentTypeEnforcer() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Skip for GET, HEAD, OPTIONS
-		if c.Request.Method == "GET" || c.Request.Method == "HEAD" || c.Request.Method == "OPTIONS" {
+		// Skip for GET, HEAD, OPTIONS and DELETE, which carry no body
+		if c.Request.Method == "GET" || c.Request.Method == "HEAD" || c.Request.Method == "OPTIONS" || c.Request.Method == "DELETE" {
 			c.Next()
 			return
 		}
@@ -366,10 +480,8 @@ func ContentTypeEnforcer() gin.HandlerFunc {
 
 		// Check if Content-Type header contains application/json
 		if contentType == "" || !strings.Contains(strings.ToLower(contentType), "application/json") {
-			c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{
-				"status":  "error",
-				"message": "Content-Type must be application/json",
-			})
+			response.UnsupportedMediaType(c, "Content-Type must be application/json")
+			c.Abort()
 			return
 		}
 
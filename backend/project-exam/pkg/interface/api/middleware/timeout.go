@@ -0,0 +1,225 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/interface/api/response"
+)
+
+// Timeout middleware aborts requests that take too long to process.
+//
+// Handler output is buffered and only copied to the client once the handler
+// returns in time, so exactly one of the handler or the timeout response is
+// ever written. routeTimeouts overrides the timeout per route path (as
+// registered, e.g. "/api/ethereum/:address"); an override of zero disables the
+// timeout for that route, e.g. for streaming responses.
+func Timeout(timeout time.Duration, routeTimeouts map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		d := timeout
+		if override, ok := routeTimeouts[c.FullPath()]; ok {
+			d = override
+		}
+		if d <= 0 {
+			c.Next()
+			return
+		}
+
+		// Create a context with timeout
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+
+		// Update request context and buffer the handler's output
+		c.Request = c.Request.WithContext(ctx)
+		original := c.Writer
+		tw := newTimeoutWriter(original)
+		c.Writer = tw
+
+		// Process the request in a goroutine
+		done := make(chan struct{})
+		var panicked interface{}
+		go func() {
+			defer func() {
+				panicked = recover()
+				close(done)
+			}()
+			c.Next()
+		}()
+
+		// Wait for the request to complete or timeout
+		select {
+		case <-done:
+		case <-ctx.Done():
+			select {
+			case <-done:
+				// Handler finished right at the deadline; keep its response
+			default:
+				// Stop accepting handler output, then reply on the real writer
+				if tw.timeout() {
+					writeTimeoutResponse(original)
+				}
+				// Wait for the handler so the context is not reused while still in use
+				<-done
+			}
+		}
+
+		c.Writer = original
+		if panicked != nil {
+			panic(panicked)
+		}
+		tw.commit()
+	}
+}
+
+// writeTimeoutResponse writes the standard 504 error envelope
+func writeTimeoutResponse(w gin.ResponseWriter) {
+	body, _ := json.Marshal(response.Response{
+		Status:  "error",
+		Code:    string(entity.ErrCodeRequestTimeout),
+		Message: "Request timed out",
+	})
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusGatewayTimeout)
+	_, _ = w.Write(body)
+	w.Flush()
+}
+
+// timeoutWriter buffers a handler's response until it is committed to the
+// underlying writer or discarded because the request timed out
+type timeoutWriter struct {
+	gin.ResponseWriter
+
+	mu       sync.Mutex
+	header   http.Header
+	body     bytes.Buffer
+	status   int
+	written  bool
+	timedOut bool
+}
+
+func newTimeoutWriter(w gin.ResponseWriter) *timeoutWriter {
+	return &timeoutWriter{
+		ResponseWriter: w,
+		header:         w.Header().Clone(),
+		status:         http.StatusOK,
+	}
+}
+
+// Header returns the buffered response headers
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+// WriteHeader records the status code
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut || tw.written {
+		return
+	}
+	tw.status = code
+}
+
+// WriteHeaderNow marks the headers as written
+func (tw *timeoutWriter) WriteHeaderNow() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.written = true
+}
+
+// Write buffers response data, failing once the request has timed out
+func (tw *timeoutWriter) Write(data []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.written = true
+	return tw.body.Write(data)
+}
+
+// WriteString buffers response data, failing once the request has timed out
+func (tw *timeoutWriter) WriteString(s string) (int, error) {
+	return tw.Write([]byte(s))
+}
+
+// Status returns the buffered status code
+func (tw *timeoutWriter) Status() int {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return tw.status
+}
+
+// Size returns the number of buffered body bytes
+func (tw *timeoutWriter) Size() int {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if !tw.written {
+		return -1
+	}
+	return tw.body.Len()
+}
+
+// Written reports whether the handler has written a response
+func (tw *timeoutWriter) Written() bool {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return tw.written
+}
+
+// Flush is a no-op; buffered data is sent when the response is committed
+func (tw *timeoutWriter) Flush() {}
+
+// timeout marks the response as timed out and reports whether the timeout
+// response should be written, i.e. the handler hasn't been committed
+func (tw *timeoutWriter) timeout() bool {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut {
+		return false
+	}
+	tw.timedOut = true
+	return true
+}
+
+// commit copies the buffered response to the underlying writer
+func (tw *timeoutWriter) commit() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut {
+		return
+	}
+
+	dst := tw.ResponseWriter.Header()
+	for key := range dst {
+		if _, ok := tw.header[key]; !ok {
+			dst.Del(key)
+		}
+	}
+	for key, values := range tw.header {
+		dst[key] = values
+	}
+
+	if !tw.written {
+		// Handler wrote nothing; leave the status for the outer middleware
+		if tw.status != http.StatusOK {
+			tw.ResponseWriter.WriteHeader(tw.status)
+		}
+		return
+	}
+
+	tw.ResponseWriter.WriteHeader(tw.status)
+	_, _ = tw.ResponseWriter.Write(tw.body.Bytes())
+}
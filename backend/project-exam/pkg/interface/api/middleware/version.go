@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-exam/pkg/interface/api/response"
+)
+
+// APIVersion records the API version of a route group, which selects the
+// response format, and reports it in the API-Version header
+func APIVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(response.VersionKey, version)
+		c.Header("API-Version", version)
+		c.Next()
+	}
+}
+
+// DeprecationOptions configures the Deprecated middleware
+type DeprecationOptions struct {
+	Date      time.Time // when the routes were deprecated
+	Sunset    time.Time // when the routes will be removed, zero if not scheduled
+	Prefix    string    // path prefix of the deprecated routes, e.g. /api
+	Successor string    // path prefix of the replacement routes, e.g. /api/v1
+}
+
+// Deprecated marks responses of deprecated routes with the Deprecation
+// (RFC 9745) and Sunset (RFC 8594) headers, and links to the successor route
+func Deprecated(opts DeprecationOptions) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", opts.Date.Unix())
+	sunset := ""
+	if !opts.Sunset.IsZero() {
+		sunset = opts.Sunset.UTC().Format(http.TimeFormat)
+	}
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		if sunset != "" {
+			c.Header("Sunset", sunset)
+		}
+		if rest, ok := strings.CutPrefix(c.Request.URL.Path, opts.Prefix); ok {
+			c.Header("Link", fmt.Sprintf(`<%s%s>; rel="successor-version"`, opts.Successor, rest))
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,307 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Version is the OpenAPI specification version of generated documents
+const Version = "3.0.3"
+
+// Document is an OpenAPI 3 document
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []SecurityRequirement `json:"security,omitempty"`
+	Tags       []Tag                 `json:"tags,omitempty"`
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Tag groups operations in the documentation
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations of a path by lowercase HTTP method
+type PathItem map[string]*Operation
+
+// Operation describes a single API operation on a path
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	OperationID string                `json:"operationId,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []SecurityRequirement `json:"security,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+}
+
+// Parameter describes a path, query or header parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+// RequestBody describes the body of a request
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// Response describes a single response, or references a shared one
+type Response struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Headers     map[string]*Header   `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Header describes a response header
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+// MediaType holds the schema of a request or response body
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Schema is a JSON schema, or a reference to a named one
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Example              interface{}        `json:"example,omitempty"`
+}
+
+// Components holds the reusable parts of a document
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	Responses       map[string]*Response       `json:"responses,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes an authentication method
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Description  string `json:"description,omitempty"`
+	Name         string `json:"name,omitempty"`
+	In           string `json:"in,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// SecurityRequirement maps security scheme names to the scopes they need
+type SecurityRequirement map[string][]string
+
+// Builder assembles a Document, deriving schemas from Go types so the
+// documentation follows the structs the API actually serializes
+type Builder struct {
+	doc *Document
+}
+
+// NewBuilder creates a builder for a document with the given info
+func NewBuilder(info Info) *Builder {
+	return &Builder{
+		doc: &Document{
+			OpenAPI: Version,
+			Info:    info,
+			Paths:   make(map[string]PathItem),
+			Components: Components{
+				Schemas:         make(map[string]*Schema),
+				Responses:       make(map[string]*Response),
+				SecuritySchemes: make(map[string]*SecurityScheme),
+			},
+		},
+	}
+}
+
+// Document returns the assembled document
+func (b *Builder) Document() *Document {
+	return b.doc
+}
+
+// AddOperation adds an operation for method on path, which uses the
+// OpenAPI {param} syntax
+func (b *Builder) AddOperation(method, path string, op *Operation) {
+	item, ok := b.doc.Paths[path]
+	if !ok {
+		item = make(PathItem)
+		b.doc.Paths[path] = item
+	}
+	item[strings.ToLower(method)] = op
+}
+
+// AddResponse registers a shared response and returns a reference to it
+func (b *Builder) AddResponse(name string, resp *Response) *Response {
+	b.doc.Components.Responses[name] = resp
+	return &Response{Ref: "#/components/responses/" + name}
+}
+
+// AddSecurityScheme registers an authentication method
+func (b *Builder) AddSecurityScheme(name string, scheme *SecurityScheme) {
+	b.doc.Components.SecuritySchemes[name] = scheme
+}
+
+// AddTag adds a tag used to group operations
+func (b *Builder) AddTag(name, description string) {
+	b.doc.Tags = append(b.doc.Tags, Tag{Name: name, Description: description})
+}
+
+// Component returns the registered schema with the given name, or nil
+func (b *Builder) Component(name string) *Schema {
+	return b.doc.Components.Schemas[name]
+}
+
+// Schema returns the schema of v's type. Named struct types are registered
+// as components and referenced by name.
+func (b *Builder) Schema(v interface{}) *Schema {
+	return b.schemaOf(reflect.TypeOf(v))
+}
+
+var (
+	timeType           = reflect.TypeOf(time.Time{})
+	jsonRawMessageType = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType  = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// customEncoding reports whether t, or a pointer to it, defines its own JSON
+// or text encoding
+func customEncoding(t reflect.Type) bool {
+	ptr := reflect.PointerTo(t)
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) ||
+		ptr.Implements(jsonMarshalerType) || ptr.Implements(textMarshalerType)
+}
+
+func (b *Builder) schemaOf(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == jsonRawMessageType:
+		return &Schema{}
+	case t.Kind() != reflect.Ptr && t.Kind() != reflect.Interface && customEncoding(t):
+		// Custom encodings such as hex quantities and big numbers are serialized as strings
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		s := b.schemaOf(t.Elem())
+		if s.Ref != "" {
+			return s
+		}
+		s.Nullable = true
+		return s
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := 0.0
+		format := "int32"
+		if t.Kind() == reflect.Uint64 || t.Kind() == reflect.Uint {
+			format = "int64"
+		}
+		return &Schema{Type: "integer", Format: format, Minimum: &zero}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: b.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		name := t.Name()
+		if _, ok := b.doc.Components.Schemas[name]; !ok {
+			// Register before descending so recursive types terminate
+			b.doc.Components.Schemas[name] = &Schema{}
+			*b.doc.Components.Schemas[name] = *b.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	default:
+		// interface{} and anything else accept any value
+		return &Schema{}
+	}
+}
+
+// structSchema builds an object schema from a struct's JSON fields. Fields
+// without omitempty are required.
+func (b *Builder) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		// Embedded structs without a name are flattened, as encoding/json does
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded := b.structSchema(field.Type)
+			for prop, schema := range embedded.Properties {
+				s.Properties[prop] = schema
+			}
+			s.Required = append(s.Required, embedded.Required...)
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		s.Properties[name] = b.schemaOf(field.Type)
+		if !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s
+}
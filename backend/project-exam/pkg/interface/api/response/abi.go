@@ -0,0 +1,24 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/project-exam/pkg/domain/entity"
+)
+
+// ContractABIResponse is the response format for a registered ABI
+type ContractABIResponse struct {
+	Address   string          `json:"address"`
+	ABI       json.RawMessage `json:"abi"`
+	UpdatedAt string          `json:"updatedAt"`
+}
+
+// FormatContractABI formats a ContractABI entity into an API response
+func FormatContractABI(contractABI *entity.ContractABI) ContractABIResponse {
+	return ContractABIResponse{
+		Address:   contractABI.Address,
+		ABI:       contractABI.ABI,
+		UpdatedAt: contractABI.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,37 @@
+package response
+
+import "github.com/project-exam/pkg/domain/entity"
+
+// ContractInfoResponse is the response format for contract information
+type ContractInfoResponse struct {
+	Address     string         `json:"address"`
+	Code        CodeResponse   `json:"code"`
+	Proxy       *ProxyResponse `json:"proxy"`
+	BlockNumber uint64         `json:"blockNumber"`
+}
+
+// ProxyResponse is the response format for the delegation of a proxy contract
+type ProxyResponse struct {
+	Type           string `json:"type"`
+	Implementation string `json:"implementation,omitempty"`
+	Admin          string `json:"admin,omitempty"`
+	Beacon         string `json:"beacon,omitempty"`
+}
+
+// FormatContractInfo formats a ContractInfo entity into an API response
+func FormatContractInfo(info *entity.ContractInfo) ContractInfoResponse {
+	resp := ContractInfoResponse{
+		Address:     info.Address,
+		Code:        formatCode(info.Code),
+		BlockNumber: info.BlockNumber,
+	}
+	if info.Proxy != nil {
+		resp.Proxy = &ProxyResponse{
+			Type:           string(info.Proxy.Type),
+			Implementation: info.Proxy.Implementation,
+			Admin:          info.Proxy.Admin,
+			Beacon:         info.Proxy.Beacon,
+		}
+	}
+	return resp
+}
@@ -0,0 +1,93 @@
+package response
+
+import "github.com/project-exam/pkg/domain/entity"
+
+// LogsResponse is the response format for an event log query
+type LogsResponse struct {
+	FromBlock uint64        `json:"fromBlock"`
+	ToBlock   uint64        `json:"toBlock"`
+	Logs      []LogResponse `json:"logs"`
+}
+
+// LogResponse is the response format for an event log
+type LogResponse struct {
+	Address          string         `json:"address"`
+	Topics           []string       `json:"topics"`
+	Data             string         `json:"data"`
+	BlockNumber      uint64         `json:"blockNumber"`
+	BlockHash        string         `json:"blockHash"`
+	TransactionHash  string         `json:"transactionHash"`
+	TransactionIndex uint64         `json:"transactionIndex"`
+	LogIndex         uint64         `json:"logIndex"`
+	Removed          bool           `json:"removed"`
+	Event            *EventResponse `json:"event,omitempty"`
+}
+
+// EventResponse is the response format for a decoded event
+type EventResponse struct {
+	Name      string        `json:"name"`
+	Signature string        `json:"signature"`
+	Args      []ArgResponse `json:"args"`
+}
+
+// ArgResponse is the response format for a decoded argument
+type ArgResponse struct {
+	Name    string      `json:"name"`
+	Type    string      `json:"type"`
+	Value   interface{} `json:"value"`
+	Indexed bool        `json:"indexed,omitempty"`
+}
+
+// FormatLogs formats a LogsResult entity into an API response
+func FormatLogs(result *entity.LogsResult) LogsResponse {
+	resp := LogsResponse{
+		FromBlock: result.FromBlock,
+		ToBlock:   result.ToBlock,
+		Logs:      make([]LogResponse, len(result.Logs)),
+	}
+	for i, log := range result.Logs {
+		resp.Logs[i] = formatLog(log)
+		if i < len(result.Events) && result.Events[i] != nil {
+			resp.Logs[i].Event = formatEvent(result.Events[i])
+		}
+	}
+	return resp
+}
+
+// formatLog formats an event log
+func formatLog(log entity.Log) LogResponse {
+	return LogResponse{
+		Address:          log.Address,
+		Topics:           log.Topics,
+		Data:             encodeHex(log.Data),
+		BlockNumber:      log.BlockNumber,
+		BlockHash:        log.BlockHash,
+		TransactionHash:  log.TransactionHash,
+		TransactionIndex: log.TransactionIndex,
+		LogIndex:         log.Index,
+		Removed:          log.Removed,
+	}
+}
+
+// formatEvent formats a decoded event
+func formatEvent(event *entity.DecodedEvent) *EventResponse {
+	return &EventResponse{
+		Name:      event.Name,
+		Signature: event.Signature,
+		Args:      formatArgs(event.Args),
+	}
+}
+
+// formatArgs formats decoded arguments
+func formatArgs(args []entity.DecodedArg) []ArgResponse {
+	out := make([]ArgResponse, len(args))
+	for i, arg := range args {
+		out[i] = ArgResponse{
+			Name:    arg.Name,
+			Type:    arg.Type,
+			Value:   arg.Value,
+			Indexed: arg.Indexed,
+		}
+	}
+	return out
+}
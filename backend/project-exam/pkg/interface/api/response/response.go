@@ -1,6 +1,7 @@
 package response
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
@@ -12,9 +13,26 @@ import (
 // Response is the standard response format for the API
 type Response struct {
 	Status  string      `json:"status"`
+	Code    string      `json:"code,omitempty"`
 	Message string      `json:"message,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// redact removes secrets from text sent to clients, see SetRedactor
+var redact = func(s string) string { return s }
+
+// SetRedactor sets the function used to remove secrets, such as API keys or
+// RPC URLs, from error messages sent to clients. It must be called before
+// serving requests.
+func SetRedactor(fn func(string) string) {
+	redact = fn
+}
+
+// Redact removes secrets from text sent to clients over other transports
+func Redact(s string) string {
+	return redact(s)
 }
 
 // AddressInfoResponse is the response format for address information
@@ -23,9 +41,27 @@ type AddressInfoResponse struct {
 	GasPrice     GasPriceResponse `json:"gasPrice"`
 	CurrentBlock uint64           `json:"currentBlock"`
 	Balance      BalanceResponse  `json:"balance"`
+	IsContract   bool             `json:"isContract"`
+	AccountType  string           `json:"accountType"`
+	Nonce        NonceResponse    `json:"nonce"`
+	Code         CodeResponse     `json:"code"`
 	Timestamp    string           `json:"timestamp"`
 }
 
+// NonceResponse is the response format for the nonces of an address
+type NonceResponse struct {
+	Latest  uint64 `json:"latest"`
+	Pending uint64 `json:"pending"`
+}
+
+// CodeResponse is the response format for the code deployed at an address
+type CodeResponse struct {
+	Hash        string `json:"hash"`
+	Size        int    `json:"size"`
+	IsProxy     bool   `json:"isProxy"`
+	DelegatedTo string `json:"delegatedTo,omitempty"`
+}
+
 // GasPriceResponse is the response format for gas price
 type GasPriceResponse struct {
 	Wei  string  `json:"wei"`
@@ -38,20 +74,79 @@ type BalanceResponse struct {
 	Ether float64 `json:"ether"`
 }
 
-// NewErrorResponse creates a new error response
-func NewErrorResponse(c *gin.Context, statusCode int, message string, err error) {
+// HealthReportResponse is the response format for readiness probes
+type HealthReportResponse struct {
+	Status    string                `json:"status"`
+	Checks    []HealthCheckResponse `json:"checks"`
+	Timestamp string                `json:"timestamp"`
+}
+
+// HealthCheckResponse is the response format for a single dependency check
+type HealthCheckResponse struct {
+	Name      string                 `json:"name"`
+	Status    string                 `json:"status"`
+	LatencyMs float64                `json:"latencyMs"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+// NewErrorResponse creates a new error response. The error text is included
+// with known secrets redacted, so err must otherwise be safe to show to clients.
+func NewErrorResponse(c *gin.Context, statusCode int, code entity.ErrorCode, message string, err error) {
 	errMsg := ""
 	if err != nil {
-		errMsg = err.Error()
+		errMsg = redact(err.Error())
 	}
 
 	c.JSON(statusCode, Response{
 		Status:  "error",
-		Message: message,
+		Code:    string(code),
+		Message: redact(message),
 		Error:   errMsg,
 	})
 }
 
+// Error sends the response matching a domain error's code. Only the domain
+// error's message is returned; wrapped causes are recorded on the context for
+// logging. Errors without a code are reported as internal errors.
+func Error(c *gin.Context, err error) {
+	_ = c.Error(err)
+
+	var domainErr *entity.Error
+	if !errors.As(err, &domainErr) {
+		InternalServerError(c, err)
+		return
+	}
+
+	NewErrorResponse(c, StatusForCode(domainErr.Code), domainErr.Code, domainErr.Message, nil)
+}
+
+// StatusForCode maps an error code to its HTTP status
+func StatusForCode(code entity.ErrorCode) int {
+	switch code {
+	case entity.ErrCodeInvalidAddress, entity.ErrCodeInvalidRequest:
+		return http.StatusBadRequest
+	case entity.ErrCodeUnauthorized:
+		return http.StatusUnauthorized
+	case entity.ErrCodeForbidden:
+		return http.StatusForbidden
+	case entity.ErrCodeNotFound:
+		return http.StatusNotFound
+	case entity.ErrCodeUnsupportedMedia:
+		return http.StatusUnsupportedMediaType
+	case entity.ErrCodeRateLimited:
+		return http.StatusTooManyRequests
+	case entity.ErrCodeUpstreamError:
+		return http.StatusBadGateway
+	case entity.ErrCodeUpstreamUnavailable:
+		return http.StatusServiceUnavailable
+	case entity.ErrCodeRequestTimeout, entity.ErrCodeUpstreamTimeout:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // NewSuccessResponse creates a new success response
 func NewSuccessResponse(c *gin.Context, statusCode int, data interface{}) {
 	c.JSON(statusCode, Response{
@@ -73,26 +168,92 @@ func FormatAddressInfo(info *entity.AddressInfo) AddressInfoResponse {
 			Wei:   info.Balance.Wei.String(),
 			Ether: info.Balance.Ether,
 		},
-		Timestamp: info.Timestamp.Format(time.RFC3339),
+		IsContract:  info.Code.IsContract,
+		AccountType: string(info.Code.Type),
+		Nonce:       formatNonce(info.Nonce),
+		Code:        formatCode(info.Code),
+		Timestamp:   info.Timestamp.Format(time.RFC3339),
 	}
 }
 
-// Success400 sends a 400 Bad Request response
+// formatNonce formats the nonces of an address
+func formatNonce(nonce entity.Nonce) NonceResponse {
+	return NonceResponse{
+		Latest:  nonce.Latest,
+		Pending: nonce.Pending,
+	}
+}
+
+// formatCode formats the description of an address's code
+func formatCode(code entity.CodeInfo) CodeResponse {
+	return CodeResponse{
+		Hash:        code.Hash,
+		Size:        code.Size,
+		IsProxy:     code.IsProxy,
+		DelegatedTo: code.DelegatedTo,
+	}
+}
+
+// FormatHealthReport formats a HealthReport entity into an API response
+func FormatHealthReport(report *entity.HealthReport) HealthReportResponse {
+	checks := make([]HealthCheckResponse, 0, len(report.Checks))
+	for _, check := range report.Checks {
+		checks = append(checks, HealthCheckResponse{
+			Name:      check.Name,
+			Status:    string(check.Status),
+			LatencyMs: float64(check.Latency.Microseconds()) / 1000,
+			Details:   check.Details,
+			Error:     check.Error,
+		})
+	}
+
+	return HealthReportResponse{
+		Status:    string(report.Status),
+		Checks:    checks,
+		Timestamp: report.Timestamp.Format(time.RFC3339),
+	}
+}
+
+// BadRequest sends a 400 Bad Request response for invalid client input
 func BadRequest(c *gin.Context, message string, err error) {
-	NewErrorResponse(c, http.StatusBadRequest, message, err)
+	NewErrorResponse(c, http.StatusBadRequest, entity.ErrCodeInvalidRequest, message, err)
 }
 
-// Success200 sends a 200 OK response
+// Success sends a 200 OK response
 func Success(c *gin.Context, data interface{}) {
 	NewSuccessResponse(c, http.StatusOK, data)
 }
 
-// InternalServerError sends a 500 Internal Server Error response
+// InternalServerError sends a 500 Internal Server Error response.
+// The error is recorded on the context for logging but never returned to the client.
 func InternalServerError(c *gin.Context, err error) {
-	NewErrorResponse(c, http.StatusInternalServerError, "Internal server error", err)
+	if err != nil {
+		_ = c.Error(err)
+	}
+	NewErrorResponse(c, http.StatusInternalServerError, entity.ErrCodeInternal, "Internal server error", nil)
 }
 
 // TooManyRequests sends a 429 Too Many Requests response
 func TooManyRequests(c *gin.Context) {
-	NewErrorResponse(c, http.StatusTooManyRequests, "Rate limit exceeded. Try again later.", nil)
+	NewErrorResponse(c, http.StatusTooManyRequests, entity.ErrCodeRateLimited, "Rate limit exceeded. Try again later.", nil)
+}
+
+// UnsupportedMediaType sends a 415 Unsupported Media Type response
+func UnsupportedMediaType(c *gin.Context, message string) {
+	NewErrorResponse(c, http.StatusUnsupportedMediaType, entity.ErrCodeUnsupportedMedia, message, nil)
+}
+
+// Forbidden sends a 403 Forbidden response with details about the denied permission
+func Forbidden(c *gin.Context, message string, details interface{}) {
+	c.JSON(http.StatusForbidden, Response{
+		Status:  "error",
+		Code:    string(entity.ErrCodeForbidden),
+		Message: message,
+		Details: details,
+	})
+}
+
+// Unauthorized sends a 401 Unauthorized response
+func Unauthorized(c *gin.Context, err error) {
+	NewErrorResponse(c, http.StatusUnauthorized, entity.ErrCodeUnauthorized, "Unauthorized", err)
 }
@@ -0,0 +1,45 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/project-exam/pkg/domain/entity"
+)
+
+// RPCResponse is a JSON-RPC 2.0 response carrying either a result or an error
+type RPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCErrorObject `json:"error,omitempty"`
+}
+
+// RPCErrorObject is the error member of a JSON-RPC 2.0 response
+type RPCErrorObject struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// NewRPCResultResponse creates a successful response to the request with the given ID
+func NewRPCResultResponse(id, result json.RawMessage) *RPCResponse {
+	return &RPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result:  result,
+	}
+}
+
+// NewRPCErrorResponse creates an error response to the request with the
+// given ID, with known secrets redacted from the message
+func NewRPCErrorResponse(id json.RawMessage, err *entity.RPCError) *RPCResponse {
+	return &RPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error: &RPCErrorObject{
+			Code:    err.Code,
+			Message: redact(err.Message),
+			Data:    err.Data,
+		},
+	}
+}
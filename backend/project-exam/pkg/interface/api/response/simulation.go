@@ -0,0 +1,72 @@
+package response
+
+import (
+	"fmt"
+
+	"github.com/project-exam/pkg/domain/entity"
+)
+
+// SimulationResponse is the response format for a simulated transaction
+type SimulationResponse struct {
+	Success        bool                    `json:"success"`
+	ReturnData     string                  `json:"returnData"` // revert data when it reverted
+	Error          string                  `json:"error,omitempty"`
+	Revert         *RevertResponse         `json:"revert,omitempty"`
+	GasUsed        *uint64                 `json:"gasUsed"` // null when the node couldn't tell
+	Traced         bool                    `json:"traced"`
+	BalanceChanges []BalanceChangeResponse `json:"balanceChanges"`
+	BlockNumber    uint64                  `json:"blockNumber"`
+	BlockHash      string                  `json:"blockHash"`
+}
+
+// RevertResponse is the response format for the decoded revert data of a call
+type RevertResponse struct {
+	Kind      string        `json:"kind"`
+	Message   string        `json:"message,omitempty"`
+	PanicCode string        `json:"panicCode,omitempty"`
+	Error     *CallResponse `json:"error,omitempty"` // the decoded custom error
+}
+
+// BalanceChangeResponse is the response format for a change of an account's
+// balance. Delta is signed.
+type BalanceChangeResponse struct {
+	Address string `json:"address"`
+	Before  string `json:"before"`
+	After   string `json:"after"`
+	Delta   string `json:"delta"`
+}
+
+// FormatSimulation formats a SimulationResult entity into an API response
+func FormatSimulation(result *entity.SimulationResult) SimulationResponse {
+	resp := SimulationResponse{
+		Success:        result.Success,
+		ReturnData:     encodeHex(result.ReturnData),
+		Error:          result.Error,
+		GasUsed:        result.GasUsed,
+		Traced:         result.Traced,
+		BalanceChanges: make([]BalanceChangeResponse, len(result.BalanceChanges)),
+		BlockNumber:    result.Block.Number,
+		BlockHash:      result.Block.Hash,
+	}
+	if revert := result.Revert; revert != nil {
+		resp.Revert = &RevertResponse{
+			Kind:    string(revert.Kind),
+			Message: revert.Message,
+		}
+		if revert.PanicCode != nil {
+			resp.Revert.PanicCode = fmt.Sprintf("0x%x", revert.PanicCode)
+		}
+		if revert.Error != nil {
+			resp.Revert.Error = formatCall(revert.Error)
+		}
+	}
+	for i, change := range result.BalanceChanges {
+		resp.BalanceChanges[i] = BalanceChangeResponse{
+			Address: change.Address,
+			Before:  formatWei(change.Before),
+			After:   formatWei(change.After),
+			Delta:   formatWei(change.Delta),
+		}
+	}
+	return resp
+}
@@ -0,0 +1,99 @@
+package response
+
+import (
+	"encoding/hex"
+
+	"github.com/project-exam/pkg/domain/entity"
+)
+
+// BlockRefResponse identifies the block state was read at
+type BlockRefResponse struct {
+	Number uint64 `json:"number"`
+	Hash   string `json:"hash"`
+}
+
+// StorageSlotResponse is the response format for the value of a storage slot
+type StorageSlotResponse struct {
+	Address string           `json:"address"`
+	Slot    string           `json:"slot"`
+	Value   string           `json:"value"`
+	Block   BlockRefResponse `json:"block"`
+}
+
+// AccountProofResponse is the response format for an account proof. Proof
+// fields use the encoding of eth_getProof, so clients can verify them again.
+type AccountProofResponse struct {
+	Address      string                 `json:"address"`
+	Block        BlockRefResponse       `json:"block"`
+	StateRoot    string                 `json:"stateRoot"`
+	Balance      string                 `json:"balance"`
+	Nonce        uint64                 `json:"nonce"`
+	CodeHash     string                 `json:"codeHash"`
+	StorageHash  string                 `json:"storageHash"`
+	AccountProof []string               `json:"accountProof"`
+	StorageProof []StorageProofResponse `json:"storageProof"`
+	Verified     bool                   `json:"verified"`
+}
+
+// StorageProofResponse is the response format for the proof of a storage slot
+type StorageProofResponse struct {
+	Slot  string   `json:"slot"`
+	Value string   `json:"value"`
+	Proof []string `json:"proof"`
+}
+
+// FormatStorageSlot formats a StorageSlot entity into an API response
+func FormatStorageSlot(slot *entity.StorageSlot) StorageSlotResponse {
+	return StorageSlotResponse{
+		Address: slot.Address,
+		Slot:    slot.Slot,
+		Value:   encodeHex(slot.Value),
+		Block:   formatBlockRef(slot.Block),
+	}
+}
+
+// FormatAccountProof formats an AccountProof entity into an API response
+func FormatAccountProof(proof *entity.AccountProof) AccountProofResponse {
+	resp := AccountProofResponse{
+		Address:      proof.Address,
+		Block:        formatBlockRef(proof.Block),
+		StateRoot:    proof.StateRoot,
+		Balance:      proof.Balance.String(),
+		Nonce:        proof.Nonce,
+		CodeHash:     proof.CodeHash,
+		StorageHash:  proof.StorageHash,
+		AccountProof: formatProofNodes(proof.AccountProof),
+		StorageProof: make([]StorageProofResponse, len(proof.StorageProofs)),
+		Verified:     proof.Verified,
+	}
+	for i, storageProof := range proof.StorageProofs {
+		resp.StorageProof[i] = StorageProofResponse{
+			Slot:  storageProof.Slot,
+			Value: encodeHex(storageProof.Value),
+			Proof: formatProofNodes(storageProof.Proof),
+		}
+	}
+	return resp
+}
+
+// formatBlockRef formats the header of the block state was read at
+func formatBlockRef(header *entity.BlockHeader) BlockRefResponse {
+	return BlockRefResponse{
+		Number: header.Number,
+		Hash:   header.Hash,
+	}
+}
+
+// formatProofNodes hex-encodes the trie nodes of a proof
+func formatProofNodes(nodes [][]byte) []string {
+	out := make([]string, len(nodes))
+	for i, node := range nodes {
+		out[i] = encodeHex(node)
+	}
+	return out
+}
+
+// encodeHex encodes bytes as 0x-prefixed hex
+func encodeHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
@@ -0,0 +1,176 @@
+package response
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/project-exam/pkg/domain/entity"
+)
+
+// Transaction statuses
+const (
+	TxStatusPending = "pending"
+	TxStatusSuccess = "success"
+	TxStatusFailed  = "failed"
+)
+
+// TransactionResponse is the response format for a transaction lookup
+type TransactionResponse struct {
+	Hash                 string           `json:"hash"`
+	Type                 uint64           `json:"type"`
+	Status               string           `json:"status"`
+	From                 string           `json:"from"`
+	To                   string           `json:"to,omitempty"` // omitted for contract creations
+	Nonce                uint64           `json:"nonce"`
+	Value                string           `json:"value"`
+	Gas                  uint64           `json:"gas"`
+	GasPrice             string           `json:"gasPrice"`
+	MaxFeePerGas         string           `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas string           `json:"maxPriorityFeePerGas,omitempty"`
+	Input                string           `json:"input"`
+	DecodedInput         *CallResponse    `json:"decodedInput,omitempty"`
+	BlockHash            string           `json:"blockHash,omitempty"`
+	BlockNumber          *uint64          `json:"blockNumber"`
+	TransactionIndex     *uint64          `json:"transactionIndex"`
+	Receipt              *ReceiptResponse `json:"receipt"` // null while pending
+}
+
+// CallResponse is the response format for decoded call input
+type CallResponse struct {
+	Name      string        `json:"name"`
+	Signature string        `json:"signature"`
+	Selector  string        `json:"selector"`
+	Args      []ArgResponse `json:"args"`
+	Source    string        `json:"source"`
+}
+
+// ReceiptResponse is the response format for a transaction receipt
+type ReceiptResponse struct {
+	GasUsed           uint64        `json:"gasUsed"`
+	CumulativeGasUsed uint64        `json:"cumulativeGasUsed"`
+	EffectiveGasPrice string        `json:"effectiveGasPrice"`
+	ContractAddress   string        `json:"contractAddress,omitempty"`
+	Logs              []LogResponse `json:"logs"`
+}
+
+// SentTransactionResponse is the response format for a broadcast transaction
+type SentTransactionResponse struct {
+	Hash  string `json:"hash"`
+	From  string `json:"from"`
+	Nonce uint64 `json:"nonce"`
+}
+
+// FormatSentTransaction formats a broadcast SignedTransaction entity into an API response
+func FormatSentTransaction(tx *entity.SignedTransaction) SentTransactionResponse {
+	return SentTransactionResponse{
+		Hash:  tx.Hash,
+		From:  tx.From,
+		Nonce: tx.Nonce,
+	}
+}
+
+// TransactionStatusResponse is the response format for the lifecycle state
+// of a tracked transaction
+type TransactionStatusResponse struct {
+	Hash          string  `json:"hash"`
+	From          string  `json:"from"`
+	Nonce         uint64  `json:"nonce"`
+	State         string  `json:"state"`
+	BlockNumber   *uint64 `json:"blockNumber"`
+	BlockHash     string  `json:"blockHash,omitempty"`
+	Confirmations uint64  `json:"confirmations"`
+	Success       *bool   `json:"success"` // receipt status, null until mined
+	ReplacedBy    string  `json:"replacedBy,omitempty"`
+	CheckedBlock  uint64  `json:"checkedBlock"`
+	UpdatedAt     string  `json:"updatedAt"`
+}
+
+// FormatTransactionStatus formats a TransactionStatus entity into an API response
+func FormatTransactionStatus(status *entity.TransactionStatus) TransactionStatusResponse {
+	return TransactionStatusResponse{
+		Hash:          status.Hash,
+		From:          status.From,
+		Nonce:         status.Nonce,
+		State:         string(status.State),
+		BlockNumber:   status.BlockNumber,
+		BlockHash:     status.BlockHash,
+		Confirmations: status.Confirmations,
+		Success:       status.Success,
+		ReplacedBy:    status.ReplacedBy,
+		CheckedBlock:  status.CheckedBlock,
+		UpdatedAt:     status.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// FormatTransaction formats a TransactionDetails entity into an API response
+func FormatTransaction(details *entity.TransactionDetails) TransactionResponse {
+	tx := details.Transaction
+	resp := TransactionResponse{
+		Hash:                 tx.Hash,
+		Type:                 tx.Type,
+		Status:               TxStatusPending,
+		From:                 tx.From,
+		To:                   tx.To,
+		Nonce:                tx.Nonce,
+		Value:                formatWei(tx.Value),
+		Gas:                  tx.Gas,
+		GasPrice:             formatWei(tx.GasPrice),
+		MaxFeePerGas:         formatOptionalWei(tx.MaxFeePerGas),
+		MaxPriorityFeePerGas: formatOptionalWei(tx.MaxPriorityFeePerGas),
+		Input:                encodeHex(tx.Input),
+		BlockHash:            tx.BlockHash,
+		BlockNumber:          tx.BlockNumber,
+		TransactionIndex:     tx.Index,
+	}
+	if details.Call != nil {
+		resp.DecodedInput = formatCall(details.Call)
+	}
+
+	if receipt := details.Receipt; receipt != nil {
+		resp.Status = TxStatusFailed
+		if receipt.Status == 1 {
+			resp.Status = TxStatusSuccess
+		}
+		resp.Receipt = &ReceiptResponse{
+			GasUsed:           receipt.GasUsed,
+			CumulativeGasUsed: receipt.CumulativeGasUsed,
+			EffectiveGasPrice: formatWei(receipt.EffectiveGasPrice),
+			ContractAddress:   receipt.ContractAddress,
+			Logs:              make([]LogResponse, len(receipt.Logs)),
+		}
+		for i, log := range receipt.Logs {
+			resp.Receipt.Logs[i] = formatLog(log)
+			if i < len(details.Events) && details.Events[i] != nil {
+				resp.Receipt.Logs[i].Event = formatEvent(details.Events[i])
+			}
+		}
+	}
+	return resp
+}
+
+// formatCall formats decoded call input
+func formatCall(call *entity.DecodedCall) *CallResponse {
+	return &CallResponse{
+		Name:      call.Name,
+		Signature: call.Signature,
+		Selector:  call.Selector,
+		Args:      formatArgs(call.Args),
+		Source:    string(call.Source),
+	}
+}
+
+// formatWei formats an amount of wei as an exact decimal string
+func formatWei(amount *big.Int) string {
+	if amount == nil {
+		return "0"
+	}
+	return amount.String()
+}
+
+// formatOptionalWei formats an amount of wei that may be unset, as empty
+func formatOptionalWei(amount *big.Int) string {
+	if amount == nil {
+		return ""
+	}
+	return amount.String()
+}
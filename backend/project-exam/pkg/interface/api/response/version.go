@@ -0,0 +1,106 @@
+package response
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/project-exam/pkg/domain/entity"
+)
+
+// API versions. Responses differ between versions; requests are the same.
+const (
+	V1 = "v1"
+	V2 = "v2"
+)
+
+// VersionKey is the context key holding the API version of a request
+const VersionKey = "APIVersion"
+
+// Version returns the API version of the request, defaulting to V1
+func Version(c *gin.Context) string {
+	if version := c.GetString(VersionKey); version != "" {
+		return version
+	}
+	return V1
+}
+
+// AddressInfoV2Response is the v2 response format for address information.
+// Amounts are exact decimal strings, as floats lose precision for large values.
+type AddressInfoV2Response struct {
+	Address     string             `json:"address"`
+	Balance     BalanceV2Response  `json:"balance"`
+	GasPrice    GasPriceV2Response `json:"gasPrice"`
+	BlockNumber uint64             `json:"blockNumber"`
+	IsContract  bool               `json:"isContract"`
+	AccountType string             `json:"accountType"`
+	Nonce       NonceResponse      `json:"nonce"`
+	Code        CodeResponse       `json:"code"`
+	Timestamp   string             `json:"timestamp"`
+}
+
+// BalanceV2Response is the v2 response format for balance
+type BalanceV2Response struct {
+	Wei   string `json:"wei"`
+	Ether string `json:"ether"`
+}
+
+// GasPriceV2Response is the v2 response format for gas price
+type GasPriceV2Response struct {
+	Wei  string `json:"wei"`
+	Gwei string `json:"gwei"`
+}
+
+// FormatAddressInfoV2 formats an AddressInfo entity into a v2 API response
+func FormatAddressInfoV2(info *entity.AddressInfo) AddressInfoV2Response {
+	return AddressInfoV2Response{
+		Address: info.Address,
+		Balance: BalanceV2Response{
+			Wei:   info.Balance.Wei.String(),
+			Ether: formatUnits(info.Balance.Wei, 18),
+		},
+		GasPrice: GasPriceV2Response{
+			Wei:  info.GasPrice.Wei.String(),
+			Gwei: formatUnits(info.GasPrice.Wei, 9),
+		},
+		BlockNumber: info.CurrentBlock,
+		IsContract:  info.Code.IsContract,
+		AccountType: string(info.Code.Type),
+		Nonce:       formatNonce(info.Nonce),
+		Code:        formatCode(info.Code),
+		Timestamp:   info.Timestamp.Format(time.RFC3339),
+	}
+}
+
+// FormatAddressInfoFor formats an AddressInfo entity for the given API version
+func FormatAddressInfoFor(version string, info *entity.AddressInfo) interface{} {
+	if version == V2 {
+		return FormatAddressInfoV2(info)
+	}
+	return FormatAddressInfo(info)
+}
+
+// formatUnits formats an integer amount of the smallest unit as an exact
+// decimal with the given number of decimals, e.g. wei as ether
+func formatUnits(amount *big.Int, decimals int) string {
+	if amount == nil {
+		return "0"
+	}
+
+	digits := new(big.Int).Abs(amount).String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+
+	whole, fraction := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+	result := whole
+	if fraction != "" {
+		result += "." + fraction
+	}
+	if amount.Sign() < 0 {
+		result = "-" + result
+	}
+	return result
+}
@@ -0,0 +1,684 @@
+package router
+
+import (
+	"maps"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/interface/api/handler"
+	"github.com/project-exam/pkg/interface/api/openapi"
+	"github.com/project-exam/pkg/interface/api/response"
+)
+
+// errorResponses are the shared error responses by status code
+var errorResponses = []struct {
+	status      int
+	name        string
+	description string
+}{
+	{http.StatusBadRequest, "BadRequest", "The request is invalid (INVALID_ADDRESS, INVALID_REQUEST)"},
+	{http.StatusUnauthorized, "Unauthorized", "Credentials are missing or invalid (UNAUTHORIZED)"},
+	{http.StatusForbidden, "Forbidden", "The caller lacks a required scope or network access (FORBIDDEN)"},
+	{http.StatusNotFound, "NotFound", "The resource does not exist (NOT_FOUND)"},
+	{http.StatusUnsupportedMediaType, "UnsupportedMediaType", "The request body is not JSON (UNSUPPORTED_MEDIA_TYPE)"},
+	{http.StatusTooManyRequests, "TooManyRequests", "The rate limit was exceeded (RATE_LIMITED)"},
+	{http.StatusInternalServerError, "InternalError", "An unexpected error occurred (INTERNAL_ERROR)"},
+	{http.StatusBadGateway, "BadGateway", "The Ethereum node returned an error (UPSTREAM_ERROR)"},
+	{http.StatusServiceUnavailable, "ServiceUnavailable", "The Ethereum node is unavailable (UPSTREAM_UNAVAILABLE)"},
+	{http.StatusGatewayTimeout, "GatewayTimeout", "The request or the Ethereum node timed out (REQUEST_TIMEOUT, UPSTREAM_TIMEOUT)"},
+}
+
+// openAPIDocument builds the OpenAPI document for every route registered on
+// the engine. Routes without an entry in routeDocs are still listed, with a
+// generic description.
+func (r *Router) openAPIDocument() *openapi.Document {
+	b := openapi.NewBuilder(openapi.Info{
+		Title:       "Ethereum Data API",
+		Description: "Ethereum blockchain data. Every JSON response uses the Response envelope.",
+		Version:     "1.0.0",
+	})
+
+	// The response envelope, with the documented status values and error codes
+	b.Schema(response.Response{})
+	envelope := b.Component("Response")
+	envelope.Properties["status"].Enum = []interface{}{"success", "error"}
+	codes := make([]interface{}, 0, len(entity.ErrorCodes))
+	for _, code := range entity.ErrorCodes {
+		codes = append(codes, string(code))
+	}
+	envelope.Properties["code"].Enum = codes
+	envelope.Properties["code"].Description = "Machine-readable error code, set on errors"
+	envelope.Properties["data"].Description = "Response payload, set on success"
+
+	errorRefs := make(map[int]*openapi.Response, len(errorResponses))
+	for _, e := range errorResponses {
+		errorRefs[e.status] = b.AddResponse(e.name, &openapi.Response{
+			Description: e.description,
+			Content:     jsonContent(&openapi.Schema{Ref: "#/components/schemas/Response"}),
+		})
+	}
+
+	auth := r.config.Server.Auth
+	var security []openapi.SecurityRequirement
+	if auth.Enabled {
+		b.AddSecurityScheme("ApiKeyAuth", &openapi.SecurityScheme{
+			Type: "apiKey",
+			In:   "header",
+			Name: "X-API-Key",
+		})
+		security = append(security, openapi.SecurityRequirement{"ApiKeyAuth": {}})
+		if auth.JWT.Enabled {
+			b.AddSecurityScheme("BearerAuth", &openapi.SecurityScheme{
+				Type:         "http",
+				Scheme:       "bearer",
+				BearerFormat: "JWT",
+			})
+			security = append(security, openapi.SecurityRequirement{"BearerAuth": {}})
+		}
+	}
+
+	docs := r.routeDocs(b)
+	routes := r.engine.Routes()
+	sort.Slice(routes, func(i, j int) bool { return routes[i].Path < routes[j].Path })
+
+	tags := make(map[string]bool)
+	for _, route := range routes {
+		op, ok := docs[route.Method+" "+route.Path]
+		if !ok {
+			op = &openapi.Operation{
+				Summary:   route.Method + " " + route.Path,
+				Responses: map[string]*openapi.Response{"200": {Description: "Success"}},
+			}
+		}
+		addPathParameters(op, route.Path)
+
+		// Middleware protecting API routes can reject any of their requests
+		statuses := []int{http.StatusInternalServerError, http.StatusGatewayTimeout}
+		if strings.HasPrefix(route.Path, "/api/") || route.Path == "/rpc" || route.Path == "/graphql" {
+			statuses = append(statuses, http.StatusTooManyRequests, http.StatusForbidden)
+			if route.Method != http.MethodGet && route.Method != http.MethodHead && route.Method != http.MethodDelete {
+				statuses = append(statuses, http.StatusUnsupportedMediaType)
+			}
+			if auth.Enabled {
+				statuses = append(statuses, http.StatusUnauthorized)
+				op.Security = security
+				if scopes := r.routeScopes[route.Method+" "+route.Path]; len(scopes) > 0 {
+					op.Description = strings.TrimSpace(op.Description + "\n\nRequired scopes: `" + strings.Join(scopes, "`, `") + "`")
+				}
+			}
+		}
+		for _, status := range statuses {
+			if _, ok := op.Responses[strconv.Itoa(status)]; !ok {
+				op.Responses[strconv.Itoa(status)] = errorRefs[status]
+			}
+		}
+
+		for _, tag := range op.Tags {
+			tags[tag] = true
+		}
+		b.AddOperation(route.Method, openAPIPath(route.Path), op)
+	}
+
+	for _, tag := range []string{"ethereum", "rpc", "graphql", "health", "debug"} {
+		if tags[tag] {
+			b.AddTag(tag, "")
+		}
+	}
+
+	return b.Document()
+}
+
+// routeDocs describes the routes registered in registerRoutes, keyed by
+// method and Gin path. The scopes they require are added from the routes.
+func (r *Router) routeDocs(b *openapi.Builder) map[string]*openapi.Operation {
+	docs := make(map[string]*openapi.Operation)
+
+	// versioned documents an API route in every version, and as the
+	// deprecated unversioned alias of v1 should it serve the route. build
+	// describes the route in a version; the version is added to its summary
+	// and operation ID.
+	versioned := func(method, path string, build func(version string) *openapi.Operation) {
+		for _, version := range []string{response.V1, response.V2} {
+			op := build(version)
+			op.Summary += " (" + version + ")"
+			op.OperationID += strings.ToUpper(version)
+			docs[method+" /api/"+version+path] = op
+		}
+
+		alias := build(response.V1)
+		alias.Summary += " (deprecated alias of v1)"
+		markDeprecated(alias)
+		docs[method+" /api"+path] = alias
+	}
+
+	errorRef := func(name string) *openapi.Response {
+		return &openapi.Response{Ref: "#/components/responses/" + name}
+	}
+
+	addressParam := openapi.Parameter{
+		Name:        "address",
+		In:          "path",
+		Description: "Ethereum address, 0x-prefixed hex",
+		Required:    true,
+		Schema:      &openapi.Schema{Type: "string", Pattern: "^0x[0-9a-fA-F]{40}$"},
+	}
+
+	// Address information, whose format differs between versions
+	versioned(http.MethodGet, "/ethereum/:address", func(version string) *openapi.Operation {
+		return &openapi.Operation{
+			Tags:    []string{"ethereum"},
+			Summary: "Get address information",
+			Description: "Returns the balance, latest and pending nonce and code of an address with the current gas price and block number. " +
+				"`accountType` tells EOAs, contracts and EIP-7702 delegated EOAs apart, and `code.isProxy` flags EIP-1167 and EIP-1967 proxies. " +
+				"Responses carry a per-block ETag.",
+			OperationID: "getAddressInfo",
+			Parameters: []openapi.Parameter{
+				addressParam,
+				{Name: "If-None-Match", In: "header", Description: "ETag of a previous response", Schema: &openapi.Schema{Type: "string"}},
+			},
+			Responses: map[string]*openapi.Response{
+				"200": {
+					Description: "Address information",
+					Headers: map[string]*openapi.Header{
+						"ETag":        {Description: "Changes with every new block", Schema: &openapi.Schema{Type: "string"}},
+						"API-Version": {Description: "API version of the response format", Schema: &openapi.Schema{Type: "string"}},
+					},
+					Content: jsonContent(envelope(b.Schema(addressInfoFormats[version]))),
+				},
+				"304": {Description: "Not modified since the given ETag"},
+				"400": errorRef("BadRequest"),
+				"502": errorRef("BadGateway"),
+				"503": errorRef("ServiceUnavailable"),
+			},
+		}
+	})
+
+	versioned(http.MethodGet, "/ethereum/:address/contract", func(string) *openapi.Operation {
+		return &openapi.Operation{
+			Tags:    []string{"ethereum"},
+			Summary: "Get contract information",
+			Description: "Describes the contract deployed at an address. For proxies, `proxy` gives the standard followed " +
+				"(EIP-1967 transparent, UUPS or beacon proxies, EIP-1822 proxies and EIP-1167 minimal proxies) with the " +
+				"implementation, admin and beacon addresses read from the standard storage slots and bytecode. " +
+				"Addresses without a contract, including EIP-7702 delegated EOAs, get 404.",
+			OperationID: "getContractInfo",
+			Parameters:  []openapi.Parameter{addressParam},
+			Responses: map[string]*openapi.Response{
+				"200": {
+					Description: "Contract information",
+					Headers:     map[string]*openapi.Header{},
+					Content:     jsonContent(envelope(b.Schema(response.ContractInfoResponse{}))),
+				},
+				"400": errorRef("BadRequest"),
+				"404": errorRef("NotFound"),
+				"502": errorRef("BadGateway"),
+				"503": errorRef("ServiceUnavailable"),
+			},
+		}
+	})
+
+	blockParam := openapi.Parameter{
+		Name:        "block",
+		In:          "query",
+		Description: "Block to read state at: a number (decimal or 0x-prefixed hex), a block hash, or latest, safe, finalized or earliest. Defaults to latest.",
+		Schema:      &openapi.Schema{Type: "string"},
+	}
+	slotDescription := "Storage slot, as a 0x-prefixed hex or decimal number of up to 32 bytes"
+
+	versioned(http.MethodGet, "/ethereum/:address/storage/:slot", func(string) *openapi.Operation {
+		return &openapi.Operation{
+			Tags:    []string{"ethereum"},
+			Summary: "Read a storage slot",
+			Description: "Returns the 32-byte value of a storage slot of an address at the selected block, " +
+				"with the number and hash of the block it was read at. Unknown blocks get 404.",
+			OperationID: "getStorage",
+			Parameters: []openapi.Parameter{
+				addressParam,
+				{Name: "slot", In: "path", Description: slotDescription, Required: true, Schema: &openapi.Schema{Type: "string"}},
+				blockParam,
+			},
+			Responses: map[string]*openapi.Response{
+				"200": {
+					Description: "Storage slot value",
+					Headers:     map[string]*openapi.Header{},
+					Content:     jsonContent(envelope(b.Schema(response.StorageSlotResponse{}))),
+				},
+				"400": errorRef("BadRequest"),
+				"404": errorRef("NotFound"),
+				"502": errorRef("BadGateway"),
+				"503": errorRef("ServiceUnavailable"),
+			},
+		}
+	})
+
+	versioned(http.MethodGet, "/ethereum/:address/proof", func(string) *openapi.Operation {
+		return &openapi.Operation{
+			Tags:    []string{"ethereum"},
+			Summary: "Prove an account and storage slots",
+			Description: "Returns the EIP-1186 Merkle proof of an account and the given storage slots at the selected block, " +
+				"as eth_getProof does. The proofs are verified against the block's state root before responding; proofs " +
+				"that don't verify get 502. Unknown blocks get 404.",
+			OperationID: "getProof",
+			Parameters: []openapi.Parameter{
+				addressParam,
+				{
+					Name:        "slots",
+					In:          "query",
+					Description: slotDescription + "; comma-separated or repeated, at most 64",
+					Schema:      &openapi.Schema{Type: "string"},
+				},
+				blockParam,
+			},
+			Responses: map[string]*openapi.Response{
+				"200": {
+					Description: "Verified account proof",
+					Headers:     map[string]*openapi.Header{},
+					Content:     jsonContent(envelope(b.Schema(response.AccountProofResponse{}))),
+				},
+				"400": errorRef("BadRequest"),
+				"404": errorRef("NotFound"),
+				"502": errorRef("BadGateway"),
+				"503": errorRef("ServiceUnavailable"),
+			},
+		}
+	})
+
+	versioned(http.MethodPost, "/ethereum/logs", func(string) *openapi.Operation {
+		return &openapi.Operation{
+			Tags:    []string{"ethereum"},
+			Summary: "Query event logs",
+			Description: "Returns the event logs matching a filter, like eth_getLogs. `address` and each `topics` position take " +
+				"a value or a list of alternatives; null topic positions match anything. `fromBlock` and `toBlock` default to " +
+				"latest; `blockHash` selects a single block instead. Ranges are queried in chunks that are split further when " +
+				"the node rejects them. With an `event` ABI, logs of its events are selected when topic 0 isn't filtered and " +
+				"each log gets its decoded `event`.",
+			OperationID: "getLogs",
+			RequestBody: &openapi.RequestBody{
+				Required: true,
+				Content:  jsonContent(b.Schema(handler.LogsRequest{})),
+			},
+			Responses: map[string]*openapi.Response{
+				"200": {
+					Description: "Matching logs",
+					Headers:     map[string]*openapi.Header{},
+					Content:     jsonContent(envelope(b.Schema(response.LogsResponse{}))),
+				},
+				"400": errorRef("BadRequest"),
+				"404": errorRef("NotFound"),
+				"502": errorRef("BadGateway"),
+				"503": errorRef("ServiceUnavailable"),
+			},
+		}
+	})
+
+	versioned(http.MethodGet, "/ethereum/tx/:hash", func(string) *openapi.Operation {
+		return &openapi.Operation{
+			Tags:    []string{"ethereum"},
+			Summary: "Get a transaction",
+			Description: "Returns a transaction with its receipt, or a null receipt while it is pending. `decodedInput` is the " +
+				"call decoded with the ABI registered for the recipient, or failing that with a database of common function " +
+				"signatures, as told by its `source`. Logs of contracts with a registered ABI get their decoded `event`.",
+			OperationID: "getTransaction",
+			Parameters: []openapi.Parameter{{
+				Name:        "hash",
+				In:          "path",
+				Description: "Transaction hash, 0x-prefixed hex",
+				Required:    true,
+				Schema:      &openapi.Schema{Type: "string", Pattern: "^0x[0-9a-fA-F]{64}$"},
+			}},
+			Responses: map[string]*openapi.Response{
+				"200": {
+					Description: "Transaction details",
+					Headers:     map[string]*openapi.Header{},
+					Content:     jsonContent(envelope(b.Schema(response.TransactionResponse{}))),
+				},
+				"400": errorRef("BadRequest"),
+				"404": errorRef("NotFound"),
+				"502": errorRef("BadGateway"),
+				"503": errorRef("ServiceUnavailable"),
+			},
+		}
+	})
+
+	versioned(http.MethodGet, "/ethereum/tx/:hash/status", func(string) *openapi.Operation {
+		return &openapi.Operation{
+			Tags:    []string{"ethereum"},
+			Summary: "Get the lifecycle status of a transaction",
+			Description: "Returns where a transaction is in its lifecycle: `pending` in the pool, `included` in a block, " +
+				"`confirmed` once enough blocks are built on it, `finalized` with its block, `dropped` from the pool, or " +
+				"`replaced` by another transaction of the sender with the same nonce. The first request starts tracking the " +
+				"transaction, as does broadcasting it; its status is then updated at every new head block. Statuses are " +
+				"kept for a while after they stop changing.",
+			OperationID: "getTransactionStatus",
+			Parameters: []openapi.Parameter{{
+				Name:        "hash",
+				In:          "path",
+				Description: "Transaction hash, 0x-prefixed hex",
+				Required:    true,
+				Schema:      &openapi.Schema{Type: "string", Pattern: "^0x[0-9a-fA-F]{64}$"},
+			}},
+			Responses: map[string]*openapi.Response{
+				"200": {
+					Description: "Transaction status",
+					Headers:     map[string]*openapi.Header{},
+					Content:     jsonContent(envelope(b.Schema(response.TransactionStatusResponse{}))),
+				},
+				"400": errorRef("BadRequest"),
+				"404": errorRef("NotFound"),
+				"502": errorRef("BadGateway"),
+				"503": errorRef("ServiceUnavailable"),
+			},
+		}
+	})
+
+	versioned(http.MethodPost, "/ethereum/tx/send", func(string) *openapi.Operation {
+		return &openapi.Operation{
+			Tags:    []string{"ethereum"},
+			Summary: "Broadcast a signed transaction",
+			Description: "Decodes a signed raw transaction and checks it before submitting it to the node: it must be replay " +
+				"protected with the network's chain ID, its nonce must be unused and leave no gap after the sender's pending " +
+				"transactions, its fee cap must cover the current base fee and the sender's balance its maximum cost. " +
+				"Transactions that fail a check, or that the node's pool rejects, get 400. Resubmitting a pending " +
+				"transaction succeeds.",
+			OperationID: "sendTransaction",
+			RequestBody: &openapi.RequestBody{
+				Required: true,
+				Content:  jsonContent(b.Schema(handler.SendTransactionRequest{})),
+			},
+			Responses: map[string]*openapi.Response{
+				"200": {
+					Description: "Transaction submitted",
+					Headers:     map[string]*openapi.Header{},
+					Content:     jsonContent(envelope(b.Schema(response.SentTransactionResponse{}))),
+				},
+				"400": errorRef("BadRequest"),
+				"502": errorRef("BadGateway"),
+				"503": errorRef("ServiceUnavailable"),
+			},
+		}
+	})
+
+	versioned(http.MethodPost, "/ethereum/simulate", func(string) *openapi.Operation {
+		return &openapi.Operation{
+			Tags:    []string{"ethereum"},
+			Summary: "Simulate a transaction",
+			Description: "Executes a transaction with `eth_call` on the state of a block, optionally with overridden account " +
+				"state and block fields, without broadcasting it. Reverts are decoded as `Error(string)`, `Panic(uint256)` " +
+				"or a custom error of an ABI registered for a called contract. Gas used and balance changes come from a call " +
+				"trace (`traced: true`); nodes without `debug_traceCall` give a gas estimate and only the transaction's own " +
+				"value transfer and fee. A transaction that reverts or fails is a successful simulation with `success: false`.",
+			OperationID: "simulateTransaction",
+			RequestBody: &openapi.RequestBody{
+				Required: true,
+				Content:  jsonContent(b.Schema(handler.SimulateRequest{})),
+			},
+			Responses: map[string]*openapi.Response{
+				"200": {
+					Description: "Simulation outcome",
+					Headers:     map[string]*openapi.Header{},
+					Content:     jsonContent(envelope(b.Schema(response.SimulationResponse{}))),
+				},
+				"400": errorRef("BadRequest"),
+				"404": errorRef("NotFound"),
+				"502": errorRef("BadGateway"),
+				"503": errorRef("ServiceUnavailable"),
+			},
+		}
+	})
+
+	// ABI registry
+	versioned(http.MethodGet, "/ethereum/:address/abi", func(string) *openapi.Operation {
+		return &openapi.Operation{
+			Tags:        []string{"ethereum"},
+			Summary:     "Get the registered ABI of a contract",
+			Description: "Returns the ABI registered for an address. Addresses without one get 404.",
+			OperationID: "getABI",
+			Parameters:  []openapi.Parameter{addressParam},
+			Responses: map[string]*openapi.Response{
+				"200": {
+					Description: "Registered ABI",
+					Headers:     map[string]*openapi.Header{},
+					Content:     jsonContent(envelope(b.Schema(response.ContractABIResponse{}))),
+				},
+				"400": errorRef("BadRequest"),
+				"404": errorRef("NotFound"),
+			},
+		}
+	})
+	versioned(http.MethodPut, "/ethereum/:address/abi", func(string) *openapi.Operation {
+		return &openapi.Operation{
+			Tags:    []string{"ethereum"},
+			Summary: "Register the ABI of a contract",
+			Description: "Registers a JSON ABI, or a single entry of one, for an address, replacing any previous one. " +
+				"Transaction inputs and logs of the address are then decoded with it.",
+			OperationID: "saveABI",
+			Parameters:  []openapi.Parameter{addressParam},
+			RequestBody: &openapi.RequestBody{
+				Required: true,
+				Content: jsonContent(&openapi.Schema{OneOf: []*openapi.Schema{
+					{Type: "array", Items: &openapi.Schema{Type: "object"}},
+					{Type: "object"},
+				}}),
+			},
+			Responses: map[string]*openapi.Response{
+				"200": {
+					Description: "Registered ABI",
+					Headers:     map[string]*openapi.Header{},
+					Content:     jsonContent(envelope(b.Schema(response.ContractABIResponse{}))),
+				},
+				"400": errorRef("BadRequest"),
+			},
+		}
+	})
+	versioned(http.MethodDelete, "/ethereum/:address/abi", func(string) *openapi.Operation {
+		return &openapi.Operation{
+			Tags:        []string{"ethereum"},
+			Summary:     "Remove the registered ABI of a contract",
+			Description: "Removes the ABI registered for an address. Addresses without one get 404.",
+			OperationID: "deleteABI",
+			Parameters:  []openapi.Parameter{addressParam},
+			Responses: map[string]*openapi.Response{
+				"204": {Description: "ABI removed", Headers: map[string]*openapi.Header{}},
+				"400": errorRef("BadRequest"),
+				"404": errorRef("NotFound"),
+			},
+		}
+	})
+
+	rpcRequest := b.Schema(handler.RPCRequest{})
+	rpcResponse := b.Schema(response.RPCResponse{})
+	rpc := &openapi.Operation{
+		Tags:    []string{"rpc"},
+		Summary: "Ethereum JSON-RPC proxy",
+		Description: "Forwards standard JSON-RPC 2.0 requests, single or batched, to the Ethereum node so libraries such as ethers " +
+			"and viem can use this service as their provider. Only allowlisted read methods are forwarded; others fail with " +
+			"code -32601. Results are cached briefly and calls fail over to fallback nodes. Errors are reported in JSON-RPC " +
+			"terms with status 200.",
+		OperationID: "rpc",
+		RequestBody: &openapi.RequestBody{
+			Required: true,
+			Content: jsonContent(&openapi.Schema{OneOf: []*openapi.Schema{
+				rpcRequest,
+				{Type: "array", Items: rpcRequest},
+			}}),
+		},
+		Responses: map[string]*openapi.Response{
+			"200": {
+				Description: "A response, or an array of responses to a batch",
+				Content: jsonContent(&openapi.Schema{OneOf: []*openapi.Schema{
+					rpcResponse,
+					{Type: "array", Items: rpcResponse},
+				}}),
+			},
+			"204": {Description: "The request only contained notifications"},
+		},
+	}
+
+	graphQLResponse := &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"data":   {Type: "object"},
+			"errors": {Type: "array", Items: &openapi.Schema{Type: "object"}},
+		},
+	}
+	graphQLDescription := "Queries accounts (balance, nonce, code, ERC-20 token balances), blocks, transactions and logs. " +
+		"Lookups of the same kind are batched per query, so nested lists cost one node round trip per level. " +
+		"Field errors are reported in `errors` with status 200, with the API error code in `extensions.code`."
+	graphQLResponses := func() map[string]*openapi.Response {
+		return map[string]*openapi.Response{
+			"200": {Description: "The query result", Content: jsonContent(graphQLResponse)},
+			"400": {Description: "The request couldn't be read", Content: jsonContent(graphQLResponse)},
+		}
+	}
+
+	liveness := &openapi.Operation{
+		Tags:    []string{"health"},
+		Summary: "Liveness probe",
+		Responses: map[string]*openapi.Response{
+			"200": {
+				Description: "The process is running",
+				Content: jsonContent(&openapi.Schema{
+					Type:       "object",
+					Properties: map[string]*openapi.Schema{"status": {Type: "string", Enum: []interface{}{"ok"}}},
+					Required:   []string{"status"},
+				}),
+			},
+		},
+	}
+
+	maps.Copy(docs, map[string]*openapi.Operation{
+		"GET /health":      liveness,
+		"GET /health/live": liveness,
+		"GET /health/ready": {
+			Tags:        []string{"health"},
+			Summary:     "Readiness probe",
+			Description: "Checks connectivity, chain ID, sync state and head block age of the Ethereum node.",
+			Responses: map[string]*openapi.Response{
+				"200": {Description: "All checks passed", Content: jsonContent(b.Schema(response.HealthReportResponse{}))},
+				"503": {Description: "A check failed", Content: jsonContent(b.Schema(response.HealthReportResponse{}))},
+			},
+		},
+		"POST /rpc": rpc,
+		"GET /graphql": {
+			Tags:        []string{"graphql"},
+			Summary:     "GraphQL query (GET)",
+			Description: graphQLDescription,
+			OperationID: "graphqlGet",
+			Parameters: []openapi.Parameter{
+				{Name: "query", In: "query", Required: true, Schema: &openapi.Schema{Type: "string"}},
+				{Name: "operationName", In: "query", Schema: &openapi.Schema{Type: "string"}},
+				{Name: "variables", In: "query", Description: "JSON-encoded variables", Schema: &openapi.Schema{Type: "string"}},
+			},
+			Responses: graphQLResponses(),
+		},
+		"POST /graphql": {
+			Tags:        []string{"graphql"},
+			Summary:     "GraphQL query",
+			Description: graphQLDescription,
+			OperationID: "graphql",
+			RequestBody: &openapi.RequestBody{
+				Required: true,
+				Content:  jsonContent(b.Schema(handler.GraphQLRequest{})),
+			},
+			Responses: graphQLResponses(),
+		},
+		"GET /debug/ping": {
+			Tags:      []string{"debug"},
+			Summary:   "Ping (debug mode only)",
+			Responses: map[string]*openapi.Response{"200": {Description: "pong", Content: textContent()}},
+		},
+		"GET /debug/vars": {
+			Tags:      []string{"debug"},
+			Summary:   "Runtime metrics (debug mode only)",
+			Responses: map[string]*openapi.Response{"200": {Description: "expvar metrics", Content: jsonContent(&openapi.Schema{Type: "object"})}},
+		},
+	})
+	return docs
+}
+
+// markDeprecated documents an operation as a deprecated unversioned alias,
+// with the deprecation headers of its successful responses
+func markDeprecated(op *openapi.Operation) {
+	op.Deprecated = true
+	op.Description += " Use /api/v1 or /api/v2 instead; this alias is removed at its Sunset date."
+	for status, resp := range op.Responses {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		for name, desc := range map[string]string{
+			"Deprecation": "Date the route was deprecated, as @<unix seconds>",
+			"Sunset":      "Date the route will be removed",
+			"Link":        "The successor-version route",
+		} {
+			resp.Headers[name] = &openapi.Header{Description: desc, Schema: &openapi.Schema{Type: "string"}}
+		}
+	}
+}
+
+// addressInfoFormats holds the address response format of each API version
+var addressInfoFormats = map[string]interface{}{
+	response.V1: response.AddressInfoResponse{},
+	response.V2: response.AddressInfoV2Response{},
+}
+
+// envelope returns the schema of a successful Response carrying data
+func envelope(data *openapi.Schema) *openapi.Schema {
+	return &openapi.Schema{AllOf: []*openapi.Schema{
+		{Ref: "#/components/schemas/Response"},
+		{
+			Type:       "object",
+			Properties: map[string]*openapi.Schema{"data": data},
+			Required:   []string{"data"},
+		},
+	}}
+}
+
+// jsonContent returns JSON body content with the given schema
+func jsonContent(schema *openapi.Schema) map[string]openapi.MediaType {
+	return map[string]openapi.MediaType{"application/json": {Schema: schema}}
+}
+
+// textContent returns plain text body content
+func textContent() map[string]openapi.MediaType {
+	return map[string]openapi.MediaType{"text/plain": {Schema: &openapi.Schema{Type: "string"}}}
+}
+
+// addPathParameters adds a string parameter for each path parameter of a Gin
+// path that the operation doesn't already document
+func addPathParameters(op *openapi.Operation, path string) {
+	for _, segment := range strings.Split(path, "/") {
+		if len(segment) < 2 || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+		name := segment[1:]
+
+		documented := false
+		for _, p := range op.Parameters {
+			if p.In == "path" && p.Name == name {
+				documented = true
+			}
+		}
+		if !documented {
+			op.Parameters = append(op.Parameters, openapi.Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &openapi.Schema{Type: "string"},
+			})
+		}
+	}
+}
+
+// openAPIPath converts Gin's :param and *param syntax to {param}
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if len(segment) > 1 && (segment[0] == ':' || segment[0] == '*') {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -1,14 +1,21 @@
 package router
 
 import (
+	"expvar"
+	"fmt"
+	"net/http"
+	"path"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 
+	"github.com/project-exam/pkg/domain/repository"
 	"github.com/project-exam/pkg/infrastructure/config"
+	"github.com/project-exam/pkg/infrastructure/jwks"
 	"github.com/project-exam/pkg/interface/api/handler"
 	"github.com/project-exam/pkg/interface/api/middleware"
+	"github.com/project-exam/pkg/interface/api/response"
 )
 
 // Router manages the routes for the API
@@ -16,11 +23,47 @@ type Router struct {
 	config          *config.Config
 	engine          *gin.Engine
 	ethereumHandler *handler.EthereumHandler
+	healthHandler   *handler.HealthHandler
+	rpcHandler      *handler.RPCHandler
+	graphQLHandler  *handler.GraphQLHandler
+	logsHandler     *handler.LogsHandler
+	txHandler       *handler.TransactionHandler
+	abiHandler      *handler.ABIHandler
+	jwtKeys         *jwks.KeySet
+	auditRepo       repository.AuditRepository
 	logger          *logrus.Logger
+
+	// Scopes required by each protected route, keyed by method and path,
+	// for the API documentation
+	routeScopes map[string][]string
+
+	// Access policy components, shared with other transports through AccessControl
+	clientIPs      *middleware.ClientIPResolver
+	authenticators []middleware.Authenticator
+
+	// Components whose settings can be reloaded at runtime
+	rateLimiter *middleware.RateLimiter
+	apiKeyAuth  *middleware.APIKeyAuth
+	cors        *middleware.CORS
+	allowList   *middleware.IPList
+	denyList    *middleware.IPList
 }
 
-// NewRouter creates a new router with the given configuration and handlers
-func NewRouter(cfg *config.Config, ethereumHandler *handler.EthereumHandler, logger *logrus.Logger) *Router {
+// NewRouter creates a new router with the given configuration and handlers.
+// jwtKeys and auditRepo may be nil when bearer tokens or auditing are disabled.
+func NewRouter(
+	cfg *config.Config,
+	ethereumHandler *handler.EthereumHandler,
+	healthHandler *handler.HealthHandler,
+	rpcHandler *handler.RPCHandler,
+	graphQLHandler *handler.GraphQLHandler,
+	logsHandler *handler.LogsHandler,
+	txHandler *handler.TransactionHandler,
+	abiHandler *handler.ABIHandler,
+	jwtKeys *jwks.KeySet,
+	auditRepo repository.AuditRepository,
+	logger *logrus.Logger,
+) (*Router, error) {
 	// Set Gin mode based on configuration
 	if gin.Mode() == gin.DebugMode && cfg.Server.Mode == "release" {
 		gin.SetMode(gin.ReleaseMode)
@@ -28,105 +71,333 @@ func NewRouter(cfg *config.Config, ethereumHandler *handler.EthereumHandler, log
 
 	engine := gin.New() // Don't use Default() as we're adding our own middleware
 
+	// Client IPs are resolved by our own middleware; make Gin's fallback use the peer address
+	if err := engine.SetTrustedProxies(nil); err != nil {
+		return nil, err
+	}
+
 	router := &Router{
 		config:          cfg,
 		engine:          engine,
 		ethereumHandler: ethereumHandler,
+		healthHandler:   healthHandler,
+		rpcHandler:      rpcHandler,
+		graphQLHandler:  graphQLHandler,
+		logsHandler:     logsHandler,
+		txHandler:       txHandler,
+		abiHandler:      abiHandler,
+		jwtKeys:         jwtKeys,
+		auditRepo:       auditRepo,
 		logger:          logger,
+		routeScopes:     make(map[string][]string),
 	}
 
 	// Register middleware and routes
-	router.setupMiddleware()
-	router.registerRoutes()
+	if err := router.setupMiddleware(); err != nil {
+		return nil, err
+	}
+	if err := router.registerRoutes(); err != nil {
+		return nil, err
+	}
 
-	return router
+	return router, nil
 }
 
 // setupMiddleware adds global middleware to the router
-func (r *Router) setupMiddleware() {
+func (r *Router) setupMiddleware() error {
 	// Recovery middleware
 	r.engine.Use(middleware.Recovery(r.logger))
 
+	// Resolve the real client IP behind trusted proxies
+	ipCfg := r.config.Server.ClientIP
+	var err error
+	r.clientIPs, err = middleware.NewClientIPResolver(ipCfg.TrustedProxies, ipCfg.Header)
+	if err != nil {
+		return fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	r.engine.Use(r.clientIPs.Middleware())
+
 	// Request logging
 	r.engine.Use(middleware.RequestLogger(r.logger))
 
+	// Reject denied networks
+	if r.allowList, err = middleware.NewIPList(ipCfg.AllowCIDRs...); err != nil {
+		return fmt.Errorf("invalid IP allow list: %w", err)
+	}
+	if r.denyList, err = middleware.NewIPList(ipCfg.DenyCIDRs...); err != nil {
+		return fmt.Errorf("invalid IP deny list: %w", err)
+	}
+	r.engine.Use(middleware.IPFilter(r.allowList, r.denyList))
+
 	// Security headers
 	r.engine.Use(middleware.SecurityHeaders())
 
 	// CORS middleware
-	r.engine.Use(middleware.CORS())
+	r.cors = middleware.NewCORS(corsOptions(r.config.Server.CORS))
+	r.engine.Use(r.cors.Handler())
+
+	// Response compression (gzip/brotli) for bodies of 1KB or more
+	r.engine.Use(middleware.Compress(1024))
 
 	// Request size limiter (10MB)
 	r.engine.Use(middleware.RequestSizeLimiter(10 * 1024 * 1024))
 
-	// Request timeout (30 seconds global timeout)
-	r.engine.Use(middleware.Timeout(30 * time.Second))
+	// Request timeout, with per-route overrides
+	r.engine.Use(middleware.Timeout(r.config.Server.RequestTimeout, r.config.Server.RouteTimeouts))
+
+	return nil
 }
 
 // registerRoutes registers all API routes
-func (r *Router) registerRoutes() {
-	// Create rate limiter
-	rateLimiter := middleware.NewRateLimiter(
+func (r *Router) registerRoutes() error {
+	// Create rate limiter, exempting configured networks
+	r.rateLimiter = middleware.NewRateLimiter(
 		r.config.Server.RateLimit.Limit,
 		r.config.Server.RateLimit.Window,
 	)
-
-	// Add localhost to rate limiter whitelist for development
-	if gin.Mode() == gin.DebugMode {
-		rateLimiter.AddToWhitelist("127.0.0.1")
-		rateLimiter.AddToWhitelist("::1")
+	if err := r.rateLimiter.SetWhitelist(rateLimitExemptions(r.config)); err != nil {
+		return fmt.Errorf("invalid rate limit exemption: %w", err)
 	}
 
-	// Create API key auth middleware if enabled
-	var apiKeyAuth *middleware.APIKeyAuth
-	if r.config.Server.Auth.Enabled {
-		apiKeyAuth = middleware.NewAPIKeyAuth()
+	// Charge each call of a JSON-RPC batch, not just its request
+	r.rpcHandler.SetCallLimit(func(c *gin.Context, n int) int {
+		return r.rateLimiter.AllowN(middleware.ClientIP(c), n)
+	})
 
-		// Add development API key if in debug mode
-		if gin.Mode() == gin.DebugMode {
-			apiKeyAuth.AddAPIKey("development-api-key", "dev-user")
-		}
+	// Create authenticators if enabled: API keys, plus bearer tokens when configured
+	if r.config.Server.Auth.Enabled {
+		r.apiKeyAuth = middleware.NewAPIKeyAuth()
+		r.apiKeyAuth.ReplaceAPIKeys(apiKeys(r.config))
+		r.authenticators = append(r.authenticators, r.apiKeyAuth)
 
-		// Add configured API keys
-		for key, userID := range r.config.Server.Auth.APIKeys {
-			apiKeyAuth.AddAPIKey(key, userID)
+		if jwtCfg := r.config.Server.Auth.JWT; jwtCfg.Enabled && r.jwtKeys != nil {
+			r.authenticators = append(r.authenticators, middleware.NewJWTAuth(r.jwtKeys, middleware.JWTOptions{
+				Issuer:             jwtCfg.Issuer,
+				Audience:           jwtCfg.Audience,
+				UserClaim:          jwtCfg.UserClaim,
+				ScopesClaim:        jwtCfg.ScopesClaim,
+				Leeway:             jwtCfg.Leeway,
+				AllowMissingExpiry: !jwtCfg.RequireExpiry,
+			}))
 		}
 	}
 
-	// Health check endpoint - no rate limiting or auth
-	r.engine.GET("/health", r.ethereumHandler.HealthCheck)
+	// Health check endpoints - no rate limiting or auth
+	r.engine.GET("/health", r.healthHandler.Live)
+	r.engine.GET("/health/live", r.healthHandler.Live)
+	r.engine.GET("/health/ready", r.healthHandler.Ready)
 
 	// API routes group
-	api := r.engine.Group("/api")
+	api := r.engine.Group("/api", r.apiMiddleware()...)
 
-	// Apply rate limiting to all API routes
-	api.Use(rateLimiter.Limit())
+	// Versioned routes; the version selects the response format
+	r.registerAPIRoutes(api.Group("/v1", middleware.APIVersion(response.V1)))
+	r.registerAPIRoutes(api.Group("/v2", middleware.APIVersion(response.V2)))
+
+	// Unversioned alias of v1, kept for existing clients until its sunset
+	// date. It only serves the routes that predate versioning.
+	deprecation := r.config.Server.Deprecation
+	alias := api.Group("",
+		middleware.APIVersion(response.V1),
+		middleware.Deprecated(middleware.DeprecationOptions{
+			Date:      deprecation.Date,
+			Sunset:    deprecation.Sunset,
+			Prefix:    "/api",
+			Successor: "/api/" + response.V1,
+		}),
+	)
+	r.registerAddressInfoRoute(alias.Group("/ethereum"))
+
+	// Standard JSON-RPC endpoint for Ethereum libraries, protected like the API routes
+	protected := r.engine.Group("", r.apiMiddleware()...)
+	r.handle(protected, http.MethodPost, "/rpc", []string{middleware.ScopeReadRPC}, r.rpcHandler.Handle)
+
+	// GraphQL endpoint over accounts, blocks and transactions, requiring the
+	// scopes of both address and transaction lookups
+	graphQLScopes := []string{middleware.ScopeReadAddress, middleware.ScopeReadTx}
+	r.handle(protected, http.MethodGet, "/graphql", graphQLScopes, r.graphQLHandler.Handle)
+	r.handle(protected, http.MethodPost, "/graphql", graphQLScopes, r.graphQLHandler.Handle)
+
+	// Other potential groups
+	if gin.Mode() == gin.DebugMode {
+		// Debug endpoints only available in debug mode
+		debug := r.engine.Group("/debug")
+		debug.GET("/ping", func(c *gin.Context) {
+			c.String(200, "pong")
+		})
+		// Runtime metrics, including configuration reload counters
+		debug.GET("/vars", gin.WrapH(expvar.Handler()))
+	}
+
+	// API documentation, generated from the routes registered above
+	docsHandler, err := handler.NewDocsHandler(r.openAPIDocument())
+	if err != nil {
+		return fmt.Errorf("failed to build OpenAPI document: %w", err)
+	}
+	r.engine.GET("/openapi.json", docsHandler.Spec)
+	r.engine.GET("/docs", func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, "/docs/")
+	})
+	r.engine.GET("/docs/*filepath", docsHandler.UI)
+
+	return nil
+}
+
+// apiMiddleware returns the middleware protecting API routes: rate limiting,
+// authentication and auditing when enabled, and JSON request bodies
+func (r *Router) apiMiddleware() []gin.HandlerFunc {
+	handlers := []gin.HandlerFunc{r.rateLimiter.Limit()}
 
-	// Apply API key authentication if enabled
 	if r.config.Server.Auth.Enabled {
-		api.Use(apiKeyAuth.Authenticate())
+		handlers = append(handlers, middleware.Authenticate(r.authenticators...))
+
+		if r.auditRepo != nil {
+			handlers = append(handlers, middleware.AuditLog(r.auditRepo, r.logger))
+		}
 	}
 
 	// Apply content type enforcer for non-GET requests
-	api.Use(middleware.ContentTypeEnforcer())
+	return append(handlers, middleware.ContentTypeEnforcer())
+}
 
+// registerAPIRoutes registers the API routes on a version group
+func (r *Router) registerAPIRoutes(api *gin.RouterGroup) {
 	// Ethereum routes
 	ethereum := api.Group("/ethereum")
 	{
-		// Cache GET requests for 5 seconds
-		ethereum.GET("/:address", middleware.CacheControl(5*time.Second), r.ethereumHandler.GetAddressInfo)
+		r.registerAddressInfoRoute(ethereum)
+
+		// Cache other GET requests for 5 seconds
+		r.handle(ethereum, http.MethodGet, "/:address/contract", addressScopes,
+			middleware.CacheControl(5*time.Second),
+			r.ethereumHandler.GetContractInfo,
+		)
+		r.handle(ethereum, http.MethodGet, "/:address/storage/:slot", addressScopes,
+			middleware.CacheControl(5*time.Second),
+			r.ethereumHandler.GetStorage,
+		)
+		r.handle(ethereum, http.MethodGet, "/:address/proof", addressScopes,
+			middleware.CacheControl(5*time.Second),
+			r.ethereumHandler.GetProof,
+		)
+
+		// Log queries are transaction data; they aren't cached as their range may end at the head block
+		r.handle(ethereum, http.MethodPost, "/logs", txScopes, r.logsHandler.GetLogs)
+
+		// Transactions, with input and logs decoded by the ABI registry
+		r.handle(ethereum, http.MethodGet, "/tx/:hash", txScopes, r.txHandler.GetTransaction)
+		r.handle(ethereum, http.MethodGet, "/tx/:hash/status", txScopes, r.txHandler.GetTransactionStatus)
+		r.handle(ethereum, http.MethodPost, "/tx/send", []string{middleware.ScopeWriteBroadcast}, r.txHandler.SendTransaction)
+
+		// Simulations run against node state without changing it, like other reads
+		r.handle(ethereum, http.MethodPost, "/simulate", txScopes, r.txHandler.SimulateTransaction)
+
+		// ABI registry; registered ABIs decode the transactions and logs of their contract
+		abiScopes := []string{middleware.ScopeWriteABI}
+		r.handle(ethereum, http.MethodGet, "/:address/abi", addressScopes, r.abiHandler.GetABI)
+		r.handle(ethereum, http.MethodPut, "/:address/abi", abiScopes, r.abiHandler.SaveABI)
+		r.handle(ethereum, http.MethodDelete, "/:address/abi", abiScopes, r.abiHandler.DeleteABI)
 	}
+}
 
-	// Other potential groups
+// addressScopes and txScopes are the scopes of address and transaction lookups
+var (
+	addressScopes = []string{middleware.ScopeReadAddress}
+	txScopes      = []string{middleware.ScopeReadTx}
+)
+
+// registerAddressInfoRoute registers the address info route on an Ethereum
+// group. Address info includes the pending nonce, which wallets need fresh
+// right after a broadcast, so it is revalidated on every request against its
+// ETag instead of being cached.
+func (r *Router) registerAddressInfoRoute(ethereum *gin.RouterGroup) {
+	r.handle(ethereum, http.MethodGet, "/:address", addressScopes,
+		middleware.CacheControl(0),
+		r.ethereumHandler.GetAddressInfo,
+	)
+}
+
+// handle registers a route on a group behind a middleware enforcing scopes,
+// and records the scopes for the API documentation
+func (r *Router) handle(group *gin.RouterGroup, method, relativePath string, scopes []string, handlers ...gin.HandlerFunc) {
+	r.routeScopes[method+" "+path.Join(group.BasePath(), relativePath)] = scopes
+	group.Handle(method, relativePath, append([]gin.HandlerFunc{r.requireScopes(scopes...)}, handlers...)...)
+}
+
+// ApplyConfig applies the reloadable settings of cfg to the running router:
+// rate limits, API keys, CORS policy and IP allow/deny lists. Other settings
+// require a restart.
+func (r *Router) ApplyConfig(cfg *config.Config) error {
+	ipCfg := cfg.Server.ClientIP
+	if err := r.allowList.Replace(ipCfg.AllowCIDRs); err != nil {
+		return fmt.Errorf("invalid IP allow list: %w", err)
+	}
+	if err := r.denyList.Replace(ipCfg.DenyCIDRs); err != nil {
+		return fmt.Errorf("invalid IP deny list: %w", err)
+	}
+
+	r.rateLimiter.SetLimit(cfg.Server.RateLimit.Limit, cfg.Server.RateLimit.Window)
+	if err := r.rateLimiter.SetWhitelist(rateLimitExemptions(cfg)); err != nil {
+		return fmt.Errorf("invalid rate limit exemption: %w", err)
+	}
+
+	if r.apiKeyAuth != nil {
+		r.apiKeyAuth.ReplaceAPIKeys(apiKeys(cfg))
+	}
+
+	r.cors.Update(corsOptions(cfg.Server.CORS))
+
+	return nil
+}
+
+// rateLimitExemptions returns the configured exempt networks, plus localhost for development
+func rateLimitExemptions(cfg *config.Config) []string {
+	exempt := append([]string(nil), cfg.Server.RateLimit.ExemptCIDRs...)
 	if gin.Mode() == gin.DebugMode {
-		// Debug endpoints only available in debug mode
-		debug := r.engine.Group("/debug")
-		debug.GET("/ping", func(c *gin.Context) {
-			c.String(200, "pong")
-		})
+		exempt = append(exempt, "127.0.0.1", "::1")
+	}
+	return exempt
+}
+
+// apiKeys returns the configured API keys, plus a development key in debug mode
+func apiKeys(cfg *config.Config) map[string]middleware.Principal {
+	keys := make(map[string]middleware.Principal, len(cfg.Server.Auth.APIKeys)+1)
+	if gin.Mode() == gin.DebugMode {
+		keys["development-api-key"] = middleware.Principal{UserID: "dev-user", Scopes: []string{middleware.ScopeAdmin}}
+	}
+	for key, apiKey := range cfg.Server.Auth.APIKeys {
+		keys[key] = middleware.Principal{UserID: apiKey.UserID, Scopes: apiKey.Scopes}
+	}
+	return keys
+}
+
+// corsOptions converts the CORS configuration into middleware options
+func corsOptions(cors config.CORSConfig) middleware.CORSOptions {
+	return middleware.CORSOptions{
+		AllowedOrigins:   cors.AllowedOrigins,
+		AllowedMethods:   cors.AllowedMethods,
+		AllowedHeaders:   cors.AllowedHeaders,
+		ExposedHeaders:   cors.ExposedHeaders,
+		AllowCredentials: cors.AllowCredentials,
+		MaxAge:           cors.MaxAge,
 	}
 }
 
+// requireScopes returns a middleware enforcing the scopes a route declares.
+// Scopes are only enforced when authentication is enabled.
+func (r *Router) requireScopes(scopes ...string) gin.HandlerFunc {
+	if !r.config.Server.Auth.Enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return middleware.RequireScopes(scopes...)
+}
+
+// AccessControl returns the access policy of the API routes, for enforcing
+// it on other transports. Reloaded settings apply to both.
+func (r *Router) AccessControl() *middleware.AccessControl {
+	return middleware.NewAccessControl(r.clientIPs, r.allowList, r.denyList, r.rateLimiter, r.authenticators)
+}
+
 // Engine returns the underlying Gin engine
 func (r *Router) Engine() *gin.Engine {
 	return r.engine
@@ -0,0 +1,283 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/usecase"
+)
+
+// Batching of loads: keys requested within batchWait of the first one are
+// fetched together, in batches of up to maxBatchKeys
+const (
+	batchWait    = 2 * time.Millisecond
+	maxBatchKeys = 100
+)
+
+// loader batches and caches lookups of one kind for the duration of a
+// query. Resolvers run concurrently, so loads made while resolving the
+// fields of a list are coalesced into a single fetch instead of one per item.
+type loader[K comparable, V any] struct {
+	ctx    context.Context
+	fetch  func(ctx context.Context, keys []K) ([]V, error)
+	cost   int // node calls of fetching a key, charged to budget
+	budget *budget
+
+	mu      sync.Mutex
+	results map[K]*loadResult[V]
+	pending *loadBatch[K, V]
+}
+
+// loadResult is the outcome of loading a key, available once done is closed
+type loadResult[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// loadBatch is a set of keys waiting to be fetched together
+type loadBatch[K comparable, V any] struct {
+	keys    []K
+	results []*loadResult[V]
+}
+
+// newLoader creates a loader fetching keys with fetch, which returns values
+// in the order of keys. Fetches use ctx, the context of the query, and each
+// key fetched costs cost of the query's budget.
+func newLoader[K comparable, V any](ctx context.Context, b *budget, cost int, fetch func(context.Context, []K) ([]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		ctx:     ctx,
+		fetch:   fetch,
+		cost:    cost,
+		budget:  b,
+		results: make(map[K]*loadResult[V]),
+	}
+}
+
+// Load returns the value of key, fetching it with other keys requested at
+// about the same time unless it was already loaded. Keys past the query's
+// budget aren't fetched.
+func (l *loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	result, ok := l.results[key]
+	if !ok {
+		if err := l.budget.charge(l.cost); err != nil {
+			l.mu.Unlock()
+			var zero V
+			return zero, err
+		}
+		result = &loadResult[V]{done: make(chan struct{})}
+		l.results[key] = result
+
+		if l.pending == nil {
+			batch := &loadBatch[K, V]{}
+			l.pending = batch
+			time.AfterFunc(batchWait, func() { l.dispatchPending(batch) })
+		}
+		batch := l.pending
+		batch.keys = append(batch.keys, key)
+		batch.results = append(batch.results, result)
+		if len(batch.keys) >= maxBatchKeys {
+			l.pending = nil
+			go l.dispatch(batch)
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-result.done:
+		return result.value, result.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// Prime caches values fetched by other means, so loading them is free
+func (l *loader[K, V]) Prime(key K, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.results[key]; !ok {
+		result := &loadResult[V]{done: make(chan struct{}), value: value}
+		close(result.done)
+		l.results[key] = result
+	}
+}
+
+// dispatchPending fetches batch when its wait is over, unless it was
+// already dispatched for being full
+func (l *loader[K, V]) dispatchPending(batch *loadBatch[K, V]) {
+	l.mu.Lock()
+	if l.pending != batch {
+		l.mu.Unlock()
+		return
+	}
+	l.pending = nil
+	l.mu.Unlock()
+
+	l.dispatch(batch)
+}
+
+// dispatch fetches the keys of batch and completes their results. A failed
+// fetch fails every key of the batch.
+func (l *loader[K, V]) dispatch(batch *loadBatch[K, V]) {
+	values, err := l.fetch(l.ctx, batch.keys)
+	for i, result := range batch.results {
+		if err != nil {
+			result.err = err
+		} else if i < len(values) {
+			result.value = values[i]
+		}
+		close(result.done)
+	}
+}
+
+// budget bounds the node calls and top-level fields of a query. List fields
+// multiply the calls of their children, so depth and argument limits alone
+// don't bound a query's cost; once it runs out, the query is cancelled and
+// fails as a whole.
+type budget struct {
+	maxCost   int64
+	maxFields int64
+	cancel    context.CancelFunc
+
+	cost     atomic.Int64
+	fields   atomic.Int64
+	exceeded atomic.Pointer[queryError]
+}
+
+// charge spends cost node calls of the budget, failing once it is exceeded
+func (b *budget) charge(cost int) error {
+	if err := b.exceeded.Load(); err != nil {
+		return err
+	}
+	if b.cost.Add(int64(cost)) > b.maxCost {
+		return b.exceed(fmt.Sprintf("Query exceeds the limit of %d node calls", b.maxCost))
+	}
+	return nil
+}
+
+// field counts a top-level field, aliases included, failing once there are
+// too many
+func (b *budget) field() error {
+	if err := b.exceeded.Load(); err != nil {
+		return err
+	}
+	if b.fields.Add(1) > b.maxFields {
+		return b.exceed(fmt.Sprintf("Query exceeds the limit of %d top-level fields", b.maxFields))
+	}
+	return nil
+}
+
+// exceed records the first limit the query exceeded and cancels it
+func (b *budget) exceed(message string) error {
+	b.exceeded.CompareAndSwap(nil, argumentError(message).(*queryError))
+	b.cancel()
+	return b.exceeded.Load()
+}
+
+// err returns the limit the query exceeded, nil if it stayed within its budget
+func (b *budget) err() *queryError {
+	return b.exceeded.Load()
+}
+
+// tokenHolding identifies the balance of an account in a token
+type tokenHolding struct {
+	owner string
+	token string
+}
+
+// loaders holds the loaders of a query, the use case they load from and the
+// query's budget
+type loaders struct {
+	useCase usecase.ChainUseCase
+	budget  *budget
+
+	mu        sync.Mutex
+	addresses []string // accounts the query looks up by address, for the audit log
+
+	balance       *loader[string, *big.Int]
+	nonce         *loader[string, uint64]
+	code          *loader[string, []byte]
+	blockByNumber *loader[uint64, *entity.Block]
+	blockByHash   *loader[string, *entity.Block]
+	transaction   *loader[string, *entity.Transaction]
+	receipt       *loader[string, *entity.Receipt]
+	token         *loader[string, *entity.Token]
+	tokenBalance  *loader[tokenHolding, *big.Int]
+}
+
+// newLoaders creates the loaders of a query executed with ctx, spending b
+func newLoaders(ctx context.Context, useCase usecase.ChainUseCase, b *budget) *loaders {
+	return &loaders{
+		useCase:       useCase,
+		budget:        b,
+		balance:       newLoader(ctx, b, 1, useCase.GetBalances),
+		nonce:         newLoader(ctx, b, 1, useCase.GetNonces),
+		code:          newLoader(ctx, b, 1, useCase.GetCodes),
+		blockByNumber: newLoader(ctx, b, 1, useCase.GetBlocksByNumber),
+		blockByHash:   newLoader(ctx, b, 1, useCase.GetBlocksByHash),
+		transaction:   newLoader(ctx, b, 1, useCase.GetTransactions),
+		receipt:       newLoader(ctx, b, 1, useCase.GetReceipts),
+		token:         newLoader(ctx, b, 3, useCase.GetTokens), // name, symbol and decimals
+		tokenBalance: newLoader(ctx, b, 1, func(ctx context.Context, holdings []tokenHolding) ([]*big.Int, error) {
+			owners := make([]string, len(holdings))
+			tokens := make([]string, len(holdings))
+			for i, holding := range holdings {
+				owners[i], tokens[i] = holding.owner, holding.token
+			}
+			return useCase.GetTokenBalances(ctx, owners, tokens)
+		}),
+	}
+}
+
+// lookedUp records the addresses of accounts a query looks up
+func (l *loaders) lookedUp(addresses ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.addresses = append(l.addresses, addresses...)
+}
+
+// lookedUpAddresses returns the addresses recorded with lookedUp
+func (l *loaders) lookedUpAddresses() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.addresses...)
+}
+
+// primeBlock caches a block under both its number and hash
+func (l *loaders) primeBlock(block *entity.Block) {
+	l.blockByNumber.Prime(block.Number, block)
+	l.blockByHash.Prime(normalizeHex(block.Hash), block)
+}
+
+// currentBlock returns the number of the latest block, charged to the budget
+func (l *loaders) currentBlock(ctx context.Context) (uint64, error) {
+	if err := l.budget.charge(1); err != nil {
+		return 0, err
+	}
+	latest, err := l.useCase.GetCurrentBlock(ctx)
+	if err != nil {
+		return 0, resolverError(err)
+	}
+	return latest, nil
+}
+
+// loadersKey is the context key of the query's loaders
+type loadersKey struct{}
+
+// withLoaders returns a context carrying the loaders of a query
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+// loadersFrom returns the loaders of the query being executed with ctx
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,631 @@
+package graphql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/project-exam/pkg/domain/entity"
+)
+
+// Limits on list arguments, bounding the node calls a single query can make
+const (
+	maxAccounts   = 100
+	maxBlockRange = 100
+	maxTokens     = 50
+)
+
+// queryResolver resolves the fields of Query
+type queryResolver struct{}
+
+// Account resolves an account by address
+func (*queryResolver) Account(ctx context.Context, args struct{ Address Address }) (*accountResolver, error) {
+	l := loadersFrom(ctx)
+	if err := l.budget.field(); err != nil {
+		return nil, err
+	}
+	account := newAccountResolver(string(args.Address))
+	l.lookedUp(account.address)
+	return account, nil
+}
+
+// Accounts resolves accounts by address
+func (*queryResolver) Accounts(ctx context.Context, args struct{ Addresses []Address }) ([]*accountResolver, error) {
+	l := loadersFrom(ctx)
+	if err := l.budget.field(); err != nil {
+		return nil, err
+	}
+	if len(args.Addresses) > maxAccounts {
+		return nil, argumentError("At most 100 accounts can be requested")
+	}
+
+	accounts := make([]*accountResolver, len(args.Addresses))
+	for i, address := range args.Addresses {
+		accounts[i] = newAccountResolver(string(address))
+		l.lookedUp(accounts[i].address)
+	}
+	return accounts, nil
+}
+
+// Block resolves a block by number or hash, or the latest block
+func (*queryResolver) Block(ctx context.Context, args struct {
+	Number *Long
+	Hash   *Bytes32
+}) (*blockResolver, error) {
+	l := loadersFrom(ctx)
+	if err := l.budget.field(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case args.Number != nil && args.Hash != nil:
+		return nil, argumentError("Only one of number and hash can be given")
+	case args.Hash != nil:
+		return loadBlockByHash(ctx, string(*args.Hash))
+	case args.Number != nil:
+		return loadBlockByNumber(ctx, uint64(*args.Number))
+	}
+
+	latest, err := l.currentBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return loadBlockByNumber(ctx, latest)
+}
+
+// Blocks resolves a range of blocks, fetched in a single batch
+func (*queryResolver) Blocks(ctx context.Context, args struct {
+	From Long
+	To   *Long
+}) ([]*blockResolver, error) {
+	l := loadersFrom(ctx)
+	if err := l.budget.field(); err != nil {
+		return nil, err
+	}
+
+	var to uint64
+	if args.To != nil {
+		to = uint64(*args.To)
+	} else {
+		latest, err := l.currentBlock(ctx)
+		if err != nil {
+			return nil, err
+		}
+		to = latest
+	}
+
+	from := uint64(args.From)
+	if from > to {
+		return []*blockResolver{}, nil
+	}
+	if to-from >= maxBlockRange {
+		return nil, argumentError("At most 100 blocks can be requested")
+	}
+
+	numbers := make([]uint64, 0, to-from+1)
+	for number := from; number <= to; number++ {
+		numbers = append(numbers, number)
+	}
+	if err := l.budget.charge(len(numbers)); err != nil {
+		return nil, err
+	}
+	found, err := l.useCase.GetBlocksByNumber(ctx, numbers)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+
+	blocks := make([]*blockResolver, 0, len(found))
+	for _, block := range found {
+		if block == nil {
+			break // the range reaches past the head
+		}
+		l.primeBlock(block)
+		blocks = append(blocks, &blockResolver{block: block})
+	}
+	return blocks, nil
+}
+
+// Transaction resolves a transaction by hash
+func (*queryResolver) Transaction(ctx context.Context, args struct{ Hash Bytes32 }) (*transactionResolver, error) {
+	l := loadersFrom(ctx)
+	if err := l.budget.field(); err != nil {
+		return nil, err
+	}
+	tx, err := l.transaction.Load(ctx, string(args.Hash))
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	if tx == nil {
+		return nil, nil
+	}
+	return newTransactionResolver(tx.Hash), nil
+}
+
+// accountResolver resolves the fields of Account. Every field is loaded
+// through the query's loaders, so the accounts of a list are fetched together.
+type accountResolver struct {
+	address string
+}
+
+// newAccountResolver creates an accountResolver, normalizing the address so
+// loads of the same account share a cache entry
+func newAccountResolver(address string) *accountResolver {
+	return &accountResolver{address: normalizeHex(address)}
+}
+
+// Address resolves the account's address
+func (r *accountResolver) Address() Address {
+	return Address(r.address)
+}
+
+// Balance resolves the account's balance
+func (r *accountResolver) Balance(ctx context.Context) (BigInt, error) {
+	balance, err := loadersFrom(ctx).balance.Load(ctx, r.address)
+	if err != nil {
+		return BigInt{}, resolverError(err)
+	}
+	return BigInt{value: balance}, nil
+}
+
+// Nonce resolves the account's nonce
+func (r *accountResolver) Nonce(ctx context.Context) (Long, error) {
+	nonce, err := loadersFrom(ctx).nonce.Load(ctx, r.address)
+	if err != nil {
+		return 0, resolverError(err)
+	}
+	return Long(nonce), nil
+}
+
+// Code resolves the account's contract code
+func (r *accountResolver) Code(ctx context.Context) (Bytes, error) {
+	code, err := loadersFrom(ctx).code.Load(ctx, r.address)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return code, nil
+}
+
+// IsContract resolves whether the account has code
+func (r *accountResolver) IsContract(ctx context.Context) (bool, error) {
+	code, err := r.Code(ctx)
+	return len(code) > 0, err
+}
+
+// Tokens resolves the account's balances in the given tokens
+func (r *accountResolver) Tokens(args struct{ Addresses []Address }) ([]*tokenBalanceResolver, error) {
+	if len(args.Addresses) > maxTokens {
+		return nil, argumentError("At most 50 tokens can be requested")
+	}
+
+	balances := make([]*tokenBalanceResolver, len(args.Addresses))
+	for i, token := range args.Addresses {
+		balances[i] = &tokenBalanceResolver{holding: tokenHolding{owner: r.address, token: string(token)}}
+	}
+	return balances, nil
+}
+
+// tokenBalanceResolver resolves the fields of TokenBalance
+type tokenBalanceResolver struct {
+	holding tokenHolding
+}
+
+// Token resolves the token's metadata
+func (r *tokenBalanceResolver) Token(ctx context.Context) (*tokenResolver, error) {
+	token, err := loadersFrom(ctx).token.Load(ctx, r.holding.token)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return &tokenResolver{token: token}, nil
+}
+
+// Balance resolves the balance, null when the token doesn't implement balanceOf
+func (r *tokenBalanceResolver) Balance(ctx context.Context) (*BigInt, error) {
+	balance, err := loadersFrom(ctx).tokenBalance.Load(ctx, r.holding)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return newBigInt(balance), nil
+}
+
+// tokenResolver resolves the fields of Token
+type tokenResolver struct {
+	token *entity.Token
+}
+
+// Address resolves the token's address
+func (r *tokenResolver) Address() Address {
+	return Address(normalizeHex(r.token.Address))
+}
+
+// Name resolves the token's name
+func (r *tokenResolver) Name() *string {
+	return optionalString(r.token.Name)
+}
+
+// Symbol resolves the token's symbol
+func (r *tokenResolver) Symbol() *string {
+	return optionalString(r.token.Symbol)
+}
+
+// Decimals resolves the token's decimals
+func (r *tokenResolver) Decimals() *int32 {
+	if r.token.Decimals == nil {
+		return nil
+	}
+	decimals := int32(*r.token.Decimals)
+	return &decimals
+}
+
+// blockResolver resolves the fields of Block
+type blockResolver struct {
+	block *entity.Block
+}
+
+// loadBlockByNumber loads a block by number, returning nil if it doesn't exist
+func loadBlockByNumber(ctx context.Context, number uint64) (*blockResolver, error) {
+	l := loadersFrom(ctx)
+	block, err := l.blockByNumber.Load(ctx, number)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	if block == nil {
+		return nil, nil
+	}
+	l.primeBlock(block)
+	return &blockResolver{block: block}, nil
+}
+
+// loadBlockByHash loads a block by hash, returning nil if it doesn't exist
+func loadBlockByHash(ctx context.Context, hash string) (*blockResolver, error) {
+	l := loadersFrom(ctx)
+	block, err := l.blockByHash.Load(ctx, normalizeHex(hash))
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	if block == nil {
+		return nil, nil
+	}
+	l.primeBlock(block)
+	return &blockResolver{block: block}, nil
+}
+
+// Number resolves the block number
+func (r *blockResolver) Number() Long {
+	return Long(r.block.Number)
+}
+
+// Hash resolves the block hash
+func (r *blockResolver) Hash() Bytes32 {
+	return Bytes32(normalizeHex(r.block.Hash))
+}
+
+// Parent resolves the parent block
+func (r *blockResolver) Parent(ctx context.Context) (*blockResolver, error) {
+	if r.block.Number == 0 {
+		return nil, nil
+	}
+	return loadBlockByHash(ctx, r.block.ParentHash)
+}
+
+// Timestamp resolves the block time in Unix seconds
+func (r *blockResolver) Timestamp() Long {
+	return Long(r.block.Timestamp.Unix())
+}
+
+// Miner resolves the account that received the block's fees
+func (r *blockResolver) Miner() *accountResolver {
+	return newAccountResolver(r.block.Miner)
+}
+
+// GasUsed resolves the gas used by the block
+func (r *blockResolver) GasUsed() Long {
+	return Long(r.block.GasUsed)
+}
+
+// GasLimit resolves the block's gas limit
+func (r *blockResolver) GasLimit() Long {
+	return Long(r.block.GasLimit)
+}
+
+// BaseFeePerGas resolves the block's base fee
+func (r *blockResolver) BaseFeePerGas() *BigInt {
+	return newBigInt(r.block.BaseFee)
+}
+
+// TransactionCount resolves the number of transactions in the block
+func (r *blockResolver) TransactionCount() int32 {
+	return int32(len(r.block.Transactions))
+}
+
+// Transactions resolves the block's transactions, loaded together when
+// their fields are queried
+func (r *blockResolver) Transactions() []*transactionResolver {
+	txs := make([]*transactionResolver, len(r.block.Transactions))
+	for i, hash := range r.block.Transactions {
+		txs[i] = newTransactionResolver(hash)
+	}
+	return txs
+}
+
+// transactionResolver resolves the fields of Transaction. The transaction
+// and its receipt are loaded on first use.
+type transactionResolver struct {
+	hash string
+}
+
+// newTransactionResolver creates a transactionResolver for a transaction hash
+func newTransactionResolver(hash string) *transactionResolver {
+	return &transactionResolver{hash: normalizeHex(hash)}
+}
+
+// tx loads the transaction
+func (r *transactionResolver) tx(ctx context.Context) (*entity.Transaction, error) {
+	tx, err := loadersFrom(ctx).transaction.Load(ctx, r.hash)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	if tx == nil {
+		// The transaction was dropped from the pool after it was listed
+		return nil, resolverError(entity.ErrNotFound)
+	}
+	return tx, nil
+}
+
+// receipt loads the transaction's receipt, nil while it is pending
+func (r *transactionResolver) receipt(ctx context.Context) (*entity.Receipt, error) {
+	receipt, err := loadersFrom(ctx).receipt.Load(ctx, r.hash)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return receipt, nil
+}
+
+// Hash resolves the transaction hash
+func (r *transactionResolver) Hash() Bytes32 {
+	return Bytes32(r.hash)
+}
+
+// Type resolves the transaction type
+func (r *transactionResolver) Type(ctx context.Context) (int32, error) {
+	tx, err := r.tx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int32(tx.Type), nil
+}
+
+// Nonce resolves the sender's nonce
+func (r *transactionResolver) Nonce(ctx context.Context) (Long, error) {
+	tx, err := r.tx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return Long(tx.Nonce), nil
+}
+
+// From resolves the sender
+func (r *transactionResolver) From(ctx context.Context) (*accountResolver, error) {
+	tx, err := r.tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newAccountResolver(tx.From), nil
+}
+
+// To resolves the recipient
+func (r *transactionResolver) To(ctx context.Context) (*accountResolver, error) {
+	tx, err := r.tx(ctx)
+	if err != nil || tx.To == "" {
+		return nil, err
+	}
+	return newAccountResolver(tx.To), nil
+}
+
+// Value resolves the value transferred
+func (r *transactionResolver) Value(ctx context.Context) (BigInt, error) {
+	tx, err := r.tx(ctx)
+	if err != nil {
+		return BigInt{}, err
+	}
+	return BigInt{value: tx.Value}, nil
+}
+
+// Gas resolves the gas limit of the transaction
+func (r *transactionResolver) Gas(ctx context.Context) (Long, error) {
+	tx, err := r.tx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return Long(tx.Gas), nil
+}
+
+// GasPrice resolves the gas price
+func (r *transactionResolver) GasPrice(ctx context.Context) (*BigInt, error) {
+	tx, err := r.tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newBigInt(tx.GasPrice), nil
+}
+
+// MaxFeePerGas resolves the fee cap of dynamic fee transactions
+func (r *transactionResolver) MaxFeePerGas(ctx context.Context) (*BigInt, error) {
+	tx, err := r.tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newBigInt(tx.MaxFeePerGas), nil
+}
+
+// MaxPriorityFeePerGas resolves the tip cap of dynamic fee transactions
+func (r *transactionResolver) MaxPriorityFeePerGas(ctx context.Context) (*BigInt, error) {
+	tx, err := r.tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newBigInt(tx.MaxPriorityFeePerGas), nil
+}
+
+// InputData resolves the call data
+func (r *transactionResolver) InputData(ctx context.Context) (Bytes, error) {
+	tx, err := r.tx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tx.Input, nil
+}
+
+// Block resolves the block including the transaction
+func (r *transactionResolver) Block(ctx context.Context) (*blockResolver, error) {
+	tx, err := r.tx(ctx)
+	if err != nil || tx.BlockHash == "" {
+		return nil, err
+	}
+	return loadBlockByHash(ctx, tx.BlockHash)
+}
+
+// Index resolves the position of the transaction in its block
+func (r *transactionResolver) Index(ctx context.Context) (*int32, error) {
+	tx, err := r.tx(ctx)
+	if err != nil || tx.Index == nil {
+		return nil, err
+	}
+	index := int32(*tx.Index)
+	return &index, nil
+}
+
+// Status resolves the execution status
+func (r *transactionResolver) Status(ctx context.Context) (*Long, error) {
+	receipt, err := r.receipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	status := Long(receipt.Status)
+	return &status, nil
+}
+
+// GasUsed resolves the gas used by the transaction
+func (r *transactionResolver) GasUsed(ctx context.Context) (*Long, error) {
+	receipt, err := r.receipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	gasUsed := Long(receipt.GasUsed)
+	return &gasUsed, nil
+}
+
+// EffectiveGasPrice resolves the price paid per unit of gas
+func (r *transactionResolver) EffectiveGasPrice(ctx context.Context) (*BigInt, error) {
+	receipt, err := r.receipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	return newBigInt(receipt.EffectiveGasPrice), nil
+}
+
+// CreatedContract resolves the contract created by the transaction
+func (r *transactionResolver) CreatedContract(ctx context.Context) (*accountResolver, error) {
+	receipt, err := r.receipt(ctx)
+	if err != nil || receipt == nil || receipt.ContractAddress == "" {
+		return nil, err
+	}
+	return newAccountResolver(receipt.ContractAddress), nil
+}
+
+// Logs resolves the logs emitted by the transaction
+func (r *transactionResolver) Logs(ctx context.Context) (*[]*logResolver, error) {
+	receipt, err := r.receipt(ctx)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+
+	logs := make([]*logResolver, len(receipt.Logs))
+	for i := range receipt.Logs {
+		logs[i] = &logResolver{log: &receipt.Logs[i]}
+	}
+	return &logs, nil
+}
+
+// logResolver resolves the fields of Log
+type logResolver struct {
+	log *entity.Log
+}
+
+// Index resolves the position of the log in its block
+func (r *logResolver) Index() int32 {
+	return int32(r.log.Index)
+}
+
+// Account resolves the contract that emitted the log
+func (r *logResolver) Account() *accountResolver {
+	return newAccountResolver(r.log.Address)
+}
+
+// Topics resolves the log's topics
+func (r *logResolver) Topics() []Bytes32 {
+	topics := make([]Bytes32, len(r.log.Topics))
+	for i, topic := range r.log.Topics {
+		topics[i] = Bytes32(normalizeHex(topic))
+	}
+	return topics
+}
+
+// Data resolves the log's data
+func (r *logResolver) Data() Bytes {
+	return r.log.Data
+}
+
+// Transaction resolves the transaction that emitted the log
+func (r *logResolver) Transaction() *transactionResolver {
+	return newTransactionResolver(r.log.TransactionHash)
+}
+
+// queryError is an error reported in a GraphQL response. Only the
+// client-safe message of domain errors is exposed, with the API error code
+// as the "code" extension.
+type queryError struct {
+	err error
+}
+
+// resolverError converts an error from the use case to a query error
+func resolverError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*queryError); ok {
+		return err
+	}
+	return &queryError{err: err}
+}
+
+// argumentError reports an invalid or excessive argument
+func argumentError(message string) error {
+	return &queryError{err: entity.NewError(entity.ErrCodeInvalidRequest, message, nil)}
+}
+
+// Error returns the client-safe message
+func (e *queryError) Error() string {
+	return entity.SafeMessage(e.err)
+}
+
+// Extensions returns the API error code of the error
+func (e *queryError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": string(entity.ErrorCodeOf(e.err))}
+}
+
+// Unwrap returns the underlying error
+func (e *queryError) Unwrap() error {
+	return e.err
+}
+
+// normalizeHex lowercases hex strings so they can be used as cache keys
+func normalizeHex(s string) string {
+	return strings.ToLower(s)
+}
+
+// optionalString returns nil for an empty string
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
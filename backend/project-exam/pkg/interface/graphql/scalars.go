@@ -0,0 +1,149 @@
+package graphql
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/project-exam/pkg/interface/validator"
+)
+
+// addressValidator validates Address arguments
+var addressValidator = validator.NewEthereumValidator()
+
+// hashPattern matches 0x-prefixed 32-byte hex hashes
+var hashPattern = regexp.MustCompile("^0x[0-9a-fA-F]{64}$")
+
+// BigInt is an unsigned integer of up to 256 bits, serialized as a decimal
+// string so clients don't lose precision
+type BigInt struct {
+	value *big.Int
+}
+
+// newBigInt wraps a big integer, returning nil for nil
+func newBigInt(value *big.Int) *BigInt {
+	if value == nil {
+		return nil
+	}
+	return &BigInt{value: value}
+}
+
+// ImplementsGraphQLType maps BigInt to its schema type
+func (BigInt) ImplementsGraphQLType(name string) bool { return name == "BigInt" }
+
+// UnmarshalGraphQL parses a decimal string or integer
+func (b *BigInt) UnmarshalGraphQL(input interface{}) error {
+	value, ok := new(big.Int), false
+	switch input := input.(type) {
+	case string:
+		value, ok = value.SetString(input, 10)
+	case int32:
+		value, ok = value.SetInt64(int64(input)), true
+	}
+	if !ok || value.Sign() < 0 {
+		return fmt.Errorf("invalid BigInt %v", input)
+	}
+	b.value = value
+	return nil
+}
+
+// MarshalJSON serializes the integer as a decimal string
+func (b BigInt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.value.String())
+}
+
+// Bytes is arbitrary bytes, serialized as 0x-prefixed hex
+type Bytes []byte
+
+// ImplementsGraphQLType maps Bytes to its schema type
+func (Bytes) ImplementsGraphQLType(name string) bool { return name == "Bytes" }
+
+// UnmarshalGraphQL parses 0x-prefixed hex
+func (b *Bytes) UnmarshalGraphQL(input interface{}) error {
+	s, ok := input.(string)
+	if !ok || !strings.HasPrefix(s, "0x") {
+		return fmt.Errorf("invalid Bytes %v", input)
+	}
+	decoded, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return fmt.Errorf("invalid Bytes %v", input)
+	}
+	*b = decoded
+	return nil
+}
+
+// MarshalJSON serializes the bytes as 0x-prefixed hex
+func (b Bytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal("0x" + hex.EncodeToString(b))
+}
+
+// Bytes32 is a 32-byte hash, kept as lowercase 0x-prefixed hex
+type Bytes32 string
+
+// ImplementsGraphQLType maps Bytes32 to its schema type
+func (Bytes32) ImplementsGraphQLType(name string) bool { return name == "Bytes32" }
+
+// UnmarshalGraphQL parses a 0x-prefixed hash
+func (b *Bytes32) UnmarshalGraphQL(input interface{}) error {
+	s, ok := input.(string)
+	if !ok || !hashPattern.MatchString(s) {
+		return fmt.Errorf("invalid Bytes32 %v", input)
+	}
+	*b = Bytes32(strings.ToLower(s))
+	return nil
+}
+
+// Address is a 20-byte account address, kept as lowercase 0x-prefixed hex
+type Address string
+
+// ImplementsGraphQLType maps Address to its schema type
+func (Address) ImplementsGraphQLType(name string) bool { return name == "Address" }
+
+// UnmarshalGraphQL parses a 0x-prefixed address
+func (a *Address) UnmarshalGraphQL(input interface{}) error {
+	s, ok := input.(string)
+	if !ok || !addressValidator.IsValidAddress(s) {
+		return fmt.Errorf("invalid Address %v", input)
+	}
+	*a = Address(addressValidator.FormatAddress(s))
+	return nil
+}
+
+// Long is an unsigned 64-bit integer. Values are serialized as numbers;
+// arguments may also be given as decimal or 0x-prefixed hex strings.
+type Long uint64
+
+// ImplementsGraphQLType maps Long to its schema type
+func (Long) ImplementsGraphQLType(name string) bool { return name == "Long" }
+
+// UnmarshalGraphQL parses an integer or a decimal or hex string
+func (l *Long) UnmarshalGraphQL(input interface{}) error {
+	switch input := input.(type) {
+	case int32:
+		if input >= 0 {
+			*l = Long(input)
+			return nil
+		}
+	case int64:
+		if input >= 0 {
+			*l = Long(input)
+			return nil
+		}
+	case float64:
+		if input >= 0 && input <= math.MaxInt64 && input == math.Trunc(input) {
+			*l = Long(input)
+			return nil
+		}
+	case string:
+		if value, err := strconv.ParseUint(input, 0, 64); err == nil {
+			*l = Long(value)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid Long %v", input)
+}
@@ -0,0 +1,93 @@
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/errors"
+	"github.com/graph-gophers/graphql-go/log"
+	"github.com/sirupsen/logrus"
+
+	"github.com/project-exam/pkg/usecase"
+)
+
+// schemaString is the GraphQL schema served by Schema
+//
+//go:embed schema.graphql
+var schemaString string
+
+// maxParallelism bounds the fields of a query resolved at the same time. It
+// matches maxBatchKeys so the items of a list fill a batch.
+const maxParallelism = maxBatchKeys
+
+// Options configures the GraphQL schema
+type Options struct {
+	// MaxDepth limits the nesting of queries
+	MaxDepth int
+	// MaxCost limits the node calls a single query can make, across all of
+	// its fields and list items
+	MaxCost int
+	// MaxFields limits the top-level fields of a query, aliases included
+	MaxFields int
+	// Logger logs panics recovered while resolving fields
+	Logger *logrus.Logger
+}
+
+// Schema executes GraphQL queries over accounts, blocks, transactions and logs
+type Schema struct {
+	schema    *graphqlgo.Schema
+	useCase   usecase.ChainUseCase
+	maxCost   int
+	maxFields int
+}
+
+// NewSchema creates a Schema resolving queries through the chain use case
+func NewSchema(useCase usecase.ChainUseCase, opts Options) (*Schema, error) {
+	schema, err := graphqlgo.ParseSchema(schemaString, &queryResolver{},
+		graphqlgo.UseStringDescriptions(),
+		graphqlgo.MaxDepth(opts.MaxDepth),
+		graphqlgo.MaxParallelism(maxParallelism),
+		graphqlgo.Logger(log.LoggerFunc(func(_ context.Context, value interface{}) {
+			opts.Logger.WithField("panic", fmt.Sprint(value)).Error("Panic while resolving GraphQL query")
+		})),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Schema{
+		schema:    schema,
+		useCase:   useCase,
+		maxCost:   opts.MaxCost,
+		maxFields: opts.MaxFields,
+	}, nil
+}
+
+// Exec executes a query with loaders scoped to it, so lookups made while
+// resolving it are batched and each key is fetched at most once. A query
+// exceeding its budget fails as a whole, with no partial data. It also
+// returns the addresses of the accounts the query looked up.
+func (s *Schema) Exec(ctx context.Context, query, operationName string, variables map[string]interface{}) (*graphqlgo.Response, []string) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	b := &budget{maxCost: int64(s.maxCost), maxFields: int64(s.maxFields), cancel: cancel}
+	l := newLoaders(ctx, s.useCase, b)
+	resp := s.schema.Exec(withLoaders(ctx, l), query, operationName, variables)
+
+	if err := b.err(); err != nil {
+		return &graphqlgo.Response{Errors: []*errors.QueryError{{
+			Message:       err.Error(),
+			ResolverError: err,
+			Extensions:    err.Extensions(),
+		}}}, l.lookedUpAddresses()
+	}
+	return resp, l.lookedUpAddresses()
+}
+
+// String returns the schema in the GraphQL schema language
+func (s *Schema) String() string {
+	return schemaString
+}
@@ -0,0 +1,150 @@
+package grpcapi
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/interface/api/response"
+	"github.com/project-exam/pkg/interface/grpcapi/ethereumpb"
+	"github.com/project-exam/pkg/interface/validator"
+	"github.com/project-exam/pkg/usecase"
+)
+
+// ethereumService implements ethereumpb.EthereumServiceServer on top of the
+// Ethereum and tracker use cases. Errors are domain errors; the interceptors convert them
+// to gRPC statuses.
+type ethereumService struct {
+	ethereumpb.UnimplementedEthereumServiceServer
+
+	useCase   usecase.EthereumUseCase
+	tracker   usecase.TrackerUseCase
+	validator *validator.EthereumValidator
+}
+
+// newEthereumService creates a new ethereumService
+func newEthereumService(useCase usecase.EthereumUseCase, tracker usecase.TrackerUseCase, validator *validator.EthereumValidator) *ethereumService {
+	return &ethereumService{
+		useCase:   useCase,
+		tracker:   tracker,
+		validator: validator,
+	}
+}
+
+// GetAddressInfo returns the balance, nonces and code of an address with the
+// current gas price and block number
+func (s *ethereumService) GetAddressInfo(ctx context.Context, req *ethereumpb.GetAddressInfoRequest) (*ethereumpb.AddressInfo, error) {
+	address := req.GetAddress()
+	if !s.validator.IsValidAddress(address) {
+		return nil, entity.ErrInvalidAddress
+	}
+	address = s.validator.FormatAddress(address)
+
+	info, err := s.useCase.GetAddressInfo(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	// Amounts use the exact decimal formatting of the v2 REST API
+	formatted := response.FormatAddressInfoV2(info)
+	return &ethereumpb.AddressInfo{
+		Address: formatted.Address,
+		Balance: &ethereumpb.Balance{
+			Wei:   formatted.Balance.Wei,
+			Ether: formatted.Balance.Ether,
+		},
+		GasPrice: &ethereumpb.GasPrice{
+			Wei:  formatted.GasPrice.Wei,
+			Gwei: formatted.GasPrice.Gwei,
+		},
+		BlockNumber: info.CurrentBlock,
+		Timestamp:   timestamppb.New(info.Timestamp),
+		IsContract:  formatted.IsContract,
+		AccountType: formatted.AccountType,
+		Nonce: &ethereumpb.Nonce{
+			Latest:  formatted.Nonce.Latest,
+			Pending: formatted.Nonce.Pending,
+		},
+		Code: &ethereumpb.Code{
+			Hash:        formatted.Code.Hash,
+			Size:        uint64(formatted.Code.Size),
+			IsProxy:     formatted.Code.IsProxy,
+			DelegatedTo: formatted.Code.DelegatedTo,
+		},
+	}, nil
+}
+
+// GetCurrentBlock returns the latest block number
+func (s *ethereumService) GetCurrentBlock(ctx context.Context, _ *ethereumpb.GetCurrentBlockRequest) (*ethereumpb.GetCurrentBlockResponse, error) {
+	blockNumber, err := s.useCase.GetCurrentBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &ethereumpb.GetCurrentBlockResponse{BlockNumber: blockNumber}, nil
+}
+
+// SubscribeNewBlocks streams the header of every new block until the client
+// cancels the call. The stream ends with UNAVAILABLE if the node connection
+// is lost, and clients should resubscribe.
+func (s *ethereumService) SubscribeNewBlocks(_ *ethereumpb.SubscribeNewBlocksRequest, stream ethereumpb.EthereumService_SubscribeNewBlocksServer) error {
+	ctx := stream.Context()
+
+	headers, err := s.useCase.SubscribeNewBlocks(ctx)
+	if err != nil {
+		return err
+	}
+
+	for header := range headers {
+		if err := stream.Send(&ethereumpb.BlockHeader{
+			Number:    header.Number,
+			Hash:      header.Hash,
+			Timestamp: timestamppb.New(header.Timestamp),
+		}); err != nil {
+			return err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return entity.NewError(entity.ErrCodeUpstreamUnavailable, "Block subscription ended", nil)
+}
+
+// WatchTransaction streams the lifecycle status of a transaction until it is
+// finalized or replaced, or the client cancels the call
+func (s *ethereumService) WatchTransaction(req *ethereumpb.WatchTransactionRequest, stream ethereumpb.EthereumService_WatchTransactionServer) error {
+	ctx := stream.Context()
+
+	hash := req.GetHash()
+	if !s.validator.IsValidHash(hash) {
+		return entity.NewError(entity.ErrCodeInvalidRequest, "Invalid transaction hash: use a 0x-prefixed 32-byte hex hash", nil)
+	}
+
+	statuses, err := s.tracker.Watch(ctx, hash, clientOf(ctx))
+	if err != nil {
+		return err
+	}
+
+	for status := range statuses {
+		msg := &ethereumpb.TransactionStatus{
+			Hash:          status.Hash,
+			From:          status.From,
+			Nonce:         status.Nonce,
+			State:         string(status.State),
+			BlockNumber:   status.BlockNumber,
+			BlockHash:     status.BlockHash,
+			Confirmations: status.Confirmations,
+			Success:       status.Success,
+			ReplacedBy:    status.ReplacedBy,
+			CheckedBlock:  status.CheckedBlock,
+			UpdatedAt:     timestamppb.New(status.UpdatedAt),
+		}
+		if err := stream.Send(msg); err != nil {
+			return err
+		}
+	}
+
+	// The tracker ends the stream at a final state, or when it stops tracking the transaction
+	return ctx.Err()
+}
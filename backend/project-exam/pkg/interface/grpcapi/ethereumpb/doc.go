@@ -0,0 +1,5 @@
+// Package ethereumpb holds the generated protobuf and gRPC bindings of the
+// Ethereum service defined in ethereum.proto
+package ethereumpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative ethereum.proto
@@ -0,0 +1,266 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.3
+// source: ethereum.proto
+
+package ethereumpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	EthereumService_GetAddressInfo_FullMethodName     = "/ethereum.v1.EthereumService/GetAddressInfo"
+	EthereumService_GetCurrentBlock_FullMethodName    = "/ethereum.v1.EthereumService/GetCurrentBlock"
+	EthereumService_SubscribeNewBlocks_FullMethodName = "/ethereum.v1.EthereumService/SubscribeNewBlocks"
+	EthereumService_WatchTransaction_FullMethodName   = "/ethereum.v1.EthereumService/WatchTransaction"
+)
+
+// EthereumServiceClient is the client API for EthereumService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// EthereumService exposes the Ethereum data API over gRPC. It mirrors the
+// REST API under /api/v2: amounts are exact decimal strings.
+type EthereumServiceClient interface {
+	// GetAddressInfo returns the balance, nonces and code of an address with
+	// the current gas price and block number. Requires the read:address scope.
+	GetAddressInfo(ctx context.Context, in *GetAddressInfoRequest, opts ...grpc.CallOption) (*AddressInfo, error)
+	// GetCurrentBlock returns the latest block number.
+	GetCurrentBlock(ctx context.Context, in *GetCurrentBlockRequest, opts ...grpc.CallOption) (*GetCurrentBlockResponse, error)
+	// SubscribeNewBlocks streams the header of every new block until the
+	// client cancels the call.
+	SubscribeNewBlocks(ctx context.Context, in *SubscribeNewBlocksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BlockHeader], error)
+	// WatchTransaction streams the lifecycle status of a transaction, first its
+	// current status and then every change, tracking it from then on. The
+	// stream ends once the transaction is finalized or replaced. Requires the
+	// read:tx scope.
+	WatchTransaction(ctx context.Context, in *WatchTransactionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TransactionStatus], error)
+}
+
+type ethereumServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEthereumServiceClient(cc grpc.ClientConnInterface) EthereumServiceClient {
+	return &ethereumServiceClient{cc}
+}
+
+func (c *ethereumServiceClient) GetAddressInfo(ctx context.Context, in *GetAddressInfoRequest, opts ...grpc.CallOption) (*AddressInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddressInfo)
+	err := c.cc.Invoke(ctx, EthereumService_GetAddressInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ethereumServiceClient) GetCurrentBlock(ctx context.Context, in *GetCurrentBlockRequest, opts ...grpc.CallOption) (*GetCurrentBlockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCurrentBlockResponse)
+	err := c.cc.Invoke(ctx, EthereumService_GetCurrentBlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ethereumServiceClient) SubscribeNewBlocks(ctx context.Context, in *SubscribeNewBlocksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BlockHeader], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EthereumService_ServiceDesc.Streams[0], EthereumService_SubscribeNewBlocks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeNewBlocksRequest, BlockHeader]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EthereumService_SubscribeNewBlocksClient = grpc.ServerStreamingClient[BlockHeader]
+
+func (c *ethereumServiceClient) WatchTransaction(ctx context.Context, in *WatchTransactionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TransactionStatus], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EthereumService_ServiceDesc.Streams[1], EthereumService_WatchTransaction_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchTransactionRequest, TransactionStatus]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EthereumService_WatchTransactionClient = grpc.ServerStreamingClient[TransactionStatus]
+
+// EthereumServiceServer is the server API for EthereumService service.
+// All implementations must embed UnimplementedEthereumServiceServer
+// for forward compatibility.
+//
+// EthereumService exposes the Ethereum data API over gRPC. It mirrors the
+// REST API under /api/v2: amounts are exact decimal strings.
+type EthereumServiceServer interface {
+	// GetAddressInfo returns the balance, nonces and code of an address with
+	// the current gas price and block number. Requires the read:address scope.
+	GetAddressInfo(context.Context, *GetAddressInfoRequest) (*AddressInfo, error)
+	// GetCurrentBlock returns the latest block number.
+	GetCurrentBlock(context.Context, *GetCurrentBlockRequest) (*GetCurrentBlockResponse, error)
+	// SubscribeNewBlocks streams the header of every new block until the
+	// client cancels the call.
+	SubscribeNewBlocks(*SubscribeNewBlocksRequest, grpc.ServerStreamingServer[BlockHeader]) error
+	// WatchTransaction streams the lifecycle status of a transaction, first its
+	// current status and then every change, tracking it from then on. The
+	// stream ends once the transaction is finalized or replaced. Requires the
+	// read:tx scope.
+	WatchTransaction(*WatchTransactionRequest, grpc.ServerStreamingServer[TransactionStatus]) error
+	mustEmbedUnimplementedEthereumServiceServer()
+}
+
+// UnimplementedEthereumServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedEthereumServiceServer struct{}
+
+func (UnimplementedEthereumServiceServer) GetAddressInfo(context.Context, *GetAddressInfoRequest) (*AddressInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAddressInfo not implemented")
+}
+func (UnimplementedEthereumServiceServer) GetCurrentBlock(context.Context, *GetCurrentBlockRequest) (*GetCurrentBlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCurrentBlock not implemented")
+}
+func (UnimplementedEthereumServiceServer) SubscribeNewBlocks(*SubscribeNewBlocksRequest, grpc.ServerStreamingServer[BlockHeader]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeNewBlocks not implemented")
+}
+func (UnimplementedEthereumServiceServer) WatchTransaction(*WatchTransactionRequest, grpc.ServerStreamingServer[TransactionStatus]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchTransaction not implemented")
+}
+func (UnimplementedEthereumServiceServer) mustEmbedUnimplementedEthereumServiceServer() {}
+func (UnimplementedEthereumServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeEthereumServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EthereumServiceServer will
+// result in compilation errors.
+type UnsafeEthereumServiceServer interface {
+	mustEmbedUnimplementedEthereumServiceServer()
+}
+
+func RegisterEthereumServiceServer(s grpc.ServiceRegistrar, srv EthereumServiceServer) {
+	// If the following call pancis, it indicates UnimplementedEthereumServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&EthereumService_ServiceDesc, srv)
+}
+
+func _EthereumService_GetAddressInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAddressInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EthereumServiceServer).GetAddressInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EthereumService_GetAddressInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EthereumServiceServer).GetAddressInfo(ctx, req.(*GetAddressInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EthereumService_GetCurrentBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCurrentBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EthereumServiceServer).GetCurrentBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EthereumService_GetCurrentBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EthereumServiceServer).GetCurrentBlock(ctx, req.(*GetCurrentBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EthereumService_SubscribeNewBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeNewBlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EthereumServiceServer).SubscribeNewBlocks(m, &grpc.GenericServerStream[SubscribeNewBlocksRequest, BlockHeader]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EthereumService_SubscribeNewBlocksServer = grpc.ServerStreamingServer[BlockHeader]
+
+func _EthereumService_WatchTransaction_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTransactionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EthereumServiceServer).WatchTransaction(m, &grpc.GenericServerStream[WatchTransactionRequest, TransactionStatus]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EthereumService_WatchTransactionServer = grpc.ServerStreamingServer[TransactionStatus]
+
+// EthereumService_ServiceDesc is the grpc.ServiceDesc for EthereumService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EthereumService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ethereum.v1.EthereumService",
+	HandlerType: (*EthereumServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetAddressInfo",
+			Handler:    _EthereumService_GetAddressInfo_Handler,
+		},
+		{
+			MethodName: "GetCurrentBlock",
+			Handler:    _EthereumService_GetCurrentBlock_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeNewBlocks",
+			Handler:       _EthereumService_SubscribeNewBlocks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchTransaction",
+			Handler:       _EthereumService_WatchTransaction_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "ethereum.proto",
+}
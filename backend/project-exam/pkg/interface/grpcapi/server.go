@@ -0,0 +1,360 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/domain/repository"
+	"github.com/project-exam/pkg/interface/api/middleware"
+	"github.com/project-exam/pkg/interface/api/response"
+	"github.com/project-exam/pkg/interface/grpcapi/ethereumpb"
+	"github.com/project-exam/pkg/interface/validator"
+	"github.com/project-exam/pkg/usecase"
+)
+
+// errorDomain identifies this API in the ErrorInfo details of error statuses
+const errorDomain = "ethereum.v1"
+
+// methodScopes lists the scopes each method requires when authentication is
+// enabled, matching the scopes of the equivalent REST routes. Methods not
+// listed only require valid credentials.
+var methodScopes = map[string][]string{
+	ethereumpb.EthereumService_GetAddressInfo_FullMethodName:   {middleware.ScopeReadAddress},
+	ethereumpb.EthereumService_WatchTransaction_FullMethodName: {middleware.ScopeReadTx},
+}
+
+// Options configures the gRPC server
+type Options struct {
+	// Access enforces the IP filter, rate limit and authentication of the REST API
+	Access *middleware.AccessControl
+	// RequestTimeout bounds unary calls; streams are not limited
+	RequestTimeout time.Duration
+	// AuditRepo records authenticated calls; nil disables auditing
+	AuditRepo repository.AuditRepository
+	Logger    *logrus.Logger
+}
+
+// Server serves the Ethereum API over gRPC with server reflection
+type Server struct {
+	server *grpc.Server
+	opts   Options
+}
+
+// NewServer creates a gRPC server for the Ethereum and tracker use cases
+func NewServer(useCase usecase.EthereumUseCase, tracker usecase.TrackerUseCase, validator *validator.EthereumValidator, opts Options) *Server {
+	s := &Server{opts: opts}
+	s.server = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.unaryInterceptor),
+		grpc.ChainStreamInterceptor(s.streamInterceptor),
+	)
+
+	ethereumpb.RegisterEthereumServiceServer(s.server, newEthereumService(useCase, tracker, validator))
+	reflection.Register(s.server)
+
+	return s
+}
+
+// Serve accepts connections on lis until Stop or GracefulStop is called
+func (s *Server) Serve(lis net.Listener) error {
+	return s.server.Serve(lis)
+}
+
+// GracefulStop stops accepting connections and waits for pending calls,
+// cancelling open streams once ctx is done
+func (s *Server) GracefulStop(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.server.Stop()
+	}
+}
+
+// call holds the state of a call shared by the interceptors
+type call struct {
+	requestID string
+	method    string
+	request   *http.Request
+	clientIP  string
+	principal *middleware.Principal
+	startTime time.Time
+}
+
+// unaryInterceptor applies the access policy, timeout, logging and auditing
+// to unary calls
+func (s *Server) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	c := s.newCall(ctx, info.FullMethod)
+	defer func() {
+		if r := recover(); r != nil {
+			err = s.recovered(c, r)
+		}
+		s.finish(c, addressOf(req), err)
+		err = toStatus(err)
+	}()
+
+	if err := s.authorize(c); err != nil {
+		return nil, err
+	}
+	ctx = withClient(ctx, c)
+
+	if s.opts.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.opts.RequestTimeout)
+		defer cancel()
+	}
+
+	return handler(ctx, req)
+}
+
+// streamInterceptor applies the access policy, logging and auditing to
+// streaming calls
+func (s *Server) streamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	c := s.newCall(stream.Context(), info.FullMethod)
+	defer func() {
+		if r := recover(); r != nil {
+			err = s.recovered(c, r)
+		}
+		s.finish(c, "", err)
+		err = toStatus(err)
+	}()
+
+	if err := s.authorize(c); err != nil {
+		return err
+	}
+
+	return handler(srv, &callStream{ServerStream: stream, ctx: withClient(stream.Context(), c)})
+}
+
+// clientKey is the context key of the ClientID of a call
+type clientKey struct{}
+
+// withClient returns ctx carrying the ClientID of an authorized call
+func withClient(ctx context.Context, c *call) context.Context {
+	userID := ""
+	if c.principal != nil {
+		userID = c.principal.UserID
+	}
+	return context.WithValue(ctx, clientKey{}, middleware.ClientID(userID, c.clientIP))
+}
+
+// clientOf returns the ClientID of the call ctx belongs to
+func clientOf(ctx context.Context) string {
+	client, _ := ctx.Value(clientKey{}).(string)
+	return client
+}
+
+// callStream is a server stream whose context carries the call's ClientID
+type callStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the stream's context with the call's ClientID
+func (s *callStream) Context() context.Context {
+	return s.ctx
+}
+
+// newCall starts a call, sending its request ID to the client
+func (s *Server) newCall(ctx context.Context, method string) *call {
+	c := &call{
+		requestID: uuid.New().String(),
+		method:    method,
+		request:   httpRequest(ctx, method),
+		startTime: time.Now(),
+	}
+	c.clientIP = s.opts.Access.ClientIP(c.request)
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs("x-request-id", c.requestID))
+	return c
+}
+
+// authorize applies the access policy to calls of the Ethereum service.
+// Server reflection is open, like the REST API documentation.
+func (s *Server) authorize(c *call) error {
+	if !strings.HasPrefix(c.method, "/"+ethereumpb.EthereumService_ServiceDesc.ServiceName+"/") {
+		return nil
+	}
+
+	principal, err := s.opts.Access.Check(c.request, c.clientIP, methodScopes[c.method]...)
+	c.principal = principal
+	return err
+}
+
+// recovered logs a panic and returns the error reported to the client
+func (s *Server) recovered(c *call, r interface{}) error {
+	s.opts.Logger.WithFields(logrus.Fields{
+		"request_id": c.requestID,
+		"error":      r,
+		"stack":      string(debug.Stack()),
+	}).Error("Panic recovered")
+
+	return entity.NewError(entity.ErrCodeInternal, "Internal server error", nil)
+}
+
+// finish logs a completed call, and records it to the audit log when it was
+// authenticated
+func (s *Server) finish(c *call, address string, err error) {
+	duration := time.Since(c.startTime)
+	code := status.Code(toStatus(err))
+
+	logEntry := s.opts.Logger.WithFields(logrus.Fields{
+		"request_id":  c.requestID,
+		"transport":   "grpc",
+		"method":      c.method,
+		"status":      code.String(),
+		"client_ip":   c.clientIP,
+		"duration_ms": duration.Milliseconds(),
+		"user_agent":  c.request.UserAgent(),
+	})
+	if err != nil {
+		logEntry = logEntry.WithError(err)
+	}
+
+	switch code {
+	case codes.OK, codes.Canceled:
+		logEntry.Info("Request processed")
+	case codes.Internal, codes.Unknown, codes.Unavailable, codes.DeadlineExceeded, codes.DataLoss, codes.Unimplemented:
+		logEntry.Error("Server error")
+	default:
+		logEntry.Warn("Client error")
+	}
+
+	if s.opts.AuditRepo == nil || c.principal == nil {
+		return
+	}
+
+	httpStatus := http.StatusOK
+	if err != nil {
+		httpStatus = response.StatusForCode(entity.ErrorCodeOf(err))
+	}
+
+	event := &entity.AuditEvent{
+		Time:      c.startTime.UTC(),
+		RequestID: c.requestID,
+		UserID:    c.principal.UserID,
+		Method:    "GRPC",
+		Route:     c.method,
+		Path:      c.method,
+		Status:    httpStatus,
+		Latency:   duration,
+		ClientIP:  c.clientIP,
+	}
+	if address != "" {
+		event.Addresses = []string{address}
+	}
+
+	// Don't tie the write to the call context, which is already done
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.opts.AuditRepo.Record(ctx, event); err != nil {
+		s.opts.Logger.WithError(err).WithField("request_id", c.requestID).Error("Failed to record audit event")
+	}
+}
+
+// httpRequest builds an HTTP request carrying the call's metadata as headers
+// and the peer as remote address, so the REST authenticators and client IP
+// resolution apply unchanged
+func httpRequest(ctx context.Context, method string) *http.Request {
+	r := &http.Request{
+		Method: http.MethodPost,
+		URL:    &url.URL{Path: method},
+		Header: make(http.Header),
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for key, values := range md {
+			if strings.HasPrefix(key, ":") {
+				continue // pseudo-headers
+			}
+			for _, value := range values {
+				r.Header.Add(key, value)
+			}
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		r.RemoteAddr = p.Addr.String()
+	}
+
+	return r.WithContext(ctx)
+}
+
+// addressOf returns the address a request refers to, for auditing
+func addressOf(req interface{}) string {
+	if r, ok := req.(interface{ GetAddress() string }); ok {
+		return r.GetAddress()
+	}
+	return ""
+}
+
+// toStatus converts an error to a gRPC status error. Domain errors are
+// mapped by code and carry only their client-safe message, with the code in
+// an ErrorInfo detail; the cause, such as why credentials were rejected, is
+// only logged by finish.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, "Request cancelled")
+	}
+
+	code := entity.ErrorCodeOf(err)
+	message := entity.SafeMessage(err)
+	if code == entity.ErrCodeInternal && errors.Is(err, context.DeadlineExceeded) {
+		code, message = entity.ErrCodeRequestTimeout, "Request timed out"
+	}
+
+	st := status.New(codeFor(code), response.Redact(message))
+	if detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{Reason: string(code), Domain: errorDomain}); detailErr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// codeFor maps an error code to its gRPC status code
+func codeFor(code entity.ErrorCode) codes.Code {
+	switch code {
+	case entity.ErrCodeInvalidAddress, entity.ErrCodeInvalidRequest, entity.ErrCodeUnsupportedMedia:
+		return codes.InvalidArgument
+	case entity.ErrCodeUnauthorized:
+		return codes.Unauthenticated
+	case entity.ErrCodeForbidden:
+		return codes.PermissionDenied
+	case entity.ErrCodeNotFound:
+		return codes.NotFound
+	case entity.ErrCodeRateLimited:
+		return codes.ResourceExhausted
+	case entity.ErrCodeUpstreamError, entity.ErrCodeUpstreamUnavailable:
+		return codes.Unavailable
+	case entity.ErrCodeRequestTimeout, entity.ErrCodeUpstreamTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
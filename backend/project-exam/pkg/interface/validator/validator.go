@@ -1,8 +1,21 @@
 package validator
 
 import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
 	"regexp"
+	"strconv"
 	"strings"
+
+	"github.com/project-exam/pkg/domain/entity"
+)
+
+var (
+	// hexQuantityPattern matches 0x-prefixed hex numbers of up to 32 bytes
+	hexQuantityPattern = regexp.MustCompile("^0x[0-9a-fA-F]{1,64}$")
+	// hashPattern matches 0x-prefixed 32-byte hashes
+	hashPattern = regexp.MustCompile("^0x[0-9a-fA-F]{64}$")
 )
 
 // EthereumValidator provides validation methods for Ethereum-related input
@@ -46,3 +59,73 @@ func (v *EthereumValidator) FormatAddress(address string) string {
 
 	return address
 }
+
+// IsValidHash checks if the provided string is a 0x-prefixed 32-byte hash,
+// such as a block hash or log topic
+func (v *EthereumValidator) IsValidHash(hash string) bool {
+	return hashPattern.MatchString(hash)
+}
+
+// ParseHexBytes parses 0x-prefixed hex data of a whole number of bytes
+func (v *EthereumValidator) ParseHexBytes(data string) ([]byte, bool) {
+	if !strings.HasPrefix(data, "0x") {
+		return nil, false
+	}
+	decoded, err := hex.DecodeString(data[2:])
+	return decoded, err == nil
+}
+
+// ParseSlot parses a storage slot given as a hex or decimal number of up to
+// 32 bytes, returning it as a 0x-prefixed 32-byte hex string
+func (v *EthereumValidator) ParseSlot(slot string) (string, bool) {
+	value, ok := v.ParseQuantity(slot)
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf("0x%064x", value), true
+}
+
+// ParseQuantity parses an unsigned number of up to 32 bytes, such as an
+// amount of wei, given as a decimal or 0x-prefixed hex number
+func (v *EthereumValidator) ParseQuantity(quantity string) (*big.Int, bool) {
+	value, ok := new(big.Int), false
+	if strings.HasPrefix(quantity, "0x") {
+		if hexQuantityPattern.MatchString(quantity) {
+			value, ok = value.SetString(quantity[2:], 16)
+		}
+	} else if quantity != "" && strings.Trim(quantity, "0123456789") == "" {
+		value, ok = value.SetString(quantity, 10)
+	}
+	if !ok || value.BitLen() > 256 {
+		return nil, false
+	}
+	return value, true
+}
+
+// ParseBlock parses a block given as a block hash, a hex or decimal number or
+// one of the tags latest, safe, finalized and earliest. Empty selects the
+// latest block.
+func (v *EthereumValidator) ParseBlock(block string) (entity.BlockSelector, bool) {
+	switch tag := entity.BlockTag(block); tag {
+	case "", entity.BlockTagLatest:
+		return entity.LatestBlock, true
+	case entity.BlockTagSafe, entity.BlockTagFinalized, entity.BlockTagEarliest:
+		return entity.BlockSelector{Tag: tag}, true
+	}
+
+	if v.IsValidHash(block) {
+		return entity.BlockSelector{Hash: strings.ToLower(block)}, true
+	}
+
+	var number uint64
+	var err error
+	if strings.HasPrefix(block, "0x") {
+		number, err = strconv.ParseUint(block[2:], 16, 64)
+	} else {
+		number, err = strconv.ParseUint(block, 10, 64)
+	}
+	if err != nil {
+		return entity.BlockSelector{}, false
+	}
+	return entity.BlockSelector{Number: &number}, true
+}
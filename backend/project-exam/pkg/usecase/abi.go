@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/domain/repository"
+)
+
+// ABIUseCase defines the interface for managing the ABI registry
+type ABIUseCase interface {
+	SaveABI(ctx context.Context, address string, abiJSON []byte) (*entity.ContractABI, error)
+	GetABI(ctx context.Context, address string) (*entity.ContractABI, error)
+	DeleteABI(ctx context.Context, address string) error
+}
+
+// abiUseCase implements the ABIUseCase interface
+type abiUseCase struct {
+	abiRepo repository.ABIRepository
+}
+
+// NewABIUseCase creates a new ABIUseCase
+func NewABIUseCase(abiRepo repository.ABIRepository) ABIUseCase {
+	return &abiUseCase{
+		abiRepo: abiRepo,
+	}
+}
+
+// SaveABI registers the ABI of a contract, replacing any previous one
+func (uc *abiUseCase) SaveABI(ctx context.Context, address string, abiJSON []byte) (*entity.ContractABI, error) {
+	return uc.abiRepo.SaveABI(ctx, address, abiJSON)
+}
+
+// GetABI returns the registered ABI of a contract
+func (uc *abiUseCase) GetABI(ctx context.Context, address string) (*entity.ContractABI, error) {
+	contractABI, err := uc.abiRepo.GetABI(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	if contractABI == nil {
+		return nil, entity.NewError(entity.ErrCodeNotFound, "No ABI registered for this address", nil)
+	}
+	return contractABI, nil
+}
+
+// DeleteABI removes the registered ABI of a contract
+func (uc *abiUseCase) DeleteABI(ctx context.Context, address string) error {
+	return uc.abiRepo.DeleteABI(ctx, address)
+}
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"strings"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/domain/repository"
+)
+
+// ChainUseCase defines the interface for batched reads of chain data.
+// Every method takes a batch of keys and returns results in the same order,
+// so callers can coalesce lookups into a single round trip to the node.
+type ChainUseCase interface {
+	GetCurrentBlock(ctx context.Context) (uint64, error)
+	GetBalances(ctx context.Context, addresses []string) ([]*big.Int, error)
+	GetNonces(ctx context.Context, addresses []string) ([]uint64, error)
+	GetCodes(ctx context.Context, addresses []string) ([][]byte, error)
+	GetBlocksByNumber(ctx context.Context, numbers []uint64) ([]*entity.Block, error)
+	GetBlocksByHash(ctx context.Context, hashes []string) ([]*entity.Block, error)
+	GetTransactions(ctx context.Context, hashes []string) ([]*entity.Transaction, error)
+	GetReceipts(ctx context.Context, hashes []string) ([]*entity.Receipt, error)
+	// GetTokens returns the ERC-20 metadata of tokens, in order
+	GetTokens(ctx context.Context, addresses []string) ([]*entity.Token, error)
+	// GetTokenBalances returns the ERC-20 balance of each owner in the token
+	// at the same index; nil when the token doesn't implement balanceOf
+	GetTokenBalances(ctx context.Context, owners, tokens []string) ([]*big.Int, error)
+}
+
+// ERC-20 function selectors
+var (
+	selectorName      = []byte{0x06, 0xfd, 0xde, 0x03}
+	selectorSymbol    = []byte{0x95, 0xd8, 0x9b, 0x41}
+	selectorDecimals  = []byte{0x31, 0x3c, 0xe5, 0x67}
+	selectorBalanceOf = []byte{0x70, 0xa0, 0x82, 0x31}
+)
+
+// chainUseCase implements the ChainUseCase interface
+type chainUseCase struct {
+	repo repository.EthereumRepository
+}
+
+// NewChainUseCase creates a new ChainUseCase
+func NewChainUseCase(repo repository.EthereumRepository) ChainUseCase {
+	return &chainUseCase{
+		repo: repo,
+	}
+}
+
+// GetCurrentBlock returns the latest block number
+func (uc *chainUseCase) GetCurrentBlock(ctx context.Context) (uint64, error) {
+	return uc.repo.GetCurrentBlock(ctx)
+}
+
+// GetBalances returns the latest balances of addresses
+func (uc *chainUseCase) GetBalances(ctx context.Context, addresses []string) ([]*big.Int, error) {
+	return uc.repo.GetBalances(ctx, addresses)
+}
+
+// GetNonces returns the latest nonces of addresses
+func (uc *chainUseCase) GetNonces(ctx context.Context, addresses []string) ([]uint64, error) {
+	return uc.repo.GetNonces(ctx, addresses)
+}
+
+// GetCodes returns the code at addresses
+func (uc *chainUseCase) GetCodes(ctx context.Context, addresses []string) ([][]byte, error) {
+	return uc.repo.GetCodes(ctx, addresses)
+}
+
+// GetBlocksByNumber returns blocks by number; nil for blocks that don't exist yet
+func (uc *chainUseCase) GetBlocksByNumber(ctx context.Context, numbers []uint64) ([]*entity.Block, error) {
+	return uc.repo.GetBlocksByNumber(ctx, numbers)
+}
+
+// GetBlocksByHash returns blocks by hash; nil for unknown blocks
+func (uc *chainUseCase) GetBlocksByHash(ctx context.Context, hashes []string) ([]*entity.Block, error) {
+	return uc.repo.GetBlocksByHash(ctx, hashes)
+}
+
+// GetTransactions returns transactions by hash; nil for unknown transactions
+func (uc *chainUseCase) GetTransactions(ctx context.Context, hashes []string) ([]*entity.Transaction, error) {
+	return uc.repo.GetTransactions(ctx, hashes)
+}
+
+// GetReceipts returns receipts by transaction hash; nil for transactions that aren't mined
+func (uc *chainUseCase) GetReceipts(ctx context.Context, hashes []string) ([]*entity.Receipt, error) {
+	return uc.repo.GetReceipts(ctx, hashes)
+}
+
+// GetTokens reads name, symbol and decimals of every token in one batch.
+// These functions are optional in ERC-20, so each one that fails or returns
+// malformed data is left empty.
+func (uc *chainUseCase) GetTokens(ctx context.Context, addresses []string) ([]*entity.Token, error) {
+	calls := make([]entity.ContractCall, 0, 3*len(addresses))
+	for _, address := range addresses {
+		calls = append(calls,
+			entity.ContractCall{To: address, Data: selectorName},
+			entity.ContractCall{To: address, Data: selectorSymbol},
+			entity.ContractCall{To: address, Data: selectorDecimals},
+		)
+	}
+
+	results, err := uc.repo.CallContracts(ctx, calls)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]*entity.Token, len(addresses))
+	for i, address := range addresses {
+		name, symbol, decimals := results[3*i], results[3*i+1], results[3*i+2]
+		tokens[i] = &entity.Token{Address: address}
+		if name.Err == nil {
+			tokens[i].Name = decodeString(name.Output)
+		}
+		if symbol.Err == nil {
+			tokens[i].Symbol = decodeString(symbol.Output)
+		}
+		if decimals.Err == nil {
+			if value, ok := decodeUint(decimals.Output); ok && value.IsUint64() && value.Uint64() <= 255 {
+				d := uint8(value.Uint64())
+				tokens[i].Decimals = &d
+			}
+		}
+	}
+	return tokens, nil
+}
+
+// GetTokenBalances calls balanceOf for every owner and token pair in one batch
+func (uc *chainUseCase) GetTokenBalances(ctx context.Context, owners, tokens []string) ([]*big.Int, error) {
+	calls := make([]entity.ContractCall, len(owners))
+	for i, owner := range owners {
+		data := make([]byte, 4+32)
+		copy(data, selectorBalanceOf)
+		// Addresses are validated by callers, so they always decode
+		address, _ := hex.DecodeString(strings.TrimPrefix(owner, "0x"))
+		copy(data[4+32-len(address):], address)
+		calls[i] = entity.ContractCall{To: tokens[i], Data: data}
+	}
+
+	results, err := uc.repo.CallContracts(ctx, calls)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]*big.Int, len(results))
+	for i, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		if value, ok := decodeUint(result.Output); ok {
+			balances[i] = value
+		}
+	}
+	return balances, nil
+}
+
+// decodeUint decodes an ABI-encoded uint256 return value
+func decodeUint(output []byte) (*big.Int, bool) {
+	if len(output) < 32 {
+		return nil, false
+	}
+	return new(big.Int).SetBytes(output[:32]), true
+}
+
+// decodeString decodes an ABI-encoded string return value. Some early
+// tokens return bytes32 instead, padded with zeros, which is also accepted.
+func decodeString(output []byte) string {
+	if len(output) == 32 {
+		return strings.TrimRight(string(output), "\x00")
+	}
+	if len(output) < 64 {
+		return ""
+	}
+
+	offset := new(big.Int).SetBytes(output[:32])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(output)-32) {
+		return ""
+	}
+	start := offset.Uint64()
+	length := binary.BigEndian.Uint64(output[start+24 : start+32])
+	if new(big.Int).SetBytes(output[start:start+24]).Sign() != 0 || length > uint64(len(output))-start-32 {
+		return ""
+	}
+	return string(output[start+32 : start+32+length])
+}
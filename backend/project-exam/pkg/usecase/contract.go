@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/project-exam/pkg/domain/entity"
+)
+
+// Standard storage slots holding the addresses a proxy delegates to
+const (
+	eip1967ImplementationSlot = "0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc"
+	eip1967AdminSlot          = "0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103"
+	eip1967BeaconSlot         = "0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50"
+	eip1822ProxiableSlot      = "0xc5f16f0fcc639fa48a6947836d9850f504798523bf8c9a3a87d5876cf622bcf7"
+)
+
+// Function selectors of proxy standards
+var (
+	selectorImplementation = []byte{0x5c, 0x60, 0xda, 0x1b} // implementation() of beacons
+	selectorProxiableUUID  = []byte{0x52, 0xd1, 0x90, 0x2d} // proxiableUUID() of UUPS implementations
+)
+
+// Bytecode patterns
+var (
+	// eip7702Prefix starts the code of an EOA delegating to a contract,
+	// followed by the 20-byte delegation target
+	eip7702Prefix = []byte{0xef, 0x01, 0x00}
+
+	// EIP-1167 minimal proxies are this prefix, the 20-byte implementation
+	// address, then this suffix
+	eip1167Prefix = []byte{0x36, 0x3d, 0x3d, 0x37, 0x3d, 0x3d, 0x3d, 0x36, 0x3d, 0x73}
+	eip1167Suffix = []byte{0x5a, 0xf4, 0x3d, 0x82, 0x80, 0x3e, 0x90, 0x3d, 0x91, 0x60, 0x2b, 0x57, 0xfd, 0x5b, 0xf3}
+)
+
+// GetContractInfo describes the contract deployed at an address and, for
+// proxies, the implementation, admin and beacon it delegates through
+func (uc *ethereumUseCase) GetContractInfo(ctx context.Context, address string) (*entity.ContractInfo, error) {
+	var code []byte
+	var blockNumber uint64
+	var slots map[string]string
+
+	// Read code and every proxy slot at once, as most contracts are checked for being proxies
+	err := runConcurrently(
+		func() (err error) {
+			code, err = uc.repo.GetCode(ctx, address)
+			return err
+		},
+		func() (err error) {
+			blockNumber, err = uc.repo.GetCurrentBlock(ctx)
+			return err
+		},
+		func() (err error) {
+			slots, err = uc.readAddressSlots(ctx, address,
+				eip1967ImplementationSlot, eip1967AdminSlot, eip1967BeaconSlot, eip1822ProxiableSlot)
+			return err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	codeInfo := describeCode(code)
+	if !codeInfo.IsContract {
+		return nil, entity.NewError(entity.ErrCodeNotFound, "No contract deployed at this address", nil)
+	}
+
+	proxy, err := uc.resolveProxy(ctx, code, slots)
+	if err != nil {
+		return nil, err
+	}
+	codeInfo.IsProxy = proxy != nil
+
+	return &entity.ContractInfo{
+		Address:     address,
+		Code:        codeInfo,
+		Proxy:       proxy,
+		BlockNumber: blockNumber,
+	}, nil
+}
+
+// resolveProxy identifies the proxy standard a contract follows from its
+// code and proxy slots, returning nil if it isn't a recognized proxy
+func (uc *ethereumUseCase) resolveProxy(ctx context.Context, code []byte, slots map[string]string) (*entity.ProxyInfo, error) {
+	if implementation, ok := minimalProxyImplementation(code); ok {
+		return &entity.ProxyInfo{Type: entity.ProxyTypeMinimal, Implementation: implementation}, nil
+	}
+
+	// Beacon proxies ask the beacon for the implementation
+	if beacon := slots[eip1967BeaconSlot]; beacon != "" {
+		output, err := uc.call(ctx, beacon, selectorImplementation)
+		if err != nil {
+			return nil, err
+		}
+		implementation, _ := wordAddress(output)
+		return &entity.ProxyInfo{Type: entity.ProxyTypeBeacon, Implementation: implementation, Beacon: beacon}, nil
+	}
+
+	if implementation := slots[eip1967ImplementationSlot]; implementation != "" {
+		proxy := &entity.ProxyInfo{Type: entity.ProxyTypeEIP1967, Implementation: implementation, Admin: slots[eip1967AdminSlot]}
+		if proxy.Admin != "" {
+			proxy.Type = entity.ProxyTypeTransparent
+			return proxy, nil
+		}
+
+		// UUPS implementations confirm the slot they are stored in
+		output, err := uc.call(ctx, implementation, selectorProxiableUUID)
+		if err != nil {
+			return nil, err
+		}
+		if "0x"+hex.EncodeToString(output) == eip1967ImplementationSlot {
+			proxy.Type = entity.ProxyTypeUUPS
+		}
+		return proxy, nil
+	}
+
+	if implementation := slots[eip1822ProxiableSlot]; implementation != "" {
+		return &entity.ProxyInfo{Type: entity.ProxyTypeEIP1822, Implementation: implementation}, nil
+	}
+
+	return nil, nil
+}
+
+// call performs a read-only call, returning no output if the call reverts
+func (uc *ethereumUseCase) call(ctx context.Context, to string, data []byte) ([]byte, error) {
+	results, err := uc.repo.CallContracts(ctx, []entity.ContractCall{{To: to, Data: data}})
+	if err != nil {
+		return nil, err
+	}
+	if results[0].Err != nil {
+		return nil, nil
+	}
+	return results[0].Output, nil
+}
+
+// hasProxySlots reports whether a contract has an EIP-1967 implementation
+// or beacon address set
+func (uc *ethereumUseCase) hasProxySlots(ctx context.Context, address string) (bool, error) {
+	slots, err := uc.readAddressSlots(ctx, address, eip1967ImplementationSlot, eip1967BeaconSlot)
+	return len(slots) > 0, err
+}
+
+// readAddressSlots reads storage slots holding addresses concurrently,
+// returning the address in each slot that is set
+func (uc *ethereumUseCase) readAddressSlots(ctx context.Context, address string, slots ...string) (map[string]string, error) {
+	var mu sync.Mutex
+	addresses := make(map[string]string, len(slots))
+
+	fns := make([]func() error, len(slots))
+	for i, slot := range slots {
+		fns[i] = func() error {
+			value, err := uc.repo.GetStorageAt(ctx, address, slot)
+			if err != nil {
+				return err
+			}
+			if stored, ok := wordAddress(value); ok {
+				mu.Lock()
+				addresses[slot] = stored
+				mu.Unlock()
+			}
+			return nil
+		}
+	}
+
+	return addresses, runConcurrently(fns...)
+}
+
+// runConcurrently runs fns concurrently and returns the first error
+func runConcurrently(fns ...func() error) error {
+	errs := make([]error, len(fns))
+
+	var wg sync.WaitGroup
+	for i, fn := range fns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = fn()
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// describeCode classifies an address by its code. Proxies that keep their
+// implementation in storage are not detected from code alone.
+func describeCode(code []byte) entity.CodeInfo {
+	info := entity.CodeInfo{
+		Type: entity.AccountTypeEOA,
+		Hash: keccak256Hex(code),
+		Size: len(code),
+	}
+
+	switch {
+	case len(code) == 0:
+	case len(code) == len(eip7702Prefix)+20 && bytes.HasPrefix(code, eip7702Prefix):
+		info.Type = entity.AccountTypeDelegated
+		info.DelegatedTo = hexAddress(code[len(eip7702Prefix):])
+	default:
+		info.Type = entity.AccountTypeContract
+		info.IsContract = true
+		_, info.IsProxy = minimalProxyImplementation(code)
+	}
+	return info
+}
+
+// minimalProxyImplementation returns the implementation of an EIP-1167
+// minimal proxy
+func minimalProxyImplementation(code []byte) (string, bool) {
+	if len(code) != len(eip1167Prefix)+20+len(eip1167Suffix) ||
+		!bytes.HasPrefix(code, eip1167Prefix) || !bytes.HasSuffix(code, eip1167Suffix) {
+		return "", false
+	}
+	return hexAddress(code[len(eip1167Prefix) : len(eip1167Prefix)+20]), true
+}
+
+// wordAddress returns the address right-aligned in a 32-byte word, such as
+// a storage slot or return value, or false if the word is empty or isn't
+// an address
+func wordAddress(word []byte) (string, bool) {
+	if len(word) != 32 || !bytes.Equal(word[:12], make([]byte, 12)) || bytes.Equal(word[12:], make([]byte, 20)) {
+		return "", false
+	}
+	return hexAddress(word[12:]), true
+}
+
+// hexAddress formats a 20-byte address as lowercase 0x-prefixed hex
+func hexAddress(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+// keccak256Hex returns the 0x-prefixed keccak256 hash of data
+func keccak256Hex(data []byte) string {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(data)
+	return fmt.Sprintf("0x%x", hash.Sum(nil))
+}
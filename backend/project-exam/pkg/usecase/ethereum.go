@@ -13,6 +13,12 @@ import (
 // EthereumUseCase defines the interface for Ethereum application business rules
 type EthereumUseCase interface {
 	GetAddressInfo(ctx context.Context, address string) (*entity.AddressInfo, error)
+	GetAddressRevision(ctx context.Context, address string) (*entity.AddressRevision, error)
+	GetContractInfo(ctx context.Context, address string) (*entity.ContractInfo, error)
+	GetStorage(ctx context.Context, address, slot string, block entity.BlockSelector) (*entity.StorageSlot, error)
+	GetProof(ctx context.Context, address string, slots []string, block entity.BlockSelector) (*entity.AccountProof, error)
+	GetCurrentBlock(ctx context.Context) (uint64, error)
+	SubscribeNewBlocks(ctx context.Context) (<-chan *entity.BlockHeader, error)
 }
 
 // ethereumUseCase implements the EthereumUseCase interface
@@ -27,13 +33,46 @@ func NewEthereumUseCase(repo repository.EthereumRepository) EthereumUseCase {
 	}
 }
 
+// GetCurrentBlock returns the latest block number
+func (uc *ethereumUseCase) GetCurrentBlock(ctx context.Context) (uint64, error) {
+	return uc.repo.GetCurrentBlock(ctx)
+}
+
+// SubscribeNewBlocks streams the header of every new block until ctx is cancelled
+func (uc *ethereumUseCase) SubscribeNewBlocks(ctx context.Context) (<-chan *entity.BlockHeader, error) {
+	return uc.repo.SubscribeNewHeads(ctx)
+}
+
+// GetAddressRevision reads the head block and the address's pending nonce
+func (uc *ethereumUseCase) GetAddressRevision(ctx context.Context, address string) (*entity.AddressRevision, error) {
+	var revision entity.AddressRevision
+	err := runConcurrently(
+		func() (err error) {
+			revision.Block, err = uc.repo.GetCurrentBlock(ctx)
+			return err
+		},
+		func() (err error) {
+			revision.PendingNonce, err = uc.repo.GetPendingNonce(ctx, address)
+			return err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
 // GetAddressInfo retrieves Ethereum data for a specific address
 func (uc *ethereumUseCase) GetAddressInfo(ctx context.Context, address string) (*entity.AddressInfo, error) {
-	// Create channels for concurrent operations
-	gasPriceCh := make(chan *big.Int)
-	blockNumberCh := make(chan uint64)
-	balanceCh := make(chan *big.Int)
-	errCh := make(chan error, 3)
+	// Create channels for concurrent operations, buffered so goroutines
+	// still running when another fails can exit
+	gasPriceCh := make(chan *big.Int, 1)
+	blockNumberCh := make(chan uint64, 1)
+	balanceCh := make(chan *big.Int, 1)
+	nonceCh := make(chan uint64, 1)
+	pendingNonceCh := make(chan uint64, 1)
+	codeCh := make(chan []byte, 1)
+	errCh := make(chan error, 6)
 
 	// Get gas price concurrently
 	go func() {
@@ -65,15 +104,45 @@ func (uc *ethereumUseCase) GetAddressInfo(ctx context.Context, address string) (
 		balanceCh <- balance
 	}()
 
+	// Get latest and pending nonce concurrently
+	go func() {
+		nonce, err := uc.repo.GetNonce(ctx, address)
+		if err != nil {
+			errCh <- fmt.Errorf("failed to get nonce: %w", err)
+			return
+		}
+		nonceCh <- nonce
+	}()
+	go func() {
+		nonce, err := uc.repo.GetPendingNonce(ctx, address)
+		if err != nil {
+			errCh <- fmt.Errorf("failed to get pending nonce: %w", err)
+			return
+		}
+		pendingNonceCh <- nonce
+	}()
+
+	// Get code concurrently
+	go func() {
+		code, err := uc.repo.GetCode(ctx, address)
+		if err != nil {
+			errCh <- fmt.Errorf("failed to get code: %w", err)
+			return
+		}
+		codeCh <- code
+	}()
+
 	// Wait for results or errors
 	var gasPrice *big.Int
 	var blockNumber uint64
 	var balance *big.Int
+	var nonce entity.Nonce
+	var code []byte
 
-	for i := 0; i < 3; i++ {
+	for i := 0; i < 6; i++ {
 		select {
 		case <-ctx.Done():
-			return nil, fmt.Errorf("context cancelled: %w", ctx.Err())
+			return nil, entity.NewError(entity.ErrCodeUpstreamTimeout, "Request cancelled before completion", ctx.Err())
 		case err := <-errCh:
 			return nil, err
 		case gasPrice = <-gasPriceCh:
@@ -82,7 +151,23 @@ func (uc *ethereumUseCase) GetAddressInfo(ctx context.Context, address string) (
 			continue
 		case balance = <-balanceCh:
 			continue
+		case nonce.Latest = <-nonceCh:
+			continue
+		case nonce.Pending = <-pendingNonceCh:
+			continue
+		case code = <-codeCh:
+			continue
+		}
+	}
+
+	// Contracts that aren't recognizable proxies from their code need their proxy slots read
+	codeInfo := describeCode(code)
+	if codeInfo.IsContract && !codeInfo.IsProxy {
+		isProxy, err := uc.hasProxySlots(ctx, address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read proxy slots: %w", err)
 		}
+		codeInfo.IsProxy = isProxy
 	}
 
 	// Convert Wei to Gwei for gas price (1 Gwei = 10^9 Wei)
@@ -113,6 +198,8 @@ func (uc *ethereumUseCase) GetAddressInfo(ctx context.Context, address string) (
 			Wei:   balance,
 			Ether: etherFloat,
 		},
+		Nonce:     nonce,
+		Code:      codeInfo,
 		Timestamp: time.Now(),
 	}, nil
 }
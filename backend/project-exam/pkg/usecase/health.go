@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/domain/repository"
+)
+
+// HealthUseCase defines the interface for service health checks
+type HealthUseCase interface {
+	// CheckReadiness checks every upstream dependency required to serve requests
+	CheckReadiness(ctx context.Context) *entity.HealthReport
+}
+
+// HealthOptions configures the readiness checks
+type HealthOptions struct {
+	ExpectedChainID uint64        // 0 accepts any chain
+	MaxBlockAge     time.Duration // maximum age of the head block before the node is considered stale
+}
+
+// healthUseCase implements the HealthUseCase interface
+type healthUseCase struct {
+	repo repository.EthereumRepository
+	opts HealthOptions
+}
+
+// NewHealthUseCase creates a new HealthUseCase
+func NewHealthUseCase(repo repository.EthereumRepository, opts HealthOptions) HealthUseCase {
+	return &healthUseCase{
+		repo: repo,
+		opts: opts,
+	}
+}
+
+// CheckReadiness runs all dependency checks concurrently; the report is up
+// only if every check is up
+func (uc *healthUseCase) CheckReadiness(ctx context.Context) *entity.HealthReport {
+	checks := []struct {
+		name string
+		fn   func(ctx context.Context) (map[string]interface{}, error)
+	}{
+		{"upstream", uc.checkChainID},
+		{"sync", uc.checkSync},
+		{"headBlock", uc.checkHeadBlock},
+	}
+
+	report := &entity.HealthReport{
+		Status:    entity.HealthStatusUp,
+		Checks:    make([]entity.HealthCheck, len(checks)),
+		Timestamp: time.Now(),
+	}
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, name string, fn func(ctx context.Context) (map[string]interface{}, error)) {
+			defer wg.Done()
+			report.Checks[i] = runCheck(ctx, name, fn)
+		}(i, check.name, check.fn)
+	}
+	wg.Wait()
+
+	for _, check := range report.Checks {
+		if check.Status != entity.HealthStatusUp {
+			report.Status = entity.HealthStatusDown
+		}
+	}
+
+	return report
+}
+
+// runCheck times a single check and converts its outcome into a HealthCheck
+func runCheck(ctx context.Context, name string, fn func(ctx context.Context) (map[string]interface{}, error)) entity.HealthCheck {
+	start := time.Now()
+	details, err := fn(ctx)
+
+	check := entity.HealthCheck{
+		Name:    name,
+		Status:  entity.HealthStatusUp,
+		Latency: time.Since(start),
+		Details: details,
+	}
+	if err != nil {
+		check.Status = entity.HealthStatusDown
+		check.Error = err.Error()
+	}
+	return check
+}
+
+// checkChainID verifies the node is reachable and on the expected chain
+func (uc *healthUseCase) checkChainID(ctx context.Context) (map[string]interface{}, error) {
+	chainID, err := uc.repo.GetChainID(ctx)
+	if err != nil {
+		return nil, healthError(err)
+	}
+
+	details := map[string]interface{}{"chainId": chainID.Uint64()}
+	if uc.opts.ExpectedChainID != 0 && chainID.Uint64() != uc.opts.ExpectedChainID {
+		return details, fmt.Errorf("expected chain ID %d", uc.opts.ExpectedChainID)
+	}
+	return details, nil
+}
+
+// checkSync verifies the node is not catching up with the network
+func (uc *healthUseCase) checkSync(ctx context.Context) (map[string]interface{}, error) {
+	progress, err := uc.repo.GetSyncProgress(ctx)
+	if err != nil {
+		return nil, healthError(err)
+	}
+	if progress == nil {
+		return map[string]interface{}{"syncing": false}, nil
+	}
+
+	return map[string]interface{}{
+		"syncing":      true,
+		"currentBlock": progress.CurrentBlock,
+		"highestBlock": progress.HighestBlock,
+	}, fmt.Errorf("node is syncing (%d/%d)", progress.CurrentBlock, progress.HighestBlock)
+}
+
+// checkHeadBlock verifies the latest block is recent enough
+func (uc *healthUseCase) checkHeadBlock(ctx context.Context) (map[string]interface{}, error) {
+	header, err := uc.repo.GetLatestHeader(ctx)
+	if err != nil {
+		return nil, healthError(err)
+	}
+
+	age := time.Since(header.Timestamp)
+	details := map[string]interface{}{
+		"number":     header.Number,
+		"timestamp":  header.Timestamp.UTC().Format(time.RFC3339),
+		"ageSeconds": int64(age.Seconds()),
+	}
+	if uc.opts.MaxBlockAge > 0 && age > uc.opts.MaxBlockAge {
+		return details, fmt.Errorf("head block is older than %s", uc.opts.MaxBlockAge)
+	}
+	return details, nil
+}
+
+// healthError reduces upstream errors to their client-safe message so probe
+// responses never expose RPC URLs
+func healthError(err error) error {
+	return errors.New(entity.SafeMessage(err))
+}
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/domain/repository"
+)
+
+// LogsUseCase defines the interface for querying event logs
+type LogsUseCase interface {
+	// GetLogs returns the logs matching a filter, decoded with the events of
+	// eventABI when it is set
+	GetLogs(ctx context.Context, filter entity.LogFilter, eventABI []byte) (*entity.LogsResult, error)
+}
+
+// LogsOptions configures the logs use case
+type LogsOptions struct {
+	ChunkSize     uint64 // blocks queried per node call, halved while the node rejects a chunk
+	MaxBlockRange uint64 // widest block range a query may span
+	MaxResults    int    // most logs a query may return
+}
+
+// logsUseCase implements the LogsUseCase interface
+type logsUseCase struct {
+	repo    repository.EthereumRepository
+	abiRepo repository.ABIRepository
+	opts    LogsOptions
+}
+
+// NewLogsUseCase creates a new LogsUseCase
+func NewLogsUseCase(repo repository.EthereumRepository, abiRepo repository.ABIRepository, opts LogsOptions) LogsUseCase {
+	return &logsUseCase{
+		repo:    repo,
+		abiRepo: abiRepo,
+		opts:    opts,
+	}
+}
+
+// GetLogs returns the logs matching a filter. Block ranges are queried in
+// chunks, as nodes limit the range or results of a single eth_getLogs call.
+func (uc *logsUseCase) GetLogs(ctx context.Context, filter entity.LogFilter, eventABI []byte) (*entity.LogsResult, error) {
+	// Without a topic filter, only select the logs of the ABI's events
+	if eventABI != nil {
+		topics, err := uc.abiRepo.EventTopics(eventABI)
+		if err != nil {
+			return nil, err
+		}
+		if len(topics) == 0 {
+			return nil, entity.NewError(entity.ErrCodeInvalidRequest, "The ABI has no events to decode logs with", nil)
+		}
+		if len(filter.Topics) == 0 {
+			filter.Topics = [][]string{topics}
+		} else if len(filter.Topics[0]) == 0 {
+			filter.Topics[0] = topics
+		}
+	}
+
+	var result *entity.LogsResult
+	var err error
+	if filter.BlockHash != "" {
+		result, err = uc.getBlockLogs(ctx, filter)
+	} else {
+		result, err = uc.getRangeLogs(ctx, filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if eventABI != nil {
+		result.Events, err = uc.abiRepo.DecodeLogs(eventABI, result.Logs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// getBlockLogs returns the logs of the block selected by hash
+func (uc *logsUseCase) getBlockLogs(ctx context.Context, filter entity.LogFilter) (*entity.LogsResult, error) {
+	header, err := uc.repo.GetBlockHeader(ctx, entity.BlockSelector{Hash: filter.BlockHash})
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := uc.repo.FilterLogs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(logs) > uc.opts.MaxResults {
+		return nil, uc.tooManyResults()
+	}
+
+	return &entity.LogsResult{
+		Logs:      logs,
+		FromBlock: header.Number,
+		ToBlock:   header.Number,
+	}, nil
+}
+
+// getRangeLogs returns the logs of a block range, querying it in chunks. A
+// chunk the node rejects is split in half until it is a single block, as
+// providers reject ranges that are too wide or return too many results.
+func (uc *logsUseCase) getRangeLogs(ctx context.Context, filter entity.LogFilter) (*entity.LogsResult, error) {
+	from, to, err := uc.resolveRange(ctx, filter.FromBlock, filter.ToBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := []entity.Log{}
+	chunkSize := uc.opts.ChunkSize
+	for start := from; start <= to; {
+		// Computed from the distance to the end, as start+chunkSize may overflow
+		end := start + min(chunkSize-1, to-start)
+
+		chunk := filter
+		chunk.FromBlock = entity.BlockSelector{Number: &start}
+		chunk.ToBlock = entity.BlockSelector{Number: &end}
+		chunkLogs, err := uc.repo.FilterLogs(ctx, chunk)
+		if err != nil {
+			if entity.ErrorCodeOf(err) == entity.ErrCodeUpstreamError && end > start {
+				chunkSize = (end - start + 1) / 2
+				continue
+			}
+			return nil, fmt.Errorf("failed to get logs of blocks %d to %d: %w", start, end, err)
+		}
+
+		logs = append(logs, chunkLogs...)
+		if len(logs) > uc.opts.MaxResults {
+			return nil, uc.tooManyResults()
+		}
+
+		if end == to {
+			break
+		}
+		start = end + 1
+	}
+
+	return &entity.LogsResult{
+		Logs:      logs,
+		FromBlock: from,
+		ToBlock:   to,
+	}, nil
+}
+
+// resolveRange resolves the ends of a block range to block numbers. Unset
+// ends select the latest block, like eth_getLogs. Block numbers past the
+// latest block are clamped to it for toBlock and rejected for fromBlock.
+func (uc *logsUseCase) resolveRange(ctx context.Context, fromBlock, toBlock entity.BlockSelector) (uint64, uint64, error) {
+	var from, to uint64
+	fns := []func() error{
+		func() (err error) {
+			from, err = uc.blockNumber(ctx, fromBlock)
+			return err
+		},
+		func() (err error) {
+			to, err = uc.blockNumber(ctx, toBlock)
+			return err
+		},
+	}
+	var head uint64
+	explicit := fromBlock.Number != nil || toBlock.Number != nil
+	if explicit {
+		fns = append(fns, func() (err error) {
+			head, err = uc.repo.GetCurrentBlock(ctx)
+			return err
+		})
+	}
+	if err := runConcurrently(fns...); err != nil {
+		return 0, 0, err
+	}
+
+	if fromBlock.Number != nil && from > head {
+		return 0, 0, entity.NewError(entity.ErrCodeInvalidRequest,
+			fmt.Sprintf("fromBlock must not be after the latest block %d", head), nil)
+	}
+	if toBlock.Number != nil {
+		to = min(to, head)
+	}
+
+	if from > to {
+		return 0, 0, entity.NewError(entity.ErrCodeInvalidRequest, "fromBlock must not be after toBlock", nil)
+	}
+	if to-from+1 > uc.opts.MaxBlockRange {
+		return 0, 0, entity.NewError(entity.ErrCodeInvalidRequest,
+			fmt.Sprintf("Block range of %d blocks exceeds the limit of %d", to-from+1, uc.opts.MaxBlockRange), nil)
+	}
+	return from, to, nil
+}
+
+// blockNumber returns the number of the selected block
+func (uc *logsUseCase) blockNumber(ctx context.Context, block entity.BlockSelector) (uint64, error) {
+	if block.Number != nil {
+		return *block.Number, nil
+	}
+	if block.Hash == "" && block.Tag == "" {
+		block = entity.LatestBlock
+	}
+
+	header, err := uc.repo.GetBlockHeader(ctx, block)
+	if err != nil {
+		return 0, err
+	}
+	return header.Number, nil
+}
+
+// tooManyResults is the error of a query matching more than MaxResults logs
+func (uc *logsUseCase) tooManyResults() error {
+	return entity.NewError(entity.ErrCodeInvalidRequest,
+		fmt.Sprintf("More than %d logs match; narrow the block range or filter", uc.opts.MaxResults), nil)
+}
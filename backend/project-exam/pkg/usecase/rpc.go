@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/domain/repository"
+)
+
+// RPCUseCase defines the interface for proxying JSON-RPC calls to the
+// Ethereum network
+type RPCUseCase interface {
+	// Forward forwards a batch of calls, answering each from the cache when
+	// possible. Results are returned in the order of calls.
+	Forward(ctx context.Context, calls []entity.RPCCall) []entity.RPCResult
+}
+
+// RPCOptions configures the JSON-RPC proxy
+type RPCOptions struct {
+	AllowedMethods []string      // methods forwarded to the node; others are rejected
+	CacheTTL       time.Duration // how long results at the latest block are cached, 0 disables caching
+}
+
+// Caching of results that don't change once they exist, such as blocks and
+// mined transactions looked up by hash
+const (
+	immutableCacheTTL = 5 * time.Minute
+	maxCacheEntries   = 10000
+)
+
+// immutableMethods return the same non-null result for the same parameters,
+// as long as the data they return is in a block
+var immutableMethods = map[string]bool{
+	"eth_chainId":                           true,
+	"net_version":                           true,
+	"eth_getBlockByHash":                    true,
+	"eth_getBlockTransactionCountByHash":    true,
+	"eth_getTransactionByBlockHashAndIndex": true,
+	"eth_getTransactionByHash":              true,
+	"eth_getTransactionReceipt":             true,
+}
+
+// maxConcurrentCalls bounds the calls of a batch forwarded at the same time
+const maxConcurrentCalls = 8
+
+// rpcUseCase implements the RPCUseCase interface
+type rpcUseCase struct {
+	repo    repository.RPCRepository
+	allowed map[string]bool
+	opts    RPCOptions
+	cache   *resultCache
+}
+
+// NewRPCUseCase creates a new RPCUseCase
+func NewRPCUseCase(repo repository.RPCRepository, opts RPCOptions) RPCUseCase {
+	allowed := make(map[string]bool, len(opts.AllowedMethods))
+	for _, method := range opts.AllowedMethods {
+		allowed[method] = true
+	}
+
+	return &rpcUseCase{
+		repo:    repo,
+		allowed: allowed,
+		opts:    opts,
+		cache:   newResultCache(maxCacheEntries),
+	}
+}
+
+// Forward forwards the calls of a batch concurrently
+func (uc *rpcUseCase) Forward(ctx context.Context, calls []entity.RPCCall) []entity.RPCResult {
+	results := make([]entity.RPCResult, len(calls))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentCalls)
+	for i, call := range calls {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, call entity.RPCCall) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = uc.forward(ctx, call)
+		}(i, call)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// forward forwards a single call, checking the allowlist and cache
+func (uc *rpcUseCase) forward(ctx context.Context, call entity.RPCCall) entity.RPCResult {
+	if !uc.allowed[call.Method] {
+		return entity.RPCResult{Error: entity.NewRPCError(entity.RPCCodeMethodNotFound, "the method %s does not exist/is not available", call.Method)}
+	}
+
+	key, ok := cacheKey(call)
+	cacheable := ok && uc.opts.CacheTTL > 0
+	if cacheable {
+		if result, ok := uc.cache.get(key); ok {
+			return entity.RPCResult{Result: result}
+		}
+	}
+
+	result, err := uc.repo.Call(ctx, call.Method, call.Params)
+	if err != nil {
+		return entity.RPCResult{Error: err}
+	}
+
+	if cacheable {
+		if ttl := uc.cacheTTL(call.Method, result); ttl > 0 {
+			uc.cache.set(key, result, ttl)
+		}
+	}
+
+	return entity.RPCResult{Result: result}
+}
+
+// cacheTTL returns how long the result of a call may be cached
+func (uc *rpcUseCase) cacheTTL(method string, result json.RawMessage) time.Duration {
+	if immutableMethods[method] && isFinalResult(method, result) {
+		return immutableCacheTTL
+	}
+	return uc.opts.CacheTTL
+}
+
+// isFinalResult reports whether the result of an immutable method is final:
+// not null, and for transactions, included in a block
+func isFinalResult(method string, result json.RawMessage) bool {
+	if len(result) == 0 || bytes.Equal(result, []byte("null")) {
+		return false
+	}
+	if method != "eth_getTransactionByHash" {
+		return true
+	}
+
+	var tx struct {
+		BlockHash *string `json:"blockHash"`
+	}
+	return json.Unmarshal(result, &tx) == nil && tx.BlockHash != nil
+}
+
+// cacheKey returns the cache key of a call: its method and compacted
+// parameters. Calls with invalid parameters are not cached.
+func cacheKey(call entity.RPCCall) (string, bool) {
+	var key bytes.Buffer
+	key.WriteString(call.Method)
+	for _, param := range call.Params {
+		key.WriteByte(0)
+		if err := json.Compact(&key, param); err != nil {
+			return "", false
+		}
+	}
+	return key.String(), true
+}
+
+// resultCache is a bounded, concurrency-safe cache of results with
+// per-entry expiry
+type resultCache struct {
+	mu         sync.Mutex
+	entries    map[string]cacheEntry
+	maxEntries int
+}
+
+// cacheEntry is a cached result and its expiry time
+type cacheEntry struct {
+	result  json.RawMessage
+	expires time.Time
+}
+
+// newResultCache creates a cache holding at most maxEntries results
+func newResultCache(maxEntries int) *resultCache {
+	return &resultCache{
+		entries:    make(map[string]cacheEntry),
+		maxEntries: maxEntries,
+	}
+}
+
+// get returns the unexpired result cached under key
+func (c *resultCache) get(key string) (json.RawMessage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.result, true
+}
+
+// set caches a result under key for ttl. When the cache is full, expired
+// entries are evicted; if it is still full, the result isn't cached.
+func (c *resultCache) set(key string, result json.RawMessage, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= c.maxEntries {
+		now := time.Now()
+		for k, entry := range c.entries {
+			if now.After(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			return
+		}
+	}
+
+	c.entries[key] = cacheEntry{result: result, expires: time.Now().Add(ttl)}
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/project-exam/pkg/domain/entity"
+)
+
+// maxProofSlots limits the storage slots proven in one request, as nodes
+// build a trie proof for each
+const maxProofSlots = 64
+
+// GetStorage returns the value of a storage slot of an address at the selected block
+func (uc *ethereumUseCase) GetStorage(ctx context.Context, address, slot string, block entity.BlockSelector) (*entity.StorageSlot, error) {
+	return uc.repo.GetStorageAtBlock(ctx, address, slot, block)
+}
+
+// GetProof returns the proof of an account and storage slots at the selected
+// block, verified against the block's state root
+func (uc *ethereumUseCase) GetProof(ctx context.Context, address string, slots []string, block entity.BlockSelector) (*entity.AccountProof, error) {
+	if len(slots) > maxProofSlots {
+		return nil, entity.NewError(entity.ErrCodeInvalidRequest, fmt.Sprintf("At most %d slots can be proven at once", maxProofSlots), nil)
+	}
+	return uc.repo.GetProof(ctx, address, slots, block)
+}
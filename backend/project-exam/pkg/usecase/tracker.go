@@ -0,0 +1,570 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/domain/repository"
+)
+
+// TrackerUseCase defines the interface for tracking transactions through
+// their lifecycle: pending, included, confirmed and finalized, or dropped
+// and replaced
+type TrackerUseCase interface {
+	// GetStatus returns the status of a transaction, tracking it from then
+	// on. client identifies who looked it up, for TrackerOptions.MaxPerClient.
+	GetStatus(ctx context.Context, hash, client string) (*entity.TransactionStatus, error)
+
+	// TrackSent starts tracking a transaction broadcast through the API
+	TrackSent(tx *entity.SignedTransaction) error
+
+	// Watch streams the status of a transaction, starting with its current
+	// status and then on every change. The channel is closed once the state
+	// is final, the transaction stops being tracked or ctx is cancelled.
+	Watch(ctx context.Context, hash, client string) (<-chan *entity.TransactionStatus, error)
+
+	// Run follows new head blocks and updates the tracked transactions at
+	// each of them until ctx is cancelled. Errors are reported to onError.
+	Run(ctx context.Context, onError func(error))
+}
+
+// TrackerOptions configures the tracker use case
+type TrackerOptions struct {
+	Confirmations uint64        // blocks, including its own, after which an included transaction is confirmed
+	Retention     time.Duration // how long transactions are kept once finalized, replaced or dropped
+	MaxTracked    int           // most transactions tracked at once
+	MaxPerClient  int           // most transactions tracked at once that a client started tracking by looking them up
+}
+
+const (
+	// maxReplacementScan is the most blocks searched for the transaction
+	// that used the nonce of a replaced one
+	maxReplacementScan = 16
+
+	// resubscribeDelay is how long Run waits before following new heads
+	// again once the subscription ended
+	resubscribeDelay = 5 * time.Second
+)
+
+// trackedTx is a tracked transaction with the streams watching it
+type trackedTx struct {
+	status       entity.TransactionStatus
+	pendingBlock uint64 // head block it was last seen pending at
+	client       string // who started tracking it by looking it up; empty when broadcast through the API
+	watchers     []chan *entity.TransactionStatus
+}
+
+// trackerUseCase implements the TrackerUseCase interface
+type trackerUseCase struct {
+	repo repository.EthereumRepository
+	opts TrackerOptions
+
+	mu        sync.Mutex
+	txs       map[string]*trackedTx // by lowercase hash
+	perClient map[string]int        // transactions tracked by the client that looked them up
+	head      uint64                // latest head block seen
+}
+
+// NewTrackerUseCase creates a new TrackerUseCase. Tracked transactions are
+// only updated while Run is running.
+func NewTrackerUseCase(repo repository.EthereumRepository, opts TrackerOptions) TrackerUseCase {
+	return &trackerUseCase{
+		repo:      repo,
+		opts:      opts,
+		txs:       make(map[string]*trackedTx),
+		perClient: make(map[string]int),
+	}
+}
+
+// GetStatus returns the status of a transaction. Transactions that aren't
+// tracked yet are looked up by hash and checked right away.
+func (uc *trackerUseCase) GetStatus(ctx context.Context, hash, client string) (*entity.TransactionStatus, error) {
+	key := strings.ToLower(hash)
+	if status, ok := uc.status(key); ok {
+		return status, nil
+	}
+
+	txs, err := uc.repo.GetTransactions(ctx, []string{hash})
+	if err != nil {
+		return nil, err
+	}
+	tx := txs[0]
+	if tx == nil {
+		return nil, entity.NewError(entity.ErrCodeNotFound, "Transaction not found", nil)
+	}
+
+	uc.mu.Lock()
+	err = uc.add(key, &trackedTx{
+		status: entity.TransactionStatus{
+			Hash:      tx.Hash,
+			From:      tx.From,
+			Nonce:     tx.Nonce,
+			State:     entity.TxStatePending,
+			UpdatedAt: time.Now(),
+		},
+		pendingBlock: uc.head,
+		client:       client,
+	})
+	uc.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := uc.repo.GetLatestHeader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.refresh(ctx, []string{key}, head.Number, uc.finalizedBlock(ctx)); err != nil {
+		return nil, err
+	}
+
+	status, ok := uc.status(key)
+	if !ok {
+		return nil, entity.NewError(entity.ErrCodeNotFound, "Transaction not found", nil)
+	}
+	return status, nil
+}
+
+// TrackSent starts tracking a transaction broadcast through the API as pending
+func (uc *trackerUseCase) TrackSent(tx *entity.SignedTransaction) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	return uc.add(strings.ToLower(tx.Hash), &trackedTx{
+		status: entity.TransactionStatus{
+			Hash:         tx.Hash,
+			From:         tx.From,
+			Nonce:        tx.Nonce,
+			State:        entity.TxStatePending,
+			CheckedBlock: uc.head,
+			UpdatedAt:    time.Now(),
+		},
+		pendingBlock: uc.head,
+	})
+}
+
+// Watch streams the status of a transaction on every change. Slow readers
+// only miss intermediate statuses; the latest one is always delivered.
+func (uc *trackerUseCase) Watch(ctx context.Context, hash, client string) (<-chan *entity.TransactionStatus, error) {
+	status, err := uc.GetStatus(ctx, hash, client)
+	if err != nil {
+		return nil, err
+	}
+
+	key := strings.ToLower(hash)
+	ch := make(chan *entity.TransactionStatus, 1)
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	tracked, ok := uc.txs[key]
+	if !ok {
+		// Evicted since it was looked up
+		ch <- status
+		close(ch)
+		return ch, nil
+	}
+
+	current := tracked.status
+	ch <- &current
+	if current.State.Final() {
+		close(ch)
+		return ch, nil
+	}
+
+	tracked.watchers = append(tracked.watchers, ch)
+	go func() {
+		<-ctx.Done()
+		uc.unwatch(key, ch)
+	}()
+	return ch, nil
+}
+
+// Run follows new head blocks and updates the tracked transactions at each
+// of them. The subscription is renewed when the node connection is lost.
+func (uc *trackerUseCase) Run(ctx context.Context, onError func(error)) {
+	for {
+		heads, err := uc.repo.SubscribeNewHeads(ctx)
+		if err != nil {
+			onError(err)
+		} else {
+			for head := range heads {
+				if err := uc.update(ctx, head.Number); err != nil {
+					onError(err)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resubscribeDelay):
+		}
+	}
+}
+
+// update evicts expired transactions and checks the others at a new head block
+func (uc *trackerUseCase) update(ctx context.Context, head uint64) error {
+	uc.mu.Lock()
+	uc.head = head
+	var keys []string
+	for key, tracked := range uc.txs {
+		switch {
+		case uc.expired(tracked):
+			uc.remove(key)
+		case !tracked.status.State.Final():
+			keys = append(keys, key)
+		}
+	}
+	uc.mu.Unlock()
+
+	if len(keys) == 0 {
+		return nil
+	}
+	return uc.refresh(ctx, keys, head, uc.finalizedBlock(ctx))
+}
+
+// txCheck is what a check of a tracked transaction found
+type txCheck struct {
+	key          string
+	hash         string
+	from         string
+	nonce        uint64
+	pendingBlock uint64
+	receipt      *entity.Receipt
+	known        bool   // the node knows the transaction
+	nonceUsed    bool   // the sender has mined a transaction with its nonce
+	replacedBy   string // hash of that transaction, when found
+}
+
+// refresh checks tracked transactions at the given head block: mined ones
+// by their receipt, the others by whether the node still knows them and
+// whether their nonce was used by another transaction
+func (uc *trackerUseCase) refresh(ctx context.Context, keys []string, head, finalized uint64) error {
+	uc.mu.Lock()
+	checks := make([]*txCheck, 0, len(keys))
+	for _, key := range keys {
+		if tracked, ok := uc.txs[key]; ok {
+			checks = append(checks, &txCheck{
+				key:          key,
+				hash:         tracked.status.Hash,
+				from:         tracked.status.From,
+				nonce:        tracked.status.Nonce,
+				pendingBlock: tracked.pendingBlock,
+			})
+		}
+	}
+	uc.mu.Unlock()
+
+	hashes := make([]string, len(checks))
+	for i, check := range checks {
+		hashes[i] = check.hash
+	}
+	receipts, err := uc.repo.GetReceipts(ctx, hashes)
+	if err != nil {
+		return err
+	}
+
+	var unmined []*txCheck
+	for i, check := range checks {
+		check.receipt = receipts[i]
+		if check.receipt == nil {
+			unmined = append(unmined, check)
+		}
+	}
+	if err := uc.checkUnmined(ctx, unmined, head); err != nil {
+		return err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	for _, check := range checks {
+		if tracked, ok := uc.txs[check.key]; ok {
+			uc.apply(tracked, check, head, finalized)
+		}
+	}
+	return nil
+}
+
+// checkUnmined checks whether the node still knows transactions without a
+// receipt and, for those it doesn't, whether their nonce was used
+func (uc *trackerUseCase) checkUnmined(ctx context.Context, checks []*txCheck, head uint64) error {
+	if len(checks) == 0 {
+		return nil
+	}
+
+	hashes := make([]string, len(checks))
+	for i, check := range checks {
+		hashes[i] = check.hash
+	}
+	txs, err := uc.repo.GetTransactions(ctx, hashes)
+	if err != nil {
+		return err
+	}
+
+	var unknown []*txCheck
+	for i, check := range checks {
+		check.known = txs[i] != nil
+		if !check.known {
+			unknown = append(unknown, check)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	senders := make([]string, len(unknown))
+	for i, check := range unknown {
+		senders[i] = check.from
+	}
+	nonces, err := uc.repo.GetNonces(ctx, senders)
+	if err != nil {
+		return err
+	}
+
+	for i, check := range unknown {
+		if nonces[i] <= check.nonce {
+			continue
+		}
+		check.nonceUsed = true
+		if check.replacedBy, err = uc.findReplacement(ctx, check, head); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findReplacement searches the blocks mined since a transaction was last
+// seen pending for the transaction that used its nonce. It returns an empty
+// hash when that transaction isn't found.
+func (uc *trackerUseCase) findReplacement(ctx context.Context, check *txCheck, head uint64) (string, error) {
+	from := check.pendingBlock + 1
+	if check.pendingBlock == 0 || head-from+1 > maxReplacementScan {
+		from = head - min(head, maxReplacementScan-1)
+	}
+	if from > head {
+		return "", nil
+	}
+
+	numbers := make([]uint64, 0, head-from+1)
+	for number := from; number <= head; number++ {
+		numbers = append(numbers, number)
+	}
+	blocks, err := uc.repo.GetBlocksByNumber(ctx, numbers)
+	if err != nil {
+		return "", err
+	}
+
+	for _, block := range blocks {
+		if block == nil || len(block.Transactions) == 0 {
+			continue
+		}
+		txs, err := uc.repo.GetTransactions(ctx, block.Transactions)
+		if err != nil {
+			return "", err
+		}
+		for _, tx := range txs {
+			if tx != nil && tx.Nonce == check.nonce && strings.EqualFold(tx.From, check.from) {
+				return tx.Hash, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// apply updates a tracked transaction with the result of a check, notifying
+// its watchers when its status changed. The caller holds the lock.
+func (uc *trackerUseCase) apply(tracked *trackedTx, check *txCheck, head, finalized uint64) {
+	status := tracked.status
+	status.CheckedBlock = head
+	status.BlockNumber, status.BlockHash, status.Confirmations, status.Success = nil, "", 0, nil
+
+	switch {
+	case check.receipt != nil:
+		number := check.receipt.BlockNumber
+		success := check.receipt.Status == 1
+		status.BlockNumber = &number
+		status.BlockHash = check.receipt.BlockHash
+		status.Success = &success
+		status.Confirmations = 1
+		if head > number {
+			status.Confirmations = head - number + 1
+		}
+
+		switch {
+		case finalized >= number:
+			status.State = entity.TxStateFinalized
+		case status.Confirmations >= uc.opts.Confirmations:
+			status.State = entity.TxStateConfirmed
+		default:
+			status.State = entity.TxStateIncluded
+		}
+	case check.known:
+		status.State = entity.TxStatePending
+		tracked.pendingBlock = head
+	case check.nonceUsed && !strings.EqualFold(check.replacedBy, check.hash):
+		status.State = entity.TxStateReplaced
+		status.ReplacedBy = check.replacedBy
+	case check.nonceUsed:
+		// Mined since its receipt was requested; the next check sees it
+		return
+	default:
+		status.State = entity.TxStateDropped
+	}
+
+	changed := status.State != tracked.status.State ||
+		status.BlockHash != tracked.status.BlockHash ||
+		status.Confirmations != tracked.status.Confirmations ||
+		status.ReplacedBy != tracked.status.ReplacedBy
+	if changed {
+		status.UpdatedAt = time.Now()
+	}
+	tracked.status = status
+
+	if changed {
+		for _, ch := range tracked.watchers {
+			current := status
+			// Replace a status the watcher hasn't read yet with the latest one
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- &current
+		}
+	}
+	if status.State.Final() {
+		uc.closeWatchers(tracked)
+	}
+}
+
+// finalizedBlock returns the number of the latest finalized block, or 0 when
+// the node doesn't report one
+func (uc *trackerUseCase) finalizedBlock(ctx context.Context) uint64 {
+	header, err := uc.repo.GetBlockHeader(ctx, entity.BlockSelector{Tag: entity.BlockTagFinalized})
+	if err != nil {
+		return 0
+	}
+	return header.Number
+}
+
+// status returns a copy of the status of a tracked transaction
+func (uc *trackerUseCase) status(key string) (*entity.TransactionStatus, bool) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	tracked, ok := uc.txs[key]
+	if !ok {
+		return nil, false
+	}
+	status := tracked.status
+	return &status, true
+}
+
+// add starts tracking a transaction unless it is already tracked. A client
+// looking up more than MaxPerClient transactions makes room with its oldest
+// finished one. At capacity, the oldest finished transaction makes room or,
+// for transactions broadcast through the API, the oldest one that was looked
+// up, so lookups can't keep broadcasts from being tracked. The caller holds
+// the lock.
+func (uc *trackerUseCase) add(key string, tracked *trackedTx) error {
+	if _, ok := uc.txs[key]; ok {
+		return nil
+	}
+
+	if client := tracked.client; client != "" && uc.perClient[client] >= uc.opts.MaxPerClient {
+		oldest := uc.oldest(func(t *trackedTx) bool { return t.client == client && finished(t) })
+		if oldest == "" {
+			return entity.NewError(entity.ErrCodeRateLimited, "Too many of your transactions are being tracked; try again later", nil)
+		}
+		uc.remove(oldest)
+	}
+
+	if len(uc.txs) >= uc.opts.MaxTracked {
+		oldest := uc.oldest(finished)
+		if oldest == "" && tracked.client == "" {
+			oldest = uc.oldest(func(t *trackedTx) bool { return t.client != "" })
+		}
+		if oldest == "" {
+			return entity.NewError(entity.ErrCodeRateLimited, "Too many transactions are being tracked; try again later", nil)
+		}
+		uc.remove(oldest)
+	}
+
+	uc.txs[key] = tracked
+	if tracked.client != "" {
+		uc.perClient[tracked.client]++
+	}
+	return nil
+}
+
+// oldest returns the key of the least recently updated transaction matching
+// filter, or an empty key when none does. The caller holds the lock.
+func (uc *trackerUseCase) oldest(filter func(*trackedTx) bool) string {
+	oldest := ""
+	for k, t := range uc.txs {
+		if !filter(t) {
+			continue
+		}
+		if oldest == "" || t.status.UpdatedAt.Before(uc.txs[oldest].status.UpdatedAt) {
+			oldest = k
+		}
+	}
+	return oldest
+}
+
+// finished reports whether a transaction was finalized, replaced or dropped
+func finished(tracked *trackedTx) bool {
+	state := tracked.status.State
+	return state.Final() || state == entity.TxStateDropped
+}
+
+// expired reports whether a transaction was finalized, replaced or dropped
+// longer than the retention period ago. The caller holds the lock.
+func (uc *trackerUseCase) expired(tracked *trackedTx) bool {
+	return finished(tracked) && time.Since(tracked.status.UpdatedAt) > uc.opts.Retention
+}
+
+// remove stops tracking a transaction, ending its watchers' streams. The
+// caller holds the lock.
+func (uc *trackerUseCase) remove(key string) {
+	if tracked, ok := uc.txs[key]; ok {
+		uc.closeWatchers(tracked)
+		delete(uc.txs, key)
+
+		if tracked.client != "" {
+			if uc.perClient[tracked.client]--; uc.perClient[tracked.client] == 0 {
+				delete(uc.perClient, tracked.client)
+			}
+		}
+	}
+}
+
+// closeWatchers ends the streams watching a transaction. The caller holds
+// the lock.
+func (uc *trackerUseCase) closeWatchers(tracked *trackedTx) {
+	for _, ch := range tracked.watchers {
+		close(ch)
+	}
+	tracked.watchers = nil
+}
+
+// unwatch ends a stream watching a transaction unless it already ended
+func (uc *trackerUseCase) unwatch(key string, ch chan *entity.TransactionStatus) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	tracked, ok := uc.txs[key]
+	if !ok {
+		return
+	}
+	for i, watcher := range tracked.watchers {
+		if watcher == ch {
+			tracked.watchers = append(tracked.watchers[:i], tracked.watchers[i+1:]...)
+			close(ch)
+			return
+		}
+	}
+}
@@ -0,0 +1,387 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/project-exam/pkg/domain/entity"
+	"github.com/project-exam/pkg/domain/repository"
+)
+
+// TransactionUseCase defines the interface for transaction business rules
+type TransactionUseCase interface {
+	GetTransaction(ctx context.Context, hash string) (*entity.TransactionDetails, error)
+
+	// SendTransaction validates a signed raw transaction and broadcasts it
+	SendTransaction(ctx context.Context, raw []byte) (*entity.SignedTransaction, error)
+
+	// SimulateTransaction executes a transaction on the state of a block
+	// without broadcasting it
+	SimulateTransaction(ctx context.Context, sim *entity.Simulation) (*entity.SimulationResult, error)
+}
+
+// transactionUseCase implements the TransactionUseCase interface
+type transactionUseCase struct {
+	repo         repository.EthereumRepository
+	abiRepo      repository.ABIRepository
+	tracker      TrackerUseCase
+	onTrackError func(tx *entity.SignedTransaction, err error)
+}
+
+// NewTransactionUseCase creates a new TransactionUseCase. Broadcast
+// transactions are tracked with tracker; failures to start tracking them
+// are reported to onTrackError.
+func NewTransactionUseCase(repo repository.EthereumRepository, abiRepo repository.ABIRepository, tracker TrackerUseCase, onTrackError func(tx *entity.SignedTransaction, err error)) TransactionUseCase {
+	return &transactionUseCase{
+		repo:         repo,
+		abiRepo:      abiRepo,
+		tracker:      tracker,
+		onTrackError: onTrackError,
+	}
+}
+
+// GetTransaction returns a transaction with its receipt, decoding its input
+// and logs with the ABI registry
+func (uc *transactionUseCase) GetTransaction(ctx context.Context, hash string) (*entity.TransactionDetails, error) {
+	var tx *entity.Transaction
+	var receipt *entity.Receipt
+	err := runConcurrently(
+		func() error {
+			txs, err := uc.repo.GetTransactions(ctx, []string{hash})
+			if err == nil {
+				tx = txs[0]
+			}
+			return err
+		},
+		func() error {
+			receipts, err := uc.repo.GetReceipts(ctx, []string{hash})
+			if err == nil {
+				receipt = receipts[0]
+			}
+			return err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, entity.NewError(entity.ErrCodeNotFound, "Transaction not found", nil)
+	}
+
+	details := &entity.TransactionDetails{
+		Transaction: tx,
+		Receipt:     receipt,
+	}
+	if details.Call, err = uc.decodeCall(ctx, tx.To, tx.Input); err != nil {
+		return nil, err
+	}
+	if receipt != nil {
+		if details.Events, err = uc.decodeLogs(ctx, receipt.Logs); err != nil {
+			return nil, err
+		}
+	}
+	return details, nil
+}
+
+// decodeCall decodes the input of a call to a contract with the ABI
+// registered for it, falling back to the selector database
+func (uc *transactionUseCase) decodeCall(ctx context.Context, to string, input []byte) (*entity.DecodedCall, error) {
+	// Contract creations carry init code rather than a call
+	if to == "" || len(input) < 4 {
+		return nil, nil
+	}
+
+	contractABI, err := uc.abiRepo.GetABI(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+	if contractABI != nil {
+		call, err := uc.abiRepo.DecodeCall(contractABI.ABI, input)
+		if err != nil || call != nil {
+			return call, err
+		}
+	}
+
+	return uc.abiRepo.DecodeCallBySelector(input), nil
+}
+
+// decodeLogs decodes logs with the ABIs registered for their emitters, in
+// order; nil for logs of contracts without a registered ABI
+func (uc *transactionUseCase) decodeLogs(ctx context.Context, logs []entity.Log) ([]*entity.DecodedEvent, error) {
+	// Decode the logs of each contract together, parsing its ABI once
+	byAddress := make(map[string][]int)
+	for i, log := range logs {
+		address := strings.ToLower(log.Address)
+		byAddress[address] = append(byAddress[address], i)
+	}
+
+	events := make([]*entity.DecodedEvent, len(logs))
+	for address, indexes := range byAddress {
+		contractABI, err := uc.abiRepo.GetABI(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		if contractABI == nil {
+			continue
+		}
+
+		contractLogs := make([]entity.Log, len(indexes))
+		for j, i := range indexes {
+			contractLogs[j] = logs[i]
+		}
+		decoded, err := uc.abiRepo.DecodeLogs(contractABI.ABI, contractLogs)
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range indexes {
+			events[i] = decoded[j]
+		}
+	}
+	return events, nil
+}
+
+// SendTransaction validates a signed raw transaction against the network and
+// the sender's account, then submits it to the node. Checking up front
+// reports mistakes the node's pool would reject or silently hold on to, such
+// as nonce gaps and fee caps below the base fee.
+func (uc *transactionUseCase) SendTransaction(ctx context.Context, raw []byte) (*entity.SignedTransaction, error) {
+	tx, err := uc.repo.DecodeTransaction(raw)
+	if err != nil {
+		return nil, err
+	}
+	if tx.ChainID == nil {
+		return nil, invalidTransaction("transactions must be replay protected with a chain ID (EIP-155)")
+	}
+
+	var chainID, balance *big.Int
+	var nonce, pendingNonce uint64
+	var head *entity.BlockHeader
+	err = runConcurrently(
+		func() (err error) {
+			chainID, err = uc.repo.GetChainID(ctx)
+			return err
+		},
+		func() (err error) {
+			nonce, err = uc.repo.GetNonce(ctx, tx.From)
+			return err
+		},
+		func() (err error) {
+			pendingNonce, err = uc.repo.GetPendingNonce(ctx, tx.From)
+			return err
+		},
+		func() (err error) {
+			head, err = uc.repo.GetLatestHeader(ctx)
+			return err
+		},
+		func() (err error) {
+			balance, err = uc.repo.GetAddressBalance(ctx, tx.From)
+			return err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if tx.ChainID.Cmp(chainID) != 0 {
+		return nil, invalidTransaction("chain ID %s doesn't match the network's chain ID %s", tx.ChainID, chainID)
+	}
+
+	// Nonces between the mined and pending nonce replace a pending transaction
+	if tx.Nonce < nonce {
+		return nil, invalidTransaction("nonce %d was already used; the sender's next nonce is %d", tx.Nonce, pendingNonce)
+	}
+	if tx.Nonce > pendingNonce {
+		return nil, invalidTransaction("nonce %d leaves a gap; the sender's next nonce is %d", tx.Nonce, pendingNonce)
+	}
+
+	feeCap := tx.GasPrice
+	if tx.MaxFeePerGas != nil {
+		feeCap = tx.MaxFeePerGas
+		if tx.MaxPriorityFeePerGas.Cmp(tx.MaxFeePerGas) > 0 {
+			return nil, invalidTransaction("max priority fee of %s wei exceeds the max fee of %s wei", tx.MaxPriorityFeePerGas, tx.MaxFeePerGas)
+		}
+	}
+	if head.BaseFee != nil && feeCap.Cmp(head.BaseFee) < 0 {
+		return nil, invalidTransaction("fee cap of %s wei is below the current base fee of %s wei", feeCap, head.BaseFee)
+	}
+
+	if balance.Cmp(tx.Cost) < 0 {
+		return nil, invalidTransaction("the sender's balance of %s wei doesn't cover the cost of up to %s wei", balance, tx.Cost)
+	}
+
+	if err := uc.repo.SendTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	// The transaction was sent either way; at capacity, clients can still
+	// start tracking it later by hash
+	if err := uc.tracker.TrackSent(tx); err != nil {
+		uc.onTrackError(tx, err)
+	}
+	return tx, nil
+}
+
+// SimulateTransaction executes a transaction on the state of a block,
+// decoding why it reverted and working out how it changes balances
+func (uc *transactionUseCase) SimulateTransaction(ctx context.Context, sim *entity.Simulation) (*entity.SimulationResult, error) {
+	exec, err := uc.repo.SimulateTransaction(ctx, sim)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.SimulationResult{
+		Success:    exec.Error == "",
+		ReturnData: exec.Output,
+		Error:      exec.Error,
+		GasUsed:    exec.GasUsed,
+		Traced:     exec.Traced,
+		Block:      exec.Block,
+	}
+	if exec.Reverted {
+		if result.Revert, err = uc.decodeRevert(ctx, sim, exec); err != nil {
+			return nil, err
+		}
+	}
+	if result.BalanceChanges, err = uc.balanceChanges(ctx, sim, exec); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// decodeRevert decodes the revert data of a simulated transaction. Custom
+// errors are looked up in the ABIs registered for the contracts it called,
+// recipient first, as errors raised by subcalls are often passed on as is.
+func (uc *transactionUseCase) decodeRevert(ctx context.Context, sim *entity.Simulation, exec *entity.SimulationExecution) (*entity.RevertReason, error) {
+	if reason := uc.abiRepo.DecodeRevert(exec.Output); reason != nil {
+		return reason, nil
+	}
+
+	// Traces start with the recipient
+	contracts := exec.Called
+	if !exec.Traced && sim.To != "" {
+		contracts = []string{sim.To}
+	}
+	for _, address := range contracts {
+		contractABI, err := uc.abiRepo.GetABI(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		if contractABI == nil {
+			continue
+		}
+
+		decoded, err := uc.abiRepo.DecodeError(contractABI.ABI, exec.Output)
+		if err != nil {
+			return nil, err
+		}
+		if decoded != nil {
+			return &entity.RevertReason{Kind: entity.RevertKindCustom, Error: decoded}, nil
+		}
+	}
+	return &entity.RevertReason{Kind: entity.RevertKindUnknown}, nil
+}
+
+// balanceChanges works out how a simulated transaction changes balances:
+// by the value it and its subcalls move, and by the fee the sender pays for
+// its gas, of which the fee recipient earns the priority fee. Accounts whose
+// balance ends up unchanged are left out.
+func (uc *transactionUseCase) balanceChanges(ctx context.Context, sim *entity.Simulation, exec *entity.SimulationExecution) ([]entity.BalanceChange, error) {
+	var addresses []string
+	deltas := make(map[string]*big.Int) // by lowercase address
+	add := func(address string, amount *big.Int) {
+		key := strings.ToLower(address)
+		if deltas[key] == nil {
+			deltas[key] = new(big.Int)
+			addresses = append(addresses, address)
+		}
+		deltas[key].Add(deltas[key], amount)
+	}
+
+	for _, transfer := range exec.Transfers {
+		add(transfer.From, new(big.Int).Neg(transfer.Value))
+		add(transfer.To, transfer.Value)
+	}
+
+	if exec.GasUsed != nil {
+		gas := new(big.Int).SetUint64(*exec.GasUsed)
+		if price := effectiveGasPrice(sim, exec.BaseFee); price.Sign() > 0 {
+			add(exec.From, new(big.Int).Neg(new(big.Int).Mul(gas, price)))
+
+			tip := price
+			if exec.BaseFee != nil {
+				tip = new(big.Int).Sub(price, exec.BaseFee)
+			}
+			if tip.Sign() > 0 {
+				add(exec.FeeRecipient, new(big.Int).Mul(gas, tip))
+			}
+		}
+	}
+
+	var changed []string
+	for _, address := range addresses {
+		if deltas[strings.ToLower(address)].Sign() != 0 {
+			changed = append(changed, address)
+		}
+	}
+	if len(changed) == 0 {
+		return []entity.BalanceChange{}, nil
+	}
+
+	balances, err := uc.repo.GetBalancesAtBlock(ctx, changed, entity.BlockSelector{Hash: exec.Block.Hash})
+	if err != nil {
+		return nil, err
+	}
+
+	// The transaction started from the overridden balances
+	overridden := make(map[string]*big.Int)
+	for address, override := range sim.StateOverrides {
+		if override.Balance != nil {
+			overridden[strings.ToLower(address)] = override.Balance
+		}
+	}
+
+	changes := make([]entity.BalanceChange, len(changed))
+	for i, address := range changed {
+		before := balances[i]
+		if balance, ok := overridden[strings.ToLower(address)]; ok {
+			before = balance
+		}
+		delta := deltas[strings.ToLower(address)]
+		changes[i] = entity.BalanceChange{
+			Address: address,
+			Before:  before,
+			After:   new(big.Int).Add(before, delta),
+			Delta:   delta,
+		}
+	}
+	return changes, nil
+}
+
+// effectiveGasPrice returns the gas price a simulated transaction pays, as
+// eth_call charges it: nothing without fee fields, its gas price, or its fee
+// cap bounded by the base fee plus its priority fee. Blocks before London
+// only charge a gas price.
+func effectiveGasPrice(sim *entity.Simulation, baseFee *big.Int) *big.Int {
+	if sim.GasPrice != nil {
+		return sim.GasPrice
+	}
+	if baseFee == nil || sim.MaxFeePerGas == nil {
+		return new(big.Int)
+	}
+
+	price := new(big.Int).Set(baseFee)
+	if sim.MaxPriorityFeePerGas != nil {
+		price.Add(price, sim.MaxPriorityFeePerGas)
+	}
+	if price.Cmp(sim.MaxFeePerGas) > 0 {
+		return sim.MaxFeePerGas
+	}
+	return price
+}
+
+// invalidTransaction is the error of a transaction that fails validation
+func invalidTransaction(format string, args ...interface{}) error {
+	return entity.NewError(entity.ErrCodeInvalidRequest, "Invalid transaction: "+fmt.Sprintf(format, args...), nil)
+}